@@ -0,0 +1,284 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	pc "github.com/paloaltonetworks/prisma-cloud-go"
+	"github.com/paloaltonetworks/prisma-cloud-go/alert/rule"
+	"github.com/paloaltonetworks/prisma-cloud-go/cloud/account/group"
+	"github.com/paloaltonetworks/prisma-cloud-go/policy"
+	"github.com/paloaltonetworks/prisma-cloud-go/user/role"
+	"github.com/terraform-providers/terraform-provider-prismacloud/prismacloud"
+	"golang.org/x/net/context"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+// objectType is a kind of object that can be exported.
+type objectType struct {
+	// Name is the name given to the -types flag and used for the file name.
+	Name string
+
+	// Resource is the Terraform resource the objects are written as.
+	Resource string
+
+	// List returns every object of this type.
+	List func(c *pc.Client) ([]object, error)
+}
+
+// object is an object returned by objectType.List.
+type object struct {
+	Id            string
+	Name          string
+	SystemDefault bool
+}
+
+var objectTypes = []objectType{
+	{"policy", "prismacloud_policy", listPolicies},
+	{"alert_rule", "prismacloud_alert_rule", listAlertRules},
+	{"account_group", "prismacloud_account_group", listAccountGroups},
+	{"user_role", "prismacloud_user_role", listUserRoles},
+}
+
+func objectTypeNames() []string {
+	ans := make([]string, 0, len(objectTypes))
+	for _, t := range objectTypes {
+		ans = append(ans, t.Name)
+	}
+	return ans
+}
+
+func findObjectType(name string) (objectType, bool) {
+	for _, t := range objectTypes {
+		if t.Name == name {
+			return t, true
+		}
+	}
+	return objectType{}, false
+}
+
+func listPolicies(c *pc.Client) ([]object, error) {
+	list, err := policy.List(c, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	ans := make([]object, 0, len(list))
+	for _, o := range list {
+		ans = append(ans, object{Id: o.PolicyId, Name: o.Name, SystemDefault: o.SystemDefault})
+	}
+	return ans, nil
+}
+
+// listAlertRules returns the alert rules.  Alert rules that cannot be edited
+// are the ones Prisma Cloud creates, so they count as system defaults.
+func listAlertRules(c *pc.Client) ([]object, error) {
+	list, err := rule.List(c)
+	if err != nil {
+		return nil, err
+	}
+
+	ans := make([]object, 0, len(list))
+	for _, o := range list {
+		if o.Deleted {
+			continue
+		}
+		ans = append(ans, object{Id: o.PolicyScanConfigId, Name: o.Name, SystemDefault: o.ReadOnly})
+	}
+	return ans, nil
+}
+
+func listAccountGroups(c *pc.Client) ([]object, error) {
+	list, err := group.List(c)
+	if err != nil {
+		return nil, err
+	}
+
+	ans := make([]object, 0, len(list))
+	for _, o := range list {
+		ans = append(ans, object{Id: o.Id, Name: o.Name})
+	}
+	return ans, nil
+}
+
+func listUserRoles(c *pc.Client) ([]object, error) {
+	list, err := role.List(c)
+	if err != nil {
+		return nil, err
+	}
+
+	ans := make([]object, 0, len(list))
+	for _, o := range list {
+		ans = append(ans, object{Id: o.Id, Name: o.Name})
+	}
+	return ans, nil
+}
+
+// exportOptions select the objects to export.
+type exportOptions struct {
+	Types                []objectType
+	Name                 *regexp.Regexp
+	IncludeSystemDefault bool
+}
+
+// exportedObject is an object as read by its Terraform resource.
+type exportedObject struct {
+	Type   objectType
+	Label  string
+	Id     string
+	Schema map[string]*schema.Schema
+	Data   *schema.ResourceData
+}
+
+// client is a configured provider along with its client.
+type client struct {
+	provider *schema.Provider
+	meta     interface{}
+}
+
+// configureClient configures the provider with the given params.  Params
+// not given are taken from the environment, as they are in Terraform.
+func configureClient(ctx context.Context, config map[string]interface{}) (*client, error) {
+	if _, ok := config["logging"]; !ok {
+		config["logging"] = map[string]interface{}{pc.LogQuiet: true}
+	}
+
+	p := prismacloud.Provider()
+	if diags := p.Configure(ctx, terraform.NewResourceConfigRaw(config)); diags.HasError() {
+		return nil, diagError(diags)
+	}
+
+	return &client{provider: p, meta: p.Meta()}, nil
+}
+
+// export reads the objects selected by opts using the provider resources.
+func export(ctx context.Context, c *client, opts exportOptions) ([]exportedObject, error) {
+	var ans []exportedObject
+
+	for _, t := range opts.Types {
+		r := c.provider.ResourcesMap[t.Resource]
+		labels := make(map[string]bool)
+
+		list, err := t.List(c.meta.(*pc.Client))
+		if err != nil {
+			return nil, fmt.Errorf("listing %s: %s", t.Name, err)
+		}
+		sort.SliceStable(list, func(i, j int) bool {
+			return list[i].Name < list[j].Name
+		})
+
+		for _, o := range list {
+			if o.SystemDefault && !opts.IncludeSystemDefault {
+				continue
+			}
+			if opts.Name != nil && !opts.Name.MatchString(o.Name) {
+				continue
+			}
+
+			d := r.Data(nil)
+			d.SetId(o.Id)
+			if diags := r.ReadContext(ctx, d, c.meta); diags.HasError() {
+				return nil, fmt.Errorf("reading %s %q: %s", t.Name, o.Name, diagError(diags))
+			}
+			if d.Id() == "" {
+				// Deleted since it was listed.
+				continue
+			}
+
+			ans = append(ans, exportedObject{
+				Type:   t,
+				Label:  resourceLabel(t.Name, o.Name, labels),
+				Id:     d.Id(),
+				Schema: r.Schema,
+				Data:   d,
+			})
+		}
+	}
+
+	return ans, nil
+}
+
+var labelInvalidChars = regexp.MustCompile(`[^a-z0-9_]+`)
+
+// resourceLabel returns a unique resource name made from the object name.
+func resourceLabel(typeName, name string, used map[string]bool) string {
+	label := strings.Trim(labelInvalidChars.ReplaceAllString(strings.ToLower(name), "_"), "_")
+	if label == "" || (label[0] >= '0' && label[0] <= '9') {
+		label = typeName + "_" + label
+		label = strings.TrimSuffix(label, "_")
+	}
+
+	ans := label
+	for i := 2; used[ans]; i++ {
+		ans = label + "_" + strconv.Itoa(i)
+	}
+	used[ans] = true
+
+	return ans
+}
+
+// writeFiles writes a "<type>.tf" file for each type that has objects, and
+// an "imports.tf" file with the import blocks for all of them.
+func writeFiles(dir string, objs []exportedObject) error {
+	files := make(map[string]*bytes.Buffer)
+	var names []string
+	var imports bytes.Buffer
+
+	for _, o := range objs {
+		fn := o.Type.Name + ".tf"
+		buf, ok := files[fn]
+		if !ok {
+			buf = &bytes.Buffer{}
+			files[fn] = buf
+			names = append(names, fn)
+		} else {
+			buf.WriteString("\n")
+		}
+
+		writeResource(buf, o.Type.Resource, o.Label, o.Schema, o.Data)
+
+		if imports.Len() > 0 {
+			imports.WriteString("\n")
+		}
+		writeImport(&imports, o.Type.Resource, o.Label, o.Id)
+	}
+
+	if len(objs) > 0 {
+		files["imports.tf"] = &imports
+		names = append(names, "imports.tf")
+	}
+
+	for _, fn := range names {
+		if err := ioutil.WriteFile(filepath.Join(dir, fn), files[fn].Bytes(), 0644); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// diagError returns the error diagnostics as a single error.
+func diagError(diags diag.Diagnostics) error {
+	var msgs []string
+	for _, d := range diags {
+		if d.Severity != diag.Error {
+			continue
+		}
+		msg := d.Summary
+		if d.Detail != "" {
+			msg += ": " + d.Detail
+		}
+		msgs = append(msgs, msg)
+	}
+
+	return fmt.Errorf("%s", strings.Join(msgs, "; "))
+}
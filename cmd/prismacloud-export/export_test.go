@@ -0,0 +1,197 @@
+package main
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/paloaltonetworks/prisma-cloud-go/alert/rule"
+	"github.com/paloaltonetworks/prisma-cloud-go/cloud/account/group"
+	"github.com/paloaltonetworks/prisma-cloud-go/policy"
+	"github.com/paloaltonetworks/prisma-cloud-go/user/role"
+	"github.com/terraform-providers/terraform-provider-prismacloud/internal/fakeapi"
+	"golang.org/x/net/context"
+)
+
+func testClient(t *testing.T) (*fakeapi.Server, *client) {
+	t.Helper()
+
+	s := fakeapi.New()
+	s.Username = "fake-access-key"
+	s.Password = "fake-secret-key"
+	t.Cleanup(s.Close)
+
+	c, err := configureClient(context.Background(), map[string]interface{}{
+		"url":      s.Host(),
+		"port":     s.Port(),
+		"protocol": "http",
+		"username": s.Username,
+		"password": s.Password,
+	})
+	if err != nil {
+		t.Fatalf("Error configuring the provider: %s", err)
+	}
+
+	return s, c
+}
+
+func testPolicy(name string, systemDefault bool) policy.Policy {
+	return policy.Policy{
+		Name:          name,
+		PolicyType:    policy.PolicyTypeConfig,
+		CloudType:     "aws",
+		Severity:      policy.SeverityHigh,
+		Description:   "Uses ${var} and\n\"quotes\"",
+		Labels:        []string{"prod", "ci"},
+		Enabled:       true,
+		SystemDefault: systemDefault,
+		Rule: policy.Rule{
+			Name:     name,
+			Type:     policy.RuleTypeConfig,
+			Criteria: "config from cloud.resource where api.name = 'aws-ec2-describe-instances'",
+			Parameters: map[string]string{
+				"savedSearch": "false",
+				"withIac":     "false",
+			},
+		},
+	}
+}
+
+func testExport(t *testing.T, c *client, opts exportOptions) map[string]string {
+	t.Helper()
+
+	objs, err := export(context.Background(), c, opts)
+	if err != nil {
+		t.Fatalf("Error exporting: %s", err)
+	}
+
+	dir := t.TempDir()
+	if err = writeFiles(dir, objs); err != nil {
+		t.Fatalf("Error writing files: %s", err)
+	}
+
+	files := make(map[string]string)
+	list, _ := ioutil.ReadDir(dir)
+	for _, fi := range list {
+		b, err := ioutil.ReadFile(filepath.Join(dir, fi.Name()))
+		if err != nil {
+			t.Fatalf("Error reading %s: %s", fi.Name(), err)
+		}
+		files[fi.Name()] = string(b)
+	}
+
+	return files
+}
+
+func TestExportFakeApi(t *testing.T) {
+	s, c := testClient(t)
+
+	custom := s.Policies.Put(testPolicy("Custom EC2 policy", false))
+	s.Policies.Put(testPolicy("System default policy", true))
+	s.AlertRules.Put(rule.Rule{Name: "Prod alerts", Enabled: true, Policies: []string{custom}})
+	s.AccountGroups.Put(group.Group{Name: "Prod accounts", Description: "All prod accounts", AccountIds: []string{"111111111111"}})
+	s.UserRoles.Put(role.Role{Name: "Read only", RoleType: "Account Group Read Only"})
+
+	files := testExport(t, c, exportOptions{Types: objectTypes})
+
+	for _, fn := range []string{"policy.tf", "alert_rule.tf", "account_group.tf", "user_role.tf", "imports.tf"} {
+		if _, ok := files[fn]; !ok {
+			t.Errorf("%s was not written", fn)
+		}
+	}
+
+	pf := files["policy.tf"]
+	for _, want := range []string{
+		`resource "prismacloud_policy" "custom_ec2_policy" {`,
+		`  name        = "Custom EC2 policy"`,
+		`  description = "Uses $${var} and\n\"quotes\""`,
+		`  labels      = ["ci", "prod"]`,
+		`    criteria   = "config from cloud.resource where api.name = 'aws-ec2-describe-instances'"`,
+	} {
+		if !strings.Contains(pf, want) {
+			t.Errorf("policy.tf is missing %q:\n%s", want, pf)
+		}
+	}
+	if !strings.Contains(pf, "    }\n    rule_type = \"Config\"\n") {
+		t.Errorf("Attributes after a multi-line value are aligned with it:\n%s", pf)
+	}
+	if strings.Contains(pf, "System default policy") {
+		t.Errorf("System default policy was exported:\n%s", pf)
+	}
+	if strings.Contains(pf, "policy_id") {
+		t.Errorf("Computed only param was exported:\n%s", pf)
+	}
+
+	want := "import {\n  to = prismacloud_policy.custom_ec2_policy\n  id = \"" + custom + "\"\n}\n"
+	if !strings.Contains(files["imports.tf"], want) {
+		t.Errorf("imports.tf is missing %q:\n%s", want, files["imports.tf"])
+	}
+	if n := strings.Count(files["imports.tf"], "import {"); n != 4 {
+		t.Errorf("imports.tf has %d import blocks, expected 4", n)
+	}
+}
+
+func TestExportFilters(t *testing.T) {
+	s, c := testClient(t)
+
+	s.Policies.Put(testPolicy("Custom EC2 policy", false))
+	s.Policies.Put(testPolicy("Custom S3 policy", false))
+	s.Policies.Put(testPolicy("System default policy", true))
+	s.AccountGroups.Put(group.Group{Name: "Prod accounts"})
+
+	policyType, _ := findObjectType("policy")
+	files := testExport(t, c, exportOptions{
+		Types:                []objectType{policyType},
+		Name:                 regexp.MustCompile("(?i)ec2|default"),
+		IncludeSystemDefault: true,
+	})
+
+	if _, ok := files["account_group.tf"]; ok {
+		t.Errorf("account_group.tf was written")
+	}
+	pf := files["policy.tf"]
+	if !strings.Contains(pf, "Custom EC2 policy") || !strings.Contains(pf, "System default policy") {
+		t.Errorf("policy.tf is missing matching policies:\n%s", pf)
+	}
+	if strings.Contains(pf, "Custom S3 policy") {
+		t.Errorf("policy.tf has a policy that does not match:\n%s", pf)
+	}
+}
+
+func TestResourceLabel(t *testing.T) {
+	used := make(map[string]bool)
+
+	for _, tc := range []struct {
+		name string
+		want string
+	}{
+		{"Prod Accounts", "prod_accounts"},
+		{"prod-accounts", "prod_accounts_2"},
+		{"AWS: S3 (public)", "aws_s3_public"},
+		{"1st group", "account_group_1st_group"},
+		{"!!!", "account_group"},
+	} {
+		if got := resourceLabel("account_group", tc.name, used); got != tc.want {
+			t.Errorf("resourceLabel(%q) = %q, expected %q", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestHclString(t *testing.T) {
+	for _, tc := range []struct {
+		in   string
+		want string
+	}{
+		{"plain", `"plain"`},
+		{`say "hi"`, `"say \"hi\""`},
+		{"${var.x} and %{if}", `"$${var.x} and %%{if}"`},
+		{"100% $5", `"100% $5"`},
+		{"a\nb\\c", `"a\nb\\c"`},
+	} {
+		if got := hclString(tc.in); got != tc.want {
+			t.Errorf("hclString(%q) = %s, expected %s", tc.in, got, tc.want)
+		}
+	}
+}
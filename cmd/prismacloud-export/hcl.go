@@ -0,0 +1,225 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+const hclIndent = "  "
+
+// writeResource writes a resource block holding the configurable params of
+// the given resource data.
+func writeResource(buf *bytes.Buffer, resourceType, label string, sm map[string]*schema.Schema, d *schema.ResourceData) {
+	values := make(map[string]interface{}, len(sm))
+	for key := range sm {
+		values[key] = d.Get(key)
+	}
+
+	fmt.Fprintf(buf, "resource %s %s {\n", hclString(resourceType), hclString(label))
+	writeBody(buf, hclIndent, sm, values)
+	buf.WriteString("}\n")
+}
+
+// writeImport writes an import block for the given resource.
+func writeImport(buf *bytes.Buffer, resourceType, label, id string) {
+	fmt.Fprintf(buf, "import {\n%sto = %s.%s\n%sid = %s\n}\n", hclIndent, resourceType, label, hclIndent, hclString(id))
+}
+
+// writeBody writes the attributes and then the nested blocks of a block.
+//
+// Computed only params are left out, as are params that are not set or are
+// set to their default, unless they are required.
+func writeBody(buf *bytes.Buffer, indent string, sm map[string]*schema.Schema, values map[string]interface{}) {
+	keys := make([]string, 0, len(sm))
+	for key, s := range sm {
+		if !s.Required && !s.Optional {
+			continue
+		}
+		if s.Deprecated != "" || (!s.Required && isDefault(s, values[key])) {
+			continue
+		}
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	// Attributes, with the equal signs aligned the way terraform fmt does.
+	var attrs [][2]string
+	for _, key := range keys {
+		if _, ok := sm[key].Elem.(*schema.Resource); ok {
+			continue
+		}
+		attrs = append(attrs, [2]string{key, hclValue(indent, sm[key], values[key])})
+	}
+	// A multi-line value ends the run of attributes aligned together.
+	for start := 0; start < len(attrs); {
+		end, width := start, 0
+		for end < len(attrs) {
+			if len(attrs[end][0]) > width {
+				width = len(attrs[end][0])
+			}
+			end++
+			if strings.Contains(attrs[end-1][1], "\n") {
+				break
+			}
+		}
+		for _, a := range attrs[start:end] {
+			fmt.Fprintf(buf, "%s%-*s = %s\n", indent, width, a[0], a[1])
+		}
+		start = end
+	}
+
+	// Nested blocks.
+	for _, key := range keys {
+		r, ok := sm[key].Elem.(*schema.Resource)
+		if !ok {
+			continue
+		}
+		for _, elm := range listOf(values[key]) {
+			m, _ := elm.(map[string]interface{})
+			buf.WriteString("\n")
+			fmt.Fprintf(buf, "%s%s {\n", indent, key)
+			writeBody(buf, indent+hclIndent, r.Schema, m)
+			fmt.Fprintf(buf, "%s}\n", indent)
+		}
+	}
+}
+
+// hclValue returns the HCL expression for an attribute value.
+func hclValue(indent string, s *schema.Schema, v interface{}) string {
+	switch s.Type {
+	case schema.TypeList, schema.TypeSet:
+		list := listOf(v)
+		items := make([]string, 0, len(list))
+		for _, x := range list {
+			items = append(items, hclPrimitive(x))
+		}
+		if s.Type == schema.TypeSet {
+			sort.Strings(items)
+		}
+		return "[" + strings.Join(items, ", ") + "]"
+	case schema.TypeMap:
+		m, _ := v.(map[string]interface{})
+		keys := make([]string, 0, len(m))
+		width := 0
+		for key := range m {
+			keys = append(keys, key)
+			if len(hclKey(key)) > width {
+				width = len(hclKey(key))
+			}
+		}
+		sort.Strings(keys)
+
+		var b strings.Builder
+		b.WriteString("{\n")
+		for _, key := range keys {
+			fmt.Fprintf(&b, "%s%s%-*s = %s\n", indent, hclIndent, width, hclKey(key), hclPrimitive(m[key]))
+		}
+		b.WriteString(indent + "}")
+		return b.String()
+	}
+
+	return hclPrimitive(v)
+}
+
+func hclPrimitive(v interface{}) string {
+	switch x := v.(type) {
+	case string:
+		return hclString(x)
+	case bool:
+		return strconv.FormatBool(x)
+	case int:
+		return strconv.Itoa(x)
+	case float64:
+		return strconv.FormatFloat(x, 'f', -1, 64)
+	case nil:
+		return "null"
+	}
+
+	return hclString(fmt.Sprint(v))
+}
+
+// hclString returns s as a quoted HCL string, escaping template sequences.
+func hclString(s string) string {
+	var b strings.Builder
+
+	b.WriteByte('"')
+	for i, r := range s {
+		switch r {
+		case '"':
+			b.WriteString(`\"`)
+		case '\\':
+			b.WriteString(`\\`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+			b.WriteString(`\r`)
+		case '\t':
+			b.WriteString(`\t`)
+		case '$', '%':
+			b.WriteRune(r)
+			if i+1 < len(s) && s[i+1] == '{' {
+				b.WriteRune(r)
+			}
+		default:
+			if r < 0x20 {
+				fmt.Fprintf(&b, `\u%04x`, r)
+			} else {
+				b.WriteRune(r)
+			}
+		}
+	}
+	b.WriteByte('"')
+
+	return b.String()
+}
+
+var hclIdentifier = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_-]*$`)
+
+func hclKey(key string) string {
+	if hclIdentifier.MatchString(key) {
+		return key
+	}
+	return hclString(key)
+}
+
+func listOf(v interface{}) []interface{} {
+	switch x := v.(type) {
+	case []interface{}:
+		return x
+	case *schema.Set:
+		return x.List()
+	}
+	return nil
+}
+
+// isDefault returns true if v is the default value of the param, or the zero
+// value if the param has no default.
+func isDefault(s *schema.Schema, v interface{}) bool {
+	if s.Default != nil {
+		return reflect.DeepEqual(s.Default, v)
+	}
+
+	switch x := v.(type) {
+	case nil:
+		return true
+	case string:
+		return x == ""
+	case bool:
+		return !x
+	case int:
+		return x == 0
+	case float64:
+		return x == 0
+	case map[string]interface{}:
+		return len(x) == 0
+	}
+
+	return len(listOf(v)) == 0
+}
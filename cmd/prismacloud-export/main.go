@@ -0,0 +1,119 @@
+/*
+Command prismacloud-export writes the objects of an existing Prisma Cloud
+tenant as Terraform configuration.
+
+For each exported object type, a "<type>.tf" file is written with one
+resource block per object, along with an "imports.tf" file holding the
+matching import blocks, so that "terraform plan" on the output directory
+imports the objects instead of creating them.  Import blocks require
+Terraform 1.5 or later.
+
+The provider is configured the same way it is in Terraform, so the usual
+PRISMACLOUD_* environment variables apply, along with the following flags:
+
+	-config file      JSON config file (json_config_file)
+	-profile name     profile in the JSON config file
+	-types list       comma separated object types to export
+	-name regexp      only export objects whose name matches
+	-system-default   also export system default objects
+	-out dir          directory the files are written to
+
+The objects are read with the provider's own resources, so the output has the
+same values that Terraform would have in state after the import.
+*/
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"regexp"
+	"strings"
+
+	"golang.org/x/net/context"
+)
+
+func main() {
+	var (
+		configFile    string
+		profile       string
+		types         string
+		name          string
+		systemDefault bool
+		out           string
+	)
+
+	flag.StringVar(&configFile, "config", "", "JSON config file")
+	flag.StringVar(&profile, "profile", "", "Profile in the JSON config file")
+	flag.StringVar(&types, "types", strings.Join(objectTypeNames(), ","), "Comma separated object types to export")
+	flag.StringVar(&name, "name", "", "Only export objects whose name matches this regular expression")
+	flag.BoolVar(&systemDefault, "system-default", false, "Also export system default objects")
+	flag.StringVar(&out, "out", ".", "Directory to write the files to")
+	flag.Parse()
+
+	// The API client logs every request, which is only useful when
+	// debugging, the same as it is in Terraform.
+	if os.Getenv("TF_LOG") == "" {
+		log.SetOutput(ioutil.Discard)
+	}
+
+	opts := exportOptions{
+		IncludeSystemDefault: systemDefault,
+	}
+
+	for _, tn := range strings.Split(types, ",") {
+		t, ok := findObjectType(strings.TrimSpace(tn))
+		if !ok {
+			fatalf("Unknown object type %q, valid types are: %s", tn, strings.Join(objectTypeNames(), ", "))
+		}
+		opts.Types = append(opts.Types, t)
+	}
+
+	if name != "" {
+		re, err := regexp.Compile(name)
+		if err != nil {
+			fatalf("Invalid name regular expression: %s", err)
+		}
+		opts.Name = re
+	}
+
+	config := map[string]interface{}{}
+	if configFile != "" {
+		config["json_config_file"] = configFile
+	}
+	if profile != "" {
+		config["profile"] = profile
+	}
+
+	ctx := context.Background()
+	client, err := configureClient(ctx, config)
+	if err != nil {
+		fatalf("Error configuring the provider: %s", err)
+	}
+
+	objs, err := export(ctx, client, opts)
+	if err != nil {
+		fatalf("Error exporting: %s", err)
+	}
+
+	if err = writeFiles(out, objs); err != nil {
+		fatalf("Error writing files: %s", err)
+	}
+
+	for _, t := range opts.Types {
+		var n int
+		for _, o := range objs {
+			if o.Type.Name == t.Name {
+				n++
+			}
+		}
+		fmt.Fprintf(os.Stderr, "Exported %d %s objects\n", n, t.Name)
+	}
+}
+
+func fatalf(format string, a ...interface{}) {
+	fmt.Fprintf(os.Stderr, format+"\n", a...)
+	os.Exit(1)
+}
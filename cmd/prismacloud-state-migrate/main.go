@@ -0,0 +1,226 @@
+/*
+Command prismacloud-state-migrate moves cloud accounts in a Terraform state
+file from the v1 cloud account resources to the v2 ones:
+
+	prismacloud_cloud_account     -> prismacloud_cloud_account_v2
+	prismacloud_org_cloud_account -> prismacloud_org_cloud_account_v2
+
+The state is read from the file given as the argument, or from stdin, and the
+migrated state is written to stdout:
+
+	terraform state pull > old.tfstate
+	prismacloud-state-migrate old.tfstate > new.tfstate
+	terraform state push new.tfstate
+
+Only the resource type changes, the resource names stay the same.  Each moved
+instance is set to schema version 0, which makes the provider convert the v1
+params to the v2 layout on the next plan without contacting Prisma Cloud.  By
+default every v1 account is moved, use -address to only move some of them.
+*/
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+)
+
+// resourceMoves maps v1 resource types to the v2 type they are moved to.
+var resourceMoves = map[string]string{
+	"prismacloud_cloud_account":     "prismacloud_cloud_account_v2",
+	"prismacloud_org_cloud_account": "prismacloud_org_cloud_account_v2",
+}
+
+// attributeRenames maps v1 params to their v2 names, so that the paths of
+// sensitive values in the state follow the rename done by the provider.
+var attributeRenames = map[string]string{
+	"credentials_json": "credentials",
+}
+
+func main() {
+	var addresses string
+
+	flag.StringVar(&addresses, "address", "", "Comma separated addresses of the resources to move, such as prismacloud_cloud_account.prod")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [-address list] [state file]\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	var in io.Reader = os.Stdin
+	if flag.NArg() > 1 {
+		flag.Usage()
+		os.Exit(2)
+	} else if flag.NArg() == 1 {
+		f, err := os.Open(flag.Arg(0))
+		if err != nil {
+			fatalf("%s", err)
+		}
+		defer f.Close()
+		in = f
+	}
+
+	b, err := ioutil.ReadAll(in)
+	if err != nil {
+		fatalf("Error reading state: %s", err)
+	}
+
+	var only []string
+	if addresses != "" {
+		for _, a := range strings.Split(addresses, ",") {
+			only = append(only, strings.TrimSpace(a))
+		}
+	}
+
+	out, moved, err := migrateState(b, only)
+	if err != nil {
+		fatalf("Error migrating state: %s", err)
+	}
+
+	os.Stdout.Write(out)
+	for _, m := range moved {
+		fmt.Fprintf(os.Stderr, "Moved %s\n", m)
+	}
+}
+
+/*
+migrateState moves the v1 cloud accounts in a version 4 state file to the v2
+resources, returning the new state along with the moves done.
+
+If only is not empty, only the resources with these addresses are moved.
+*/
+func migrateState(b []byte, only []string) ([]byte, []string, error) {
+	var state map[string]interface{}
+
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	if err := dec.Decode(&state); err != nil {
+		return nil, nil, err
+	}
+	if v, _ := state["version"].(json.Number); v.String() != "4" {
+		return nil, nil, fmt.Errorf("unsupported state version %q, expected 4", v)
+	}
+
+	resources, _ := state["resources"].([]interface{})
+	addrs := make(map[string]bool, len(resources))
+	for _, v := range resources {
+		if r, ok := v.(map[string]interface{}); ok {
+			addrs[resourceAddress(r, "")] = true
+		}
+	}
+
+	wanted := make(map[string]bool, len(only))
+	for _, a := range only {
+		wanted[a] = true
+	}
+
+	var moved []string
+	for _, v := range resources {
+		r, ok := v.(map[string]interface{})
+		if !ok || r["mode"] != "managed" {
+			continue
+		}
+		t, _ := r["type"].(string)
+		to, ok := resourceMoves[t]
+		if !ok {
+			continue
+		}
+		from := resourceAddress(r, "")
+		if len(wanted) > 0 && !wanted[from] {
+			continue
+		}
+		delete(wanted, from)
+
+		dst := resourceAddress(r, to)
+		if addrs[dst] {
+			return nil, nil, fmt.Errorf("cannot move %s, %s is already in the state", from, dst)
+		}
+
+		r["type"] = to
+		instances, _ := r["instances"].([]interface{})
+		for _, iv := range instances {
+			inst, ok := iv.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			inst["schema_version"] = json.Number("0")
+			renameSensitivePaths(inst)
+		}
+		moved = append(moved, fmt.Sprintf("%s to %s", from, dst))
+	}
+
+	if len(wanted) > 0 {
+		missing := make([]string, 0, len(wanted))
+		for _, a := range only {
+			if wanted[a] {
+				missing = append(missing, a)
+			}
+		}
+		return nil, nil, fmt.Errorf("no v1 cloud account found at %s", strings.Join(missing, ", "))
+	}
+
+	if len(moved) > 0 {
+		if n, err := serialOf(state); err == nil {
+			state["serial"] = json.Number(fmt.Sprint(n + 1))
+		}
+	}
+
+	out, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return append(out, '\n'), moved, nil
+}
+
+func serialOf(state map[string]interface{}) (int64, error) {
+	n, _ := state["serial"].(json.Number)
+	return n.Int64()
+}
+
+// resourceAddress returns the address of a resource in the state, using the
+// given type instead of its own if not empty.
+func resourceAddress(r map[string]interface{}, t string) string {
+	if t == "" {
+		t, _ = r["type"].(string)
+	}
+	name, _ := r["name"].(string)
+
+	addr := t + "." + name
+	if r["mode"] == "data" {
+		addr = "data." + addr
+	}
+	if m, _ := r["module"].(string); m != "" {
+		addr = m + "." + addr
+	}
+
+	return addr
+}
+
+// renameSensitivePaths renames the v1 params in the paths of the sensitive
+// values of a resource instance.
+func renameSensitivePaths(inst map[string]interface{}) {
+	paths, _ := inst["sensitive_attributes"].([]interface{})
+	for _, p := range paths {
+		steps, _ := p.([]interface{})
+		for _, s := range steps {
+			step, ok := s.(map[string]interface{})
+			if !ok || step["type"] != "get_attr" {
+				continue
+			}
+			if name, _ := step["value"].(string); attributeRenames[name] != "" {
+				step["value"] = attributeRenames[name]
+			}
+		}
+	}
+}
+
+func fatalf(format string, a ...interface{}) {
+	fmt.Fprintf(os.Stderr, format+"\n", a...)
+	os.Exit(1)
+}
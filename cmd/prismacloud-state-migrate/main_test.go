@@ -0,0 +1,145 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+const testState = `{
+  "version": 4,
+  "terraform_version": "1.5.7",
+  "serial": 7,
+  "lineage": "00000000-0000-0000-0000-000000000000",
+  "outputs": {},
+  "resources": [
+    {
+      "mode": "managed",
+      "type": "prismacloud_cloud_account",
+      "name": "gcp",
+      "provider": "provider[\"registry.terraform.io/paloaltonetworks/prismacloud\"]",
+      "instances": [
+        {
+          "schema_version": 0,
+          "attributes": {
+            "id": "gcp:my-project",
+            "disable_on_destroy": false,
+            "gcp": [{"account_id": "my-project", "credentials_json": "{}", "group_ids": ["group-1"]}]
+          },
+          "sensitive_attributes": [[{"type": "get_attr", "value": "gcp"}, {"type": "index", "value": {"value": 0, "type": "number"}}, {"type": "get_attr", "value": "credentials_json"}]]
+        }
+      ]
+    },
+    {
+      "module": "module.org",
+      "mode": "managed",
+      "type": "prismacloud_org_cloud_account",
+      "name": "aws",
+      "provider": "provider[\"registry.terraform.io/paloaltonetworks/prismacloud\"]",
+      "instances": [{"schema_version": 0, "attributes": {"id": "aws:123456789012"}, "sensitive_attributes": []}]
+    },
+    {
+      "mode": "managed",
+      "type": "prismacloud_account_group",
+      "name": "prod",
+      "provider": "provider[\"registry.terraform.io/paloaltonetworks/prismacloud\"]",
+      "instances": [{"schema_version": 0, "attributes": {"id": "group-1"}, "sensitive_attributes": []}]
+    }
+  ]
+}
+`
+
+type testStateFile struct {
+	Serial    int `json:"serial"`
+	Resources []struct {
+		Module    string `json:"module"`
+		Type      string `json:"type"`
+		Name      string `json:"name"`
+		Instances []struct {
+			SchemaVersion       int               `json:"schema_version"`
+			Attributes          json.RawMessage   `json:"attributes"`
+			SensitiveAttributes []json.RawMessage `json:"sensitive_attributes"`
+		} `json:"instances"`
+	} `json:"resources"`
+}
+
+func compactJSON(t *testing.T, b []byte) string {
+	t.Helper()
+
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, b); err != nil {
+		t.Fatalf("Error compacting %s: %s", b, err)
+	}
+	return buf.String()
+}
+
+func TestMigrateState(t *testing.T) {
+	b, moved, err := migrateState([]byte(testState), nil)
+	if err != nil {
+		t.Fatalf("Error migrating state: %s", err)
+	}
+
+	if len(moved) != 2 {
+		t.Errorf("Moved %d resources, expected 2: %v", len(moved), moved)
+	}
+
+	var state testStateFile
+	if err = json.Unmarshal(b, &state); err != nil {
+		t.Fatalf("Error parsing migrated state: %s", err)
+	}
+	if state.Serial != 8 {
+		t.Errorf("Serial is %d, expected 8", state.Serial)
+	}
+
+	for i, want := range []string{"prismacloud_cloud_account_v2", "prismacloud_org_cloud_account_v2", "prismacloud_account_group"} {
+		if got := state.Resources[i].Type; got != want {
+			t.Errorf("Resource %d has type %q, expected %q", i, got, want)
+		}
+	}
+	inst := state.Resources[0].Instances[0]
+	if inst.SchemaVersion != 0 {
+		t.Errorf("Schema version is %d, expected 0", inst.SchemaVersion)
+	}
+	if s := compactJSON(t, inst.Attributes); !strings.Contains(s, `"credentials_json":"{}"`) {
+		t.Errorf("Attributes changed: %s", s)
+	}
+	if s := compactJSON(t, inst.SensitiveAttributes[0]); !strings.Contains(s, `"value":"credentials"}`) {
+		t.Errorf("Sensitive path was not renamed: %s", s)
+	}
+}
+
+func TestMigrateStateAddress(t *testing.T) {
+	b, moved, err := migrateState([]byte(testState), []string{"module.org.prismacloud_org_cloud_account.aws"})
+	if err != nil {
+		t.Fatalf("Error migrating state: %s", err)
+	}
+	if len(moved) != 1 {
+		t.Errorf("Moved %d resources, expected 1: %v", len(moved), moved)
+	}
+
+	var state testStateFile
+	if err = json.Unmarshal(b, &state); err != nil {
+		t.Fatalf("Error parsing migrated state: %s", err)
+	}
+	if got := state.Resources[0].Type; got != "prismacloud_cloud_account" {
+		t.Errorf("Resource not given was moved to %q", got)
+	}
+	if got := state.Resources[1].Type; got != "prismacloud_org_cloud_account_v2" {
+		t.Errorf("Resource given was not moved, type is %q", got)
+	}
+
+	if _, _, err = migrateState([]byte(testState), []string{"prismacloud_cloud_account.missing"}); err == nil {
+		t.Errorf("Expected an error for an address not in the state")
+	}
+}
+
+func TestMigrateStateConflict(t *testing.T) {
+	state := strings.Replace(testState, `"type": "prismacloud_account_group",
+      "name": "prod"`, `"type": "prismacloud_cloud_account_v2",
+      "name": "gcp"`, 1)
+
+	if _, _, err := migrateState([]byte(state), nil); err == nil {
+		t.Errorf("Expected an error moving onto an existing resource")
+	}
+}
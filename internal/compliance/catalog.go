@@ -0,0 +1,221 @@
+/*
+Package compliance loads custom compliance standards from catalogs.
+
+A catalog is either an OSCAL catalog, such as the ones published by NIST, or
+a simple JSON document that mirrors the standard, requirement and section
+tree of Prisma Cloud:
+
+	{
+	    "name": "Example standard",
+	    "description": "Made by Terraform",
+	    "requirements": [
+	        {
+	            "requirement_id": "1",
+	            "name": "Identity",
+	            "view_order": 1,
+	            "sections": [
+	                {
+	                    "section_id": "1.1",
+	                    "description": "Users have MFA enabled",
+	                    "label": "MFA",
+	                    "view_order": 1
+	                }
+	            ]
+	        }
+	    ]
+	}
+
+Either way the catalog is loaded into a Catalog, which Diff compares with the
+live standard.
+*/
+package compliance
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Catalog is a compliance standard along with its requirements.
+type Catalog struct {
+	Name         string        `json:"name"`
+	Description  string        `json:"description,omitempty"`
+	Requirements []Requirement `json:"requirements"`
+}
+
+// Requirement is a requirement of a compliance standard along with its
+// sections.
+type Requirement struct {
+	RequirementId string    `json:"requirement_id"`
+	Name          string    `json:"name"`
+	Description   string    `json:"description,omitempty"`
+	ViewOrder     int       `json:"view_order,omitempty"`
+	Sections      []Section `json:"sections,omitempty"`
+}
+
+// Section is a section of a compliance requirement.
+type Section struct {
+	SectionId   string `json:"section_id"`
+	Description string `json:"description,omitempty"`
+	Label       string `json:"label,omitempty"`
+	ViewOrder   int    `json:"view_order,omitempty"`
+}
+
+/*
+Parse loads an OSCAL catalog or a simple catalog.
+
+The catalog returned is normalized: text is trimmed, view orders that are
+not given are the position of the requirement or section, and requirements
+and sections are sorted by view order.
+*/
+func Parse(b []byte) (Catalog, error) {
+	var probe map[string]json.RawMessage
+	if err := json.Unmarshal(b, &probe); err != nil {
+		return Catalog{}, fmt.Errorf("catalog is not a JSON object: %s", err)
+	}
+
+	var ans Catalog
+	if oscal, ok := probe["catalog"]; ok {
+		var err error
+		if ans, err = parseOscal(oscal); err != nil {
+			return Catalog{}, err
+		}
+	} else {
+		dec := json.NewDecoder(bytes.NewReader(b))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&ans); err != nil {
+			return Catalog{}, fmt.Errorf("invalid catalog: %s", err)
+		}
+	}
+
+	ans.Normalize()
+	if err := ans.Validate(); err != nil {
+		return Catalog{}, err
+	}
+
+	return ans, nil
+}
+
+// Normalize trims text, fills in missing view orders and sorts the
+// requirements and sections by view order.
+func (c *Catalog) Normalize() {
+	c.Name = strings.TrimSpace(c.Name)
+	c.Description = strings.TrimSpace(c.Description)
+
+	for i := range c.Requirements {
+		r := &c.Requirements[i]
+		r.RequirementId = strings.TrimSpace(r.RequirementId)
+		r.Name = strings.TrimSpace(r.Name)
+		r.Description = strings.TrimSpace(r.Description)
+		if r.ViewOrder == 0 {
+			r.ViewOrder = i + 1
+		}
+
+		for j := range r.Sections {
+			s := &r.Sections[j]
+			s.SectionId = strings.TrimSpace(s.SectionId)
+			s.Description = strings.TrimSpace(s.Description)
+			s.Label = strings.TrimSpace(s.Label)
+			if s.ViewOrder == 0 {
+				s.ViewOrder = j + 1
+			}
+		}
+		sort.SliceStable(r.Sections, func(a, b int) bool {
+			return r.Sections[a].ViewOrder < r.Sections[b].ViewOrder
+		})
+	}
+	sort.SliceStable(c.Requirements, func(a, b int) bool {
+		return c.Requirements[a].ViewOrder < c.Requirements[b].ViewOrder
+	})
+}
+
+// Validate checks that the catalog has a name, and that requirement IDs and
+// the section IDs of each requirement are given and unique.
+func (c Catalog) Validate() error {
+	if c.Name == "" {
+		return fmt.Errorf("catalog has no name")
+	}
+
+	reqs := make(map[string]bool, len(c.Requirements))
+	for _, r := range c.Requirements {
+		switch {
+		case r.RequirementId == "":
+			return fmt.Errorf("requirement %q has no requirement_id", r.Name)
+		case reqs[r.RequirementId]:
+			return fmt.Errorf("requirement_id %q is used more than once", r.RequirementId)
+		case r.Name == "":
+			return fmt.Errorf("requirement %q has no name", r.RequirementId)
+		}
+		reqs[r.RequirementId] = true
+
+		sections := make(map[string]bool, len(r.Sections))
+		for _, s := range r.Sections {
+			switch {
+			case s.SectionId == "":
+				return fmt.Errorf("requirement %q has a section with no section_id", r.RequirementId)
+			case sections[s.SectionId]:
+				return fmt.Errorf("requirement %q has section_id %q more than once", r.RequirementId, s.SectionId)
+			}
+			sections[s.SectionId] = true
+		}
+	}
+
+	return nil
+}
+
+// Encode returns the catalog as a compact simple catalog.
+func (c Catalog) Encode() string {
+	b, _ := json.Marshal(c)
+	return string(b)
+}
+
+// Requirement returns the requirement with the given requirement ID.
+func (c Catalog) Requirement(id string) (Requirement, bool) {
+	for _, r := range c.Requirements {
+		if r.RequirementId == id {
+			return r, true
+		}
+	}
+
+	return Requirement{}, false
+}
+
+// Section returns the section with the given section ID.
+func (r Requirement) Section(id string) (Section, bool) {
+	for _, s := range r.Sections {
+		if s.SectionId == id {
+			return s, true
+		}
+	}
+
+	return Section{}, false
+}
+
+// Filter returns a copy of the catalog with only the sections that keep
+// returns true for.  Requirements left with no sections are dropped, but
+// requirements that had no sections to begin with are kept.  View orders are
+// left as is.
+func (c Catalog) Filter(keep func(r Requirement, s Section) bool) Catalog {
+	ans := c
+	ans.Requirements = make([]Requirement, 0, len(c.Requirements))
+	for _, r := range c.Requirements {
+		sections := make([]Section, 0, len(r.Sections))
+		for _, s := range r.Sections {
+			if keep(r, s) {
+				sections = append(sections, s)
+			}
+		}
+		if len(sections) == 0 && len(r.Sections) > 0 {
+			continue
+		}
+		if len(sections) == 0 {
+			sections = nil
+		}
+		r.Sections = sections
+		ans.Requirements = append(ans.Requirements, r)
+	}
+
+	return ans
+}
@@ -0,0 +1,124 @@
+package compliance
+
+import (
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func testParseFile(t *testing.T, name string) Catalog {
+	t.Helper()
+
+	b, err := os.ReadFile("testdata/" + name)
+	if err != nil {
+		t.Fatalf("Error reading fixture: %s", err)
+	}
+	ans, err := Parse(b)
+	if err != nil {
+		t.Fatalf("Error parsing %s: %s", name, err)
+	}
+	return ans
+}
+
+func TestParseSimple(t *testing.T) {
+	got := testParseFile(t, "simple_catalog.json")
+
+	want := Catalog{
+		Name:        "Example standard",
+		Description: "Made by Terraform",
+		Requirements: []Requirement{
+			{
+				RequirementId: "1",
+				Name:          "Identity",
+				Description:   "Identity and access management",
+				ViewOrder:     1,
+				Sections: []Section{
+					{SectionId: "1.1", Description: "Users have MFA enabled", Label: "MFA", ViewOrder: 1},
+					{SectionId: "1.2", Description: "Access keys are rotated", ViewOrder: 2},
+				},
+			},
+			{
+				RequirementId: "2",
+				Name:          "Logging",
+				ViewOrder:     2,
+				Sections: []Section{
+					{SectionId: "2.1", Description: "Audit logging is enabled", Label: "Audit logs", ViewOrder: 1},
+				},
+			},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Got %+v\nexpected %+v", got, want)
+	}
+}
+
+func TestParseEncodeRoundTrip(t *testing.T) {
+	c := testParseFile(t, "oscal_catalog.json")
+
+	got, err := Parse([]byte(c.Encode()))
+	if err != nil {
+		t.Fatalf("Error parsing encoded catalog: %s", err)
+	}
+	if !reflect.DeepEqual(got, c) {
+		t.Errorf("Encoded catalog parsed as %+v\nexpected %+v", got, c)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, tc := range []struct {
+		doc  string
+		want string
+	}{
+		{`[]`, "not a JSON object"},
+		{`{"name": "x", "requirement": []}`, "unknown field"},
+		{`{"requirements": []}`, "no name"},
+		{`{"name": "x", "requirements": [{"name": "a"}]}`, "no requirement_id"},
+		{`{"name": "x", "requirements": [{"requirement_id": "1", "name": "a"}, {"requirement_id": "1", "name": "b"}]}`, "more than once"},
+		{`{"name": "x", "requirements": [{"requirement_id": "1"}]}`, "no name"},
+		{`{"name": "x", "requirements": [{"requirement_id": "1", "name": "a", "sections": [{"description": "d"}]}]}`, "no section_id"},
+		{`{"name": "x", "requirements": [{"requirement_id": "1", "name": "a", "sections": [{"section_id": "1"}, {"section_id": "1"}]}]}`, "more than once"},
+		{`{"catalog": {"metadata": {"title": ""}}}`, "no name"},
+	} {
+		_, err := Parse([]byte(tc.doc))
+		if err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("%s: got error %v, expected %q", tc.doc, err, tc.want)
+		}
+	}
+}
+
+func TestFilter(t *testing.T) {
+	c := Catalog{
+		Name: "Source",
+		Requirements: []Requirement{
+			{RequirementId: "1", Name: "One", ViewOrder: 1, Sections: []Section{
+				{SectionId: "1.1", ViewOrder: 1},
+				{SectionId: "1.2", ViewOrder: 2},
+			}},
+			{RequirementId: "2", Name: "Two", ViewOrder: 2, Sections: []Section{
+				{SectionId: "2.1", ViewOrder: 1},
+			}},
+			{RequirementId: "3", Name: "Three", ViewOrder: 3},
+		},
+	}
+
+	got := c.Filter(func(r Requirement, s Section) bool {
+		return s.SectionId != "1.1" && s.SectionId != "2.1"
+	})
+
+	want := Catalog{
+		Name: "Source",
+		Requirements: []Requirement{
+			{RequirementId: "1", Name: "One", ViewOrder: 1, Sections: []Section{
+				{SectionId: "1.2", ViewOrder: 2},
+			}},
+			{RequirementId: "3", Name: "Three", ViewOrder: 3},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Got %+v\nexpected %+v", got, want)
+	}
+	if len(c.Requirements) != 3 || len(c.Requirements[0].Sections) != 2 {
+		t.Errorf("Filter changed the catalog: %+v", c)
+	}
+}
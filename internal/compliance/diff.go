@@ -0,0 +1,87 @@
+package compliance
+
+// Op is the kind of change made to a requirement or section.
+type Op int
+
+// Ops.
+const (
+	Create Op = iota
+	Update
+	Delete
+)
+
+func (o Op) String() string {
+	switch o {
+	case Create:
+		return "create"
+	case Update:
+		return "update"
+	case Delete:
+		return "delete"
+	}
+
+	return "unknown"
+}
+
+// Change is a change to a requirement, or to one of its sections if
+// SectionId is set.
+type Change struct {
+	Op            Op
+	RequirementId string
+	SectionId     string
+}
+
+/*
+Diff returns the changes that turn the old catalog into the new one.
+
+Requirements are matched by requirement ID and sections by section ID within
+their requirement.  The changes are in the order they can be applied in:
+deletions first, with the sections of a requirement deleted before it, then
+each created or updated requirement followed by the changes to its sections.
+The standard name and description are not compared.
+*/
+func Diff(old, new Catalog) []Change {
+	var ans []Change
+
+	for _, o := range old.Requirements {
+		n, ok := new.Requirement(o.RequirementId)
+		for _, s := range o.Sections {
+			if ok {
+				if _, keep := n.Section(s.SectionId); keep {
+					continue
+				}
+			}
+			ans = append(ans, Change{Op: Delete, RequirementId: o.RequirementId, SectionId: s.SectionId})
+		}
+		if !ok {
+			ans = append(ans, Change{Op: Delete, RequirementId: o.RequirementId})
+		}
+	}
+
+	for _, n := range new.Requirements {
+		o, ok := old.Requirement(n.RequirementId)
+		switch {
+		case !ok:
+			ans = append(ans, Change{Op: Create, RequirementId: n.RequirementId})
+		case !sameRequirement(o, n):
+			ans = append(ans, Change{Op: Update, RequirementId: n.RequirementId})
+		}
+
+		for _, s := range n.Sections {
+			os, ok := o.Section(s.SectionId)
+			switch {
+			case !ok:
+				ans = append(ans, Change{Op: Create, RequirementId: n.RequirementId, SectionId: s.SectionId})
+			case os != s:
+				ans = append(ans, Change{Op: Update, RequirementId: n.RequirementId, SectionId: s.SectionId})
+			}
+		}
+	}
+
+	return ans
+}
+
+// sameRequirement compares requirements without their sections.
+func sameRequirement(a, b Requirement) bool {
+	return a.Name == b.Name && a.Description == b.Description && a.ViewOrder == b.ViewOrder
+}
@@ -0,0 +1,69 @@
+package compliance
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	old := testParseFile(t, "simple_catalog.json")
+
+	// Drop requirement 2, reword section 1.1, drop 1.2 and add 1.3 and 3.
+	b := []byte(`{
+	    "name": "Renamed",
+	    "requirements": [
+	        {
+	            "requirement_id": "1",
+	            "name": "Identity",
+	            "description": "Identity and access management",
+	            "sections": [
+	                {"section_id": "1.1", "description": "All users have MFA enabled", "label": "MFA"},
+	                {"section_id": "1.3", "description": "Root account is not used"}
+	            ]
+	        },
+	        {
+	            "requirement_id": "3",
+	            "name": "Network",
+	            "sections": [{"section_id": "3.1"}]
+	        }
+	    ]
+	}`)
+	n, err := Parse(b)
+	if err != nil {
+		t.Fatalf("Error parsing: %s", err)
+	}
+
+	got := Diff(old, n)
+	want := []Change{
+		{Op: Delete, RequirementId: "1", SectionId: "1.2"},
+		{Op: Delete, RequirementId: "2", SectionId: "2.1"},
+		{Op: Delete, RequirementId: "2"},
+		{Op: Update, RequirementId: "1", SectionId: "1.1"},
+		{Op: Create, RequirementId: "1", SectionId: "1.3"},
+		{Op: Create, RequirementId: "3"},
+		{Op: Create, RequirementId: "3", SectionId: "3.1"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Got %v\nexpected %v", got, want)
+	}
+
+	if changes := Diff(n, n); len(changes) != 0 {
+		t.Errorf("Got changes between a catalog and itself: %v", changes)
+	}
+}
+
+func TestDiffViewOrder(t *testing.T) {
+	old := testParseFile(t, "simple_catalog.json")
+	n := testParseFile(t, "simple_catalog.json")
+	n.Requirements[0].ViewOrder = 5
+	n.Requirements[1].Sections[0].Label = "CloudTrail"
+
+	got := Diff(old, n)
+	want := []Change{
+		{Op: Update, RequirementId: "1"},
+		{Op: Update, RequirementId: "2", SectionId: "2.1"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Got %v\nexpected %v", got, want)
+	}
+}
@@ -0,0 +1,176 @@
+package compliance
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// The parts of an OSCAL catalog that are loaded.
+type oscalCatalog struct {
+	Metadata struct {
+		Title   string `json:"title"`
+		Remarks string `json:"remarks"`
+	} `json:"metadata"`
+	Groups   []oscalGroup   `json:"groups"`
+	Controls []oscalControl `json:"controls"`
+}
+
+type oscalGroup struct {
+	Id       string         `json:"id"`
+	Title    string         `json:"title"`
+	Props    []oscalProp    `json:"props"`
+	Parts    []oscalPart    `json:"parts"`
+	Groups   []oscalGroup   `json:"groups"`
+	Controls []oscalControl `json:"controls"`
+}
+
+type oscalControl struct {
+	Id       string         `json:"id"`
+	Title    string         `json:"title"`
+	Props    []oscalProp    `json:"props"`
+	Parts    []oscalPart    `json:"parts"`
+	Controls []oscalControl `json:"controls"`
+}
+
+type oscalProp struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type oscalPart struct {
+	Name  string      `json:"name"`
+	Title string      `json:"title"`
+	Props []oscalProp `json:"props"`
+	Prose string      `json:"prose"`
+	Parts []oscalPart `json:"parts"`
+}
+
+/*
+parseOscal loads the "catalog" of an OSCAL catalog.
+
+The catalog title is the standard name.  Each top level group becomes a
+requirement, and the controls in the group, in its nested groups and their
+control enhancements become its sections, in document order.  Controls that
+are not in a group become a requirement each, with their enhancements as
+sections, or with a section for the control itself if it has none.
+
+Requirement and section IDs are the "label" prop, falling back to the OSCAL
+ID.  Section labels are the control titles, and descriptions are the prose
+of the group overview or control statement.  Withdrawn controls are skipped.
+*/
+func parseOscal(b []byte) (Catalog, error) {
+	var oc oscalCatalog
+	if err := json.Unmarshal(b, &oc); err != nil {
+		return Catalog{}, fmt.Errorf("invalid OSCAL catalog: %s", err)
+	}
+
+	ans := Catalog{
+		Name:        oc.Metadata.Title,
+		Description: oc.Metadata.Remarks,
+	}
+
+	for _, g := range oc.Groups {
+		ans.Requirements = append(ans.Requirements, Requirement{
+			RequirementId: oscalLabel(g.Props, g.Id),
+			Name:          g.Title,
+			Description:   oscalProse(g.Parts, "overview"),
+			Sections:      oscalGroupSections(g),
+		})
+	}
+
+	for _, c := range oc.Controls {
+		if oscalWithdrawn(c.Props) {
+			continue
+		}
+		sections := oscalControlSections(c.Controls)
+		if len(sections) == 0 {
+			sections = oscalControlSections([]oscalControl{c})
+		}
+		ans.Requirements = append(ans.Requirements, Requirement{
+			RequirementId: oscalLabel(c.Props, c.Id),
+			Name:          c.Title,
+			Description:   oscalProse(c.Parts, "statement"),
+			Sections:      sections,
+		})
+	}
+
+	return ans, nil
+}
+
+// oscalGroupSections returns the sections for the controls of a group and
+// its nested groups.
+func oscalGroupSections(g oscalGroup) []Section {
+	ans := oscalControlSections(g.Controls)
+	for _, sub := range g.Groups {
+		ans = append(ans, oscalGroupSections(sub)...)
+	}
+
+	return ans
+}
+
+// oscalControlSections returns the sections for the given controls, each
+// followed by its enhancements.
+func oscalControlSections(controls []oscalControl) []Section {
+	var ans []Section
+	for _, c := range controls {
+		if oscalWithdrawn(c.Props) {
+			continue
+		}
+		ans = append(ans, Section{
+			SectionId:   oscalLabel(c.Props, c.Id),
+			Description: oscalProse(c.Parts, "statement"),
+			Label:       c.Title,
+		})
+		ans = append(ans, oscalControlSections(c.Controls)...)
+	}
+
+	return ans
+}
+
+// oscalLabel returns the "label" prop, or the given ID if there is none.
+func oscalLabel(props []oscalProp, id string) string {
+	for _, p := range props {
+		if p.Name == "label" {
+			return p.Value
+		}
+	}
+
+	return id
+}
+
+func oscalWithdrawn(props []oscalProp) bool {
+	for _, p := range props {
+		if p.Name == "status" && p.Value == "withdrawn" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// oscalProse returns the prose of the part with the given name, with one
+// line for each nested part, such as the items of a control statement.
+func oscalProse(parts []oscalPart, name string) string {
+	for _, p := range parts {
+		if p.Name == name {
+			var lines []string
+			oscalPartLines(p, &lines)
+			return strings.Join(lines, "\n")
+		}
+	}
+
+	return ""
+}
+
+func oscalPartLines(p oscalPart, lines *[]string) {
+	if prose := strings.TrimSpace(p.Prose); prose != "" {
+		if label := oscalLabel(p.Props, ""); label != "" {
+			prose = label + " " + prose
+		}
+		*lines = append(*lines, prose)
+	}
+	for _, sub := range p.Parts {
+		oscalPartLines(sub, lines)
+	}
+}
@@ -0,0 +1,73 @@
+package compliance
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseOscal(t *testing.T) {
+	got := testParseFile(t, "oscal_catalog.json")
+
+	want := Catalog{
+		Name:        "Example Security Controls",
+		Description: "An excerpt in the shape of NIST SP 800-53.",
+		Requirements: []Requirement{
+			{
+				RequirementId: "ac",
+				Name:          "Access Control",
+				ViewOrder:     1,
+				Sections: []Section{
+					{
+						SectionId:   "AC-1",
+						Description: "a. Develop, document, and disseminate to {{ insert: param, ac-1_prm_1 }} an access control policy;\nb. Review and update the current access control policy.",
+						Label:       "Policy and Procedures",
+						ViewOrder:   1,
+					},
+					{
+						SectionId:   "AC-2",
+						Description: "Manage system accounts.",
+						Label:       "Account Management",
+						ViewOrder:   2,
+					},
+					{
+						SectionId:   "AC-2(1)",
+						Description: "Support the management of system accounts using automated mechanisms.",
+						Label:       "Automated System Account Management",
+						ViewOrder:   3,
+					},
+				},
+			},
+			{
+				RequirementId: "ir",
+				Name:          "Incident Response",
+				Description:   "Controls for handling incidents.",
+				ViewOrder:     2,
+				Sections: []Section{
+					{
+						SectionId:   "ir-1",
+						Description: "Develop an incident response policy.",
+						Label:       "Policy and Procedures",
+						ViewOrder:   1,
+					},
+				},
+			},
+			{
+				RequirementId: "PM-1",
+				Name:          "Information Security Program Plan",
+				Description:   "Develop and disseminate an organization-wide information security program plan.",
+				ViewOrder:     3,
+				Sections: []Section{
+					{
+						SectionId:   "PM-1",
+						Description: "Develop and disseminate an organization-wide information security program plan.",
+						Label:       "Information Security Program Plan",
+						ViewOrder:   1,
+					},
+				},
+			},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Got %+v\nexpected %+v", got, want)
+	}
+}
@@ -0,0 +1,338 @@
+package fakeapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Collection is an in-memory store of JSON objects of a single type.
+type Collection struct {
+	// Name is the human readable name of the objects stored.
+	Name string
+
+	// IdKey is the JSON key the object ID is stored in.
+	IdKey string
+
+	// NameKey is the JSON key the object name is stored in.
+	NameKey string
+
+	// NotFoundKey is the i18n key returned when an object does not exist.
+	NotFoundKey string
+
+	// DuplicateKey is the i18n key returned when an object is created with
+	// a name that is already in use.
+	DuplicateKey string
+
+	// ParentKey is the JSON key the parent object ID is stored in, for
+	// objects that are created and listed under their parent, such as the
+	// requirements of a compliance standard.  The parent ID is taken from
+	// the "id" path param, names only need to be unique within the parent,
+	// and updates keep the parent ID.
+	ParentKey string
+
+	s     *Server
+	items map[string]map[string]interface{}
+	order []string
+}
+
+// NewCollection returns a new empty collection owned by the server.
+func (s *Server) NewCollection(name, idKey string) *Collection {
+	return &Collection{
+		Name:         name,
+		IdKey:        idKey,
+		NameKey:      "name",
+		NotFoundKey:  "not_found",
+		DuplicateKey: strings.Replace(name, " ", "_", -1) + "_already_exists",
+		s:            s,
+		items:        make(map[string]map[string]interface{}),
+	}
+}
+
+// Put stores the given object, which may be a struct or a map, and returns
+// its ID.  If the object has no ID, a new one is assigned.
+func (c *Collection) Put(v interface{}) string {
+	obj := toMap(v)
+
+	c.s.mu.Lock()
+	defer c.s.mu.Unlock()
+
+	return c.put(obj)
+}
+
+func (c *Collection) put(obj map[string]interface{}) string {
+	id, _ := obj[c.IdKey].(string)
+	if id == "" {
+		id = c.s.nextId()
+		obj[c.IdKey] = id
+	}
+	if _, ok := c.items[id]; !ok {
+		c.order = append(c.order, id)
+	}
+	c.items[id] = obj
+
+	return id
+}
+
+// Get returns a copy of the object with the given ID.
+func (c *Collection) Get(id string) (map[string]interface{}, bool) {
+	c.s.mu.Lock()
+	defer c.s.mu.Unlock()
+
+	obj, ok := c.items[id]
+	if !ok {
+		return nil, false
+	}
+	return toMap(obj), true
+}
+
+// Load unmarshals the object with the given ID into v.
+func (c *Collection) Load(id string, v interface{}) bool {
+	obj, ok := c.Get(id)
+	if !ok {
+		return false
+	}
+	b, _ := json.Marshal(obj)
+	return json.Unmarshal(b, v) == nil
+}
+
+// All returns copies of all objects in insertion order.
+func (c *Collection) All() []map[string]interface{} {
+	c.s.mu.Lock()
+	defer c.s.mu.Unlock()
+
+	return c.all()
+}
+
+func (c *Collection) all() []map[string]interface{} {
+	ans := make([]map[string]interface{}, 0, len(c.order))
+	for _, id := range c.order {
+		ans = append(ans, toMap(c.items[id]))
+	}
+	return ans
+}
+
+// Len returns the number of objects stored.
+func (c *Collection) Len() int {
+	c.s.mu.Lock()
+	defer c.s.mu.Unlock()
+
+	return len(c.order)
+}
+
+// Delete removes the object with the given ID.
+func (c *Collection) Delete(id string) {
+	c.s.mu.Lock()
+	defer c.s.mu.Unlock()
+
+	c.delete(id)
+}
+
+func (c *Collection) delete(id string) bool {
+	if _, ok := c.items[id]; !ok {
+		return false
+	}
+	delete(c.items, id)
+	for i := range c.order {
+		if c.order[i] == id {
+			c.order = append(c.order[:i], c.order[i+1:]...)
+			break
+		}
+	}
+	return true
+}
+
+func (c *Collection) notFound(id string) *Error {
+	return Errorf(http.StatusNotFound, c.NotFoundKey, id)
+}
+
+// ListHandler returns all objects, filtered by any query params given.
+//
+// A query param named "<prefix>.<key>" only keeps objects whose JSON field
+// <key> matches the param value.  Array fields match if any element does.
+func (c *Collection) ListHandler(prefix string) HandlerFunc {
+	return func(r *Request) (interface{}, error) {
+		c.s.mu.Lock()
+		defer c.s.mu.Unlock()
+
+		return filter(c.all(), prefix, r.URL.Query()), nil
+	}
+}
+
+// ChildrenHandler returns the objects whose parent is named by the "id" path
+// param.
+func (c *Collection) ChildrenHandler() HandlerFunc {
+	return func(r *Request) (interface{}, error) {
+		c.s.mu.Lock()
+		defer c.s.mu.Unlock()
+
+		ans := make([]map[string]interface{}, 0)
+		for _, obj := range c.all() {
+			if obj[c.ParentKey] == r.Params["id"] {
+				ans = append(ans, obj)
+			}
+		}
+		return ans, nil
+	}
+}
+
+// NamesHandler returns the id and name of all objects.
+func (c *Collection) NamesHandler() HandlerFunc {
+	return func(r *Request) (interface{}, error) {
+		c.s.mu.Lock()
+		defer c.s.mu.Unlock()
+
+		ans := make([]map[string]interface{}, 0, len(c.order))
+		for _, id := range c.order {
+			ans = append(ans, map[string]interface{}{
+				"id":   id,
+				"name": c.items[id][c.NameKey],
+			})
+		}
+		return ans, nil
+	}
+}
+
+// GetHandler returns the object named by the "id" path param.
+func (c *Collection) GetHandler() HandlerFunc {
+	return func(r *Request) (interface{}, error) {
+		c.s.mu.Lock()
+		defer c.s.mu.Unlock()
+
+		id := r.Params["id"]
+		obj, ok := c.items[id]
+		if !ok {
+			return nil, c.notFound(id)
+		}
+		return obj, nil
+	}
+}
+
+// CreateHandler stores the request body as a new object.
+func (c *Collection) CreateHandler() HandlerFunc {
+	return func(r *Request) (interface{}, error) {
+		var obj map[string]interface{}
+		if err := r.Decode(&obj); err != nil || obj == nil {
+			return nil, Errorf(http.StatusBadRequest, "invalid_json", c.Name)
+		}
+
+		c.s.mu.Lock()
+		defer c.s.mu.Unlock()
+
+		if c.ParentKey != "" {
+			obj[c.ParentKey] = r.Params["id"]
+		}
+
+		name := obj[c.NameKey]
+		for _, o := range c.items {
+			if name != nil && o[c.NameKey] == name && o[c.ParentKey] == obj[c.ParentKey] {
+				return nil, Errorf(http.StatusBadRequest, c.DuplicateKey, fmt.Sprint(name))
+			}
+		}
+		obj[c.IdKey] = ""
+		c.put(obj)
+
+		return obj, nil
+	}
+}
+
+// UpdateHandler replaces the object named by the "id" path param.
+func (c *Collection) UpdateHandler() HandlerFunc {
+	return func(r *Request) (interface{}, error) {
+		var obj map[string]interface{}
+		if err := r.Decode(&obj); err != nil || obj == nil {
+			return nil, Errorf(http.StatusBadRequest, "invalid_json", c.Name)
+		}
+
+		c.s.mu.Lock()
+		defer c.s.mu.Unlock()
+
+		id := r.Params["id"]
+		cur, ok := c.items[id]
+		if !ok {
+			return nil, c.notFound(id)
+		}
+		obj[c.IdKey] = id
+		if c.ParentKey != "" {
+			obj[c.ParentKey] = cur[c.ParentKey]
+		}
+		c.put(obj)
+
+		return nil, nil
+	}
+}
+
+// DeleteHandler removes the object named by the "id" path param.
+func (c *Collection) DeleteHandler() HandlerFunc {
+	return func(r *Request) (interface{}, error) {
+		c.s.mu.Lock()
+		defer c.s.mu.Unlock()
+
+		id := r.Params["id"]
+		if !c.delete(id) {
+			return nil, c.notFound(id)
+		}
+		return nil, nil
+	}
+}
+
+// Serve registers the standard create, read, update and delete routes for
+// the collection under the given path, along with the list route.
+func (c *Collection) Serve(path, listPath, filterPrefix string) {
+	c.s.Handle("GET", listPath, c.ListHandler(filterPrefix))
+	c.s.Handle("POST", path, c.CreateHandler())
+	c.s.Handle("GET", path+"/{id}", c.GetHandler())
+	c.s.Handle("PUT", path+"/{id}", c.UpdateHandler())
+	c.s.Handle("DELETE", path+"/{id}", c.DeleteHandler())
+}
+
+func filter(list []map[string]interface{}, prefix string, query url.Values) []map[string]interface{} {
+	if prefix == "" || len(query) == 0 {
+		return list
+	}
+
+	ans := make([]map[string]interface{}, 0, len(list))
+	for _, obj := range list {
+		keep := true
+		for k := range query {
+			if !strings.HasPrefix(k, prefix+".") {
+				continue
+			}
+			if !fieldMatches(obj[strings.TrimPrefix(k, prefix+".")], query.Get(k)) {
+				keep = false
+				break
+			}
+		}
+		if keep {
+			ans = append(ans, obj)
+		}
+	}
+	return ans
+}
+
+func fieldMatches(v interface{}, want string) bool {
+	if list, ok := v.([]interface{}); ok {
+		for _, item := range list {
+			if fmt.Sprint(item) == want {
+				return true
+			}
+		}
+		return false
+	}
+	return v != nil && fmt.Sprint(v) == want
+}
+
+// toMap converts v to a generic JSON object by round tripping it.
+func toMap(v interface{}) map[string]interface{} {
+	b, err := json.Marshal(v)
+	if err != nil {
+		panic(err)
+	}
+	var ans map[string]interface{}
+	if err = json.Unmarshal(b, &ans); err != nil {
+		panic(err)
+	}
+	return ans
+}
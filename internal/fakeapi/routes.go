@@ -0,0 +1,436 @@
+package fakeapi
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+func (s *Server) registerDefaults() {
+	s.Handle("POST", "/login", s.login)
+	s.Handle("GET", "/auth_token/extend", s.extend)
+
+	s.Policies.Serve("/policy", "/v2/policy", "policy")
+	s.Handle("GET", "/v2/policy", s.listPolicies)
+	s.Handle("PATCH", "/policy/{id}/status/{enabled}", s.setPolicyStatus)
+	s.AlertRules.Serve("/alert/rule", "/v2/alert/rule", "")
+	s.AccountGroups.Serve("/cloud/group", "/cloud/group", "")
+	s.Handle("GET", "/cloud/group/name", s.AccountGroups.NamesHandler())
+	s.UserRoles.Serve("/user/role", "/user/role", "")
+	s.Handle("GET", "/user/role/name", s.UserRoles.NamesHandler())
+	s.Reports.Serve("/report", "/report", "")
+	s.Handle("GET", "/report/{id}/download", s.downloadReport)
+	s.Standards.Serve("/compliance", "/compliance", "")
+	s.Handle("GET", "/compliance/{id}/requirement", s.Requirements.ChildrenHandler())
+	s.Handle("POST", "/compliance/{id}/requirement", s.Requirements.CreateHandler())
+	s.Handle("GET", "/compliance/requirement/{id}", s.Requirements.GetHandler())
+	s.Handle("PUT", "/compliance/requirement/{id}", s.Requirements.UpdateHandler())
+	s.Handle("DELETE", "/compliance/requirement/{id}", s.Requirements.DeleteHandler())
+	s.Handle("GET", "/compliance/{id}/section", s.listSections)
+	s.Handle("POST", "/compliance/{id}/section", s.Sections.CreateHandler())
+	s.Handle("PUT", "/compliance/requirement/section/{id}", s.Sections.UpdateHandler())
+	s.Handle("DELETE", "/compliance/requirement/section/{id}", s.Sections.DeleteHandler())
+	s.Handle("POST", "/cas/v1/aws_account", s.createAwsAccountV2)
+	s.Handle("PUT", "/cas/v1/aws_account/{id}", s.AwsAccountsV2.UpdateHandler())
+	s.Handle("GET", "/v1/cloudAccounts/awsAccounts", s.listAwsAccountsV2)
+	s.Handle("GET", "/v1/cloudAccounts/aws/{id}", s.getAwsAccountV2)
+	s.Handle("DELETE", "/cloud/aws/{id}", s.AwsAccountsV2.DeleteHandler())
+
+	s.Handle("POST", "/v2/alert", s.listAlerts)
+	s.Handle("GET", "/alert/{id}", s.Alerts.GetHandler())
+	s.Handle("POST", "/alert/dismiss", s.setAlertStatus(true))
+	s.Handle("POST", "/alert/reopen", s.setAlertStatus(false))
+
+	for _, st := range []struct {
+		path       string
+		searchType string
+	}{
+		{"/search/config", "config"},
+		{"/search/event", "event"},
+		{"/search/", "network"},
+		{"/api/v1/permission", "iam"},
+		{"/search/api/v1/asset", "asset"},
+	} {
+		s.Handle("POST", st.path, s.search(st.searchType))
+	}
+}
+
+func (s *Server) login(r *Request) (interface{}, error) {
+	var req struct {
+		Username     string `json:"username"`
+		Password     string `json:"password"`
+		CustomerName string `json:"customerName"`
+	}
+	if err := r.Decode(&req); err != nil {
+		return nil, Errorf(http.StatusBadRequest, "invalid_json", "login")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.Username != "" && (req.Username != s.Username || req.Password != s.Password) {
+		return nil, Errorf(http.StatusUnauthorized, "invalid_credentials", "login")
+	}
+
+	return map[string]interface{}{
+		"token":   s.issueToken(),
+		"message": "login_successful",
+	}, nil
+}
+
+func (s *Server) extend(r *Request) (interface{}, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return map[string]interface{}{
+		"token":   s.issueToken(),
+		"message": "login_successful",
+	}, nil
+}
+
+// alertFields maps alert filter names to the path of the JSON field they
+// match against.
+var alertFields = map[string][]string{
+	"alert.id":        {"id"},
+	"alert.status":    {"status"},
+	"policy.id":       {"policy", "policyId"},
+	"policy.type":     {"policy", "policyType"},
+	"resource.id":     {"resource", "id"},
+	"resource.name":   {"resource", "name"},
+	"cloud.account":   {"resource", "account"},
+	"cloud.accountId": {"resource", "accountId"},
+	"cloud.region":    {"resource", "region"},
+	"cloud.type":      {"resource", "cloudType"},
+}
+
+// listAlerts implements alert listing with filters and page token based
+// pagination, where the page token is the offset of the next page.
+func (s *Server) listAlerts(r *Request) (interface{}, error) {
+	var req struct {
+		Limit     int           `json:"limit"`
+		PageToken string        `json:"pageToken"`
+		Filters   []alertFilter `json:"filters"`
+	}
+	if err := r.Decode(&req); err != nil {
+		return nil, Errorf(http.StatusBadRequest, "invalid_json", "alert")
+	}
+
+	items, err := s.matchAlerts(req.Filters)
+	if err != nil {
+		return nil, err
+	}
+
+	start := 0
+	if req.PageToken != "" {
+		v, err := strconv.Atoi(req.PageToken)
+		if err != nil || v < 0 || v > len(items) {
+			return nil, Errorf(http.StatusBadRequest, "invalid_page_token", req.PageToken)
+		}
+		start = v
+	}
+	end := len(items)
+	if req.Limit > 0 && start+req.Limit < end {
+		end = start + req.Limit
+	}
+
+	var next string
+	if end < len(items) {
+		next = strconv.Itoa(end)
+	}
+
+	return map[string]interface{}{
+		"totalRows":     len(items),
+		"items":         items[start:end],
+		"nextPageToken": next,
+	}, nil
+}
+
+type alertFilter struct {
+	Name     string `json:"name"`
+	Operator string `json:"operator"`
+	Value    string `json:"value"`
+}
+
+// matchAlerts returns the alerts that match all of the given filters.
+func (s *Server) matchAlerts(filters []alertFilter) ([]map[string]interface{}, error) {
+	items := make([]map[string]interface{}, 0)
+	for _, a := range s.Alerts.All() {
+		keep := true
+		for _, f := range filters {
+			path, ok := alertFields[f.Name]
+			if !ok {
+				return nil, Errorf(http.StatusBadRequest, "invalid_filter", f.Name)
+			}
+			if !fieldMatches(lookup(a, path), f.Value) {
+				keep = false
+				break
+			}
+		}
+		if keep {
+			items = append(items, a)
+		}
+	}
+
+	return items, nil
+}
+
+// setAlertStatus implements dismissing (or snoozing, if a dismissal time
+// range is given) and reopening the alerts listed, or the alerts matching
+// the filter if none are listed.  A history entry is added to each alert.
+func (s *Server) setAlertStatus(dismiss bool) HandlerFunc {
+	return func(r *Request) (interface{}, error) {
+		var req struct {
+			Alerts             []string    `json:"alerts"`
+			DismissalNote      string      `json:"dismissalNote"`
+			DismissalTimeRange interface{} `json:"dismissalTimeRange"`
+			Filter             struct {
+				Filters []alertFilter `json:"filters"`
+			} `json:"filter"`
+		}
+		if err := r.Decode(&req); err != nil {
+			return nil, Errorf(http.StatusBadRequest, "invalid_json", "alert")
+		}
+		if dismiss && req.DismissalNote == "" {
+			return nil, Errorf(http.StatusBadRequest, "dismissal_note_required", "dismissalNote")
+		}
+
+		var items []map[string]interface{}
+		if len(req.Alerts) > 0 {
+			for _, id := range req.Alerts {
+				a, ok := s.Alerts.Get(id)
+				if !ok {
+					return nil, s.Alerts.notFound(id)
+				}
+				items = append(items, a)
+			}
+		} else {
+			var err error
+			if items, err = s.matchAlerts(req.Filter.Filters); err != nil {
+				return nil, err
+			}
+		}
+
+		status := "open"
+		if dismiss {
+			status = "dismissed"
+			if req.DismissalTimeRange != nil {
+				status = "snoozed"
+			}
+		}
+		for _, a := range items {
+			a["status"] = status
+			history, _ := a["history"].([]interface{})
+			a["history"] = append(history, map[string]interface{}{
+				"status":     status,
+				"reason":     req.DismissalNote,
+				"modifiedBy": s.Username,
+			})
+			s.Alerts.Put(a)
+		}
+
+		return nil, nil
+	}
+}
+
+// listPolicies implements policy listing with the policy.label and
+// policy.complianceStandard filters on top of the collection filters.
+func (s *Server) listPolicies(r *Request) (interface{}, error) {
+	query := url.Values{}
+	var standard string
+	for k, v := range r.URL.Query() {
+		switch k {
+		case "policy.label":
+			query["policy.labels"] = v
+		case "policy.complianceStandard":
+			standard = v[0]
+		default:
+			query[k] = v
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	list := filter(s.Policies.all(), "policy", query)
+	if standard == "" {
+		return list, nil
+	}
+
+	ans := make([]map[string]interface{}, 0, len(list))
+	for _, obj := range list {
+		cms, _ := obj["complianceMetadata"].([]interface{})
+		for _, cm := range cms {
+			if m, ok := cm.(map[string]interface{}); ok && m["standardName"] == standard {
+				ans = append(ans, obj)
+				break
+			}
+		}
+	}
+	return ans, nil
+}
+
+func (s *Server) setPolicyStatus(r *Request) (interface{}, error) {
+	enabled, err := strconv.ParseBool(r.Params["enabled"])
+	if err != nil {
+		return nil, Errorf(http.StatusBadRequest, "invalid_enabled", r.Params["enabled"])
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := r.Params["id"]
+	obj, ok := s.Policies.items[id]
+	if !ok {
+		return nil, s.Policies.notFound(id)
+	}
+	obj["enabled"] = enabled
+
+	return nil, nil
+}
+
+// listSections returns the compliance sections of the requirement named by
+// the "id" path param, which is kept in the requirementId field of each
+// section.  The associated policy IDs are those of the policies whose
+// compliance metadata has the section ID as its complianceId.
+func (s *Server) listSections(r *Request) (interface{}, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	policies := make(map[string][]interface{})
+	for _, p := range s.Policies.all() {
+		cms, _ := p["complianceMetadata"].([]interface{})
+		for _, cm := range cms {
+			if m, ok := cm.(map[string]interface{}); ok {
+				id, _ := m["complianceId"].(string)
+				policies[id] = append(policies[id], p["policyId"])
+			}
+		}
+	}
+
+	ans := make([]map[string]interface{}, 0)
+	for _, obj := range s.Sections.all() {
+		if obj["requirementId"] != r.Params["id"] {
+			continue
+		}
+		id, _ := obj["id"].(string)
+		obj["associatedPolicyIds"] = policies[id]
+		obj["policiesAssignedCount"] = len(policies[id])
+		ans = append(ans, obj)
+	}
+	return ans, nil
+}
+
+func (s *Server) downloadReport(r *Request) (interface{}, error) {
+	id := r.Params["id"]
+	obj, ok := s.Reports.Get(id)
+	if !ok {
+		return nil, s.Reports.notFound(id)
+	}
+	if obj["status"] != "completed" {
+		return nil, Errorf(http.StatusBadRequest, "report_not_ready", id)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.ReportFiles[id], nil
+}
+
+func (s *Server) search(searchType string) HandlerFunc {
+	return func(r *Request) (interface{}, error) {
+		var req map[string]interface{}
+		if err := r.Decode(&req); err != nil {
+			return nil, Errorf(http.StatusBadRequest, "invalid_json", "search")
+		}
+		q, _ := req["query"].(string)
+		if strings.TrimSpace(q) == "" {
+			return nil, Errorf(http.StatusBadRequest, "invalid_rql", "query")
+		}
+
+		return map[string]interface{}{
+			"id":         s.NextId(),
+			"searchType": searchType,
+			"name":       fmt.Sprintf("%s search", searchType),
+			"data": map[string]interface{}{
+				"items": []interface{}{},
+			},
+		}, nil
+	}
+}
+
+func lookup(obj map[string]interface{}, path []string) interface{} {
+	var cur interface{} = obj
+	for _, key := range path {
+		m, ok := cur.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		cur = m[key]
+	}
+	return cur
+}
+
+// createAwsAccountV2 onboards an AWS account, which is stored under the
+// account ID given in the request body.
+func (s *Server) createAwsAccountV2(r *Request) (interface{}, error) {
+	var obj map[string]interface{}
+	if err := r.Decode(&obj); err != nil || obj == nil {
+		return nil, Errorf(http.StatusBadRequest, "invalid_json", "cloud account")
+	}
+	id, _ := obj["accountId"].(string)
+	if id == "" {
+		return nil, Errorf(http.StatusBadRequest, "invalid_account_id", "accountId")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.AwsAccountsV2.items[id]; ok {
+		return nil, Errorf(http.StatusBadRequest, s.AwsAccountsV2.DuplicateKey, id)
+	}
+	s.AwsAccountsV2.put(obj)
+
+	return nil, nil
+}
+
+func (s *Server) listAwsAccountsV2(r *Request) (interface{}, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ans := make([]map[string]interface{}, 0, len(s.AwsAccountsV2.items))
+	for _, obj := range s.AwsAccountsV2.all() {
+		ans = append(ans, awsAccountV2Response(obj))
+	}
+	return ans, nil
+}
+
+func (s *Server) getAwsAccountV2(r *Request) (interface{}, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := r.Params["id"]
+	obj, ok := s.AwsAccountsV2.items[id]
+	if !ok {
+		return nil, s.AwsAccountsV2.notFound(id)
+	}
+	return awsAccountV2Response(obj), nil
+}
+
+// awsAccountV2Response returns a stored AWS account the way it is read back,
+// with the account details nested under "cloudAccount".
+func awsAccountV2Response(obj map[string]interface{}) map[string]interface{} {
+	return map[string]interface{}{
+		"cloudAccount": map[string]interface{}{
+			"accountId":   obj["accountId"],
+			"name":        obj["name"],
+			"cloudType":   "aws",
+			"accountType": obj["accountType"],
+			"enabled":     obj["enabled"],
+		},
+		"name":     obj["name"],
+		"roleArn":  obj["roleArn"],
+		"groupIds": obj["groupIds"],
+	}
+}
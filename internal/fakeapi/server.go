@@ -0,0 +1,363 @@
+/*
+Package fakeapi is an in-memory stand-in for the Prisma Cloud API.
+
+It serves the endpoints used by the prisma-cloud-go packages over a local
+httptest server so that provider code can be exercised without a live tenant.
+Errors are returned the same way the real API returns them: a non-2xx status
+code along with a JSON list of errors in the X-Redlock-Status header.
+*/
+package fakeapi
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// StatusHeader is the header Prisma Cloud returns error details in.
+const StatusHeader = "X-Redlock-Status"
+
+// RequestIdHeader is the header Prisma Cloud identifies each request with.
+const RequestIdHeader = "X-Redlock-Request-Id"
+
+// HandlerFunc handles a single API call.
+//
+// The returned value is marshaled as the JSON response body, except for a
+// []byte, which is sent as is like a file download.  If the returned error
+// is an *Error, its status code and i18n key are sent back to the client,
+// otherwise the error is reported as an internal error.
+type HandlerFunc func(r *Request) (interface{}, error)
+
+// Request is an API call as seen by a HandlerFunc.
+type Request struct {
+	*http.Request
+
+	// Params are the path parameters matched by the route.
+	Params map[string]string
+
+	// Body is the raw request body.
+	Body []byte
+}
+
+// Decode unmarshals the request body into v.
+func (r *Request) Decode(v interface{}) error {
+	if len(r.Body) == 0 {
+		return nil
+	}
+	return json.Unmarshal(r.Body, v)
+}
+
+// Error is an API error returned by a HandlerFunc.
+type Error struct {
+	StatusCode int
+	Key        string
+	Subject    string
+
+	// Header holds additional response headers, such as Retry-After.
+	Header http.Header
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%d %s (%s)", e.StatusCode, e.Key, e.Subject)
+}
+
+// Errorf returns an *Error with the given status code, i18n key and subject.
+func Errorf(code int, key, subject string) *Error {
+	return &Error{StatusCode: code, Key: key, Subject: subject}
+}
+
+// Call is a record of a request received by the server.
+type Call struct {
+	Method string
+	Path   string
+	Query  string
+}
+
+type route struct {
+	method   string
+	segments []string
+	handler  HandlerFunc
+}
+
+// Server is the fake Prisma Cloud API server.
+type Server struct {
+	*httptest.Server
+
+	// Username and Password are the credentials accepted by /login.  If
+	// Username is empty, any credentials are accepted.
+	Username string
+	Password string
+
+	// TokenLifetime is how long issued JSON web tokens are valid for.
+	TokenLifetime time.Duration
+
+	// Collections served by the default routes.
+	Policies      *Collection
+	AlertRules    *Collection
+	AccountGroups *Collection
+	UserRoles     *Collection
+	Reports       *Collection
+	Alerts        *Collection
+	Standards     *Collection
+	Requirements  *Collection
+	Sections      *Collection
+	AwsAccountsV2 *Collection
+
+	// ReportFiles holds the generated file of each report by report ID,
+	// which is served once the report status is "completed".
+	ReportFiles map[string][]byte
+
+	mu      sync.Mutex
+	routes  []route
+	tokens  map[string]time.Time
+	calls   []Call
+	counter int
+}
+
+// New starts a new fake API server with the default routes registered.
+//
+// The caller should call Close when finished.
+func New() *Server {
+	s := &Server{
+		TokenLifetime: 10 * time.Minute,
+		tokens:        make(map[string]time.Time),
+		ReportFiles:   make(map[string][]byte),
+	}
+
+	s.Policies = s.NewCollection("policy", "policyId")
+	s.Policies.DuplicateKey = "duplicate_policy_name"
+	s.AlertRules = s.NewCollection("alert rule", "policyScanConfigId")
+	s.AccountGroups = s.NewCollection("account group", "id")
+	s.AccountGroups.NotFoundKey = "account_group_not_found"
+	s.UserRoles = s.NewCollection("user role", "id")
+	s.Reports = s.NewCollection("report", "id")
+	s.Alerts = s.NewCollection("alert", "id")
+	s.Standards = s.NewCollection("compliance standard", "id")
+	s.Requirements = s.NewCollection("compliance standard requirement", "id")
+	s.Requirements.NameKey = "requirementId"
+	s.Requirements.ParentKey = "complianceId"
+	s.Sections = s.NewCollection("compliance standard requirement section", "id")
+	s.Sections.NameKey = "sectionId"
+	s.Sections.ParentKey = "requirementId"
+	s.AwsAccountsV2 = s.NewCollection("cloud account", "accountId")
+	s.AwsAccountsV2.DuplicateKey = "duplicate_cloud_account"
+
+	s.registerDefaults()
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+
+	return s
+}
+
+// Host returns the host portion of the server's address.
+func (s *Server) Host() string {
+	host, _, _ := net.SplitHostPort(s.Listener.Addr().String())
+	return host
+}
+
+// Port returns the port the server is listening on.
+func (s *Server) Port() int {
+	_, port, _ := net.SplitHostPort(s.Listener.Addr().String())
+	ans, _ := strconv.Atoi(port)
+	return ans
+}
+
+/*
+Handle registers a handler for the given method and path pattern.
+
+Path segments in braces, such as "/policy/{id}", match any single segment
+and are made available in Request.Params.  Routes registered later take
+precedence over earlier ones, so tests can override the default behavior of
+any endpoint.
+*/
+func (s *Server) Handle(method, pattern string, h HandlerFunc) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	r := route{
+		method:   method,
+		segments: strings.Split(strings.TrimPrefix(pattern, "/"), "/"),
+		handler:  h,
+	}
+	s.routes = append([]route{r}, s.routes...)
+}
+
+// Calls returns the requests received so far.
+func (s *Server) Calls() []Call {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ans := make([]Call, len(s.calls))
+	copy(ans, s.calls)
+	return ans
+}
+
+// Count returns how many times the given method and path have been called.
+func (s *Server) Count(method, path string) int {
+	var ans int
+	for _, c := range s.Calls() {
+		if c.Method == method && c.Path == path {
+			ans++
+		}
+	}
+	return ans
+}
+
+// Token issues a new valid JSON web token.
+func (s *Server) Token() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.issueToken()
+}
+
+// ExpireTokens invalidates all issued JSON web tokens.
+func (s *Server) ExpireTokens() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.tokens = make(map[string]time.Time)
+}
+
+// NextId returns a new unique object ID.
+func (s *Server) NextId() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.nextId()
+}
+
+func (s *Server) nextId() string {
+	s.counter++
+	return fmt.Sprintf("00000000-0000-0000-0000-%012d", s.counter)
+}
+
+// issueToken returns a JWT shaped token with an "exp" claim.  The signature
+// is not meaningful, only the server's own record of the token is checked.
+func (s *Server) issueToken() string {
+	s.counter++
+	exp := time.Now().Add(s.TokenLifetime)
+	claims, _ := json.Marshal(map[string]interface{}{
+		"exp":      exp.Unix(),
+		"username": s.Username,
+		"jti":      s.counter,
+	})
+
+	enc := base64.RawURLEncoding
+	token := strings.Join([]string{
+		enc.EncodeToString([]byte(`{"alg":"none","typ":"JWT"}`)),
+		enc.EncodeToString(claims),
+		enc.EncodeToString([]byte("fake")),
+	}, ".")
+	s.tokens[token] = exp
+
+	return token
+}
+
+func (s *Server) validToken(token string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	exp, ok := s.tokens[token]
+	return ok && time.Now().Before(exp)
+}
+
+func (s *Server) match(method, path string) (HandlerFunc, map[string]string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	segments := strings.Split(strings.TrimPrefix(path, "/"), "/")
+	for _, r := range s.routes {
+		if r.method != method || len(r.segments) != len(segments) {
+			continue
+		}
+
+		params := make(map[string]string)
+		ok := true
+		for i, seg := range r.segments {
+			if strings.HasPrefix(seg, "{") && strings.HasSuffix(seg, "}") {
+				params[seg[1:len(seg)-1]] = segments[i]
+			} else if seg != segments[i] {
+				ok = false
+				break
+			}
+		}
+		if ok {
+			return r.handler, params
+		}
+	}
+
+	return nil, nil
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	s.calls = append(s.calls, Call{Method: r.Method, Path: r.URL.Path, Query: r.URL.RawQuery})
+	w.Header().Set(RequestIdHeader, fmt.Sprintf("fake-request-%d", len(s.calls)))
+	s.mu.Unlock()
+
+	body, _ := ioutil.ReadAll(r.Body)
+
+	if r.URL.Path != "/login" && !s.validToken(r.Header.Get("x-redlock-auth")) {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	h, params := s.match(r.Method, r.URL.Path)
+	if h == nil {
+		writeError(w, Errorf(http.StatusNotFound, "not_found", r.URL.Path))
+		return
+	}
+
+	ans, err := h(&Request{Request: r, Params: params, Body: body})
+	if err != nil {
+		e, ok := err.(*Error)
+		if !ok {
+			e = Errorf(http.StatusInternalServerError, "internal_error", err.Error())
+		}
+		writeError(w, e)
+		return
+	}
+
+	if raw, ok := ans.([]byte); ok {
+		w.Header().Set("Content-Type", "application/octet-stream")
+		w.WriteHeader(http.StatusOK)
+		w.Write(raw)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if ans == nil {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+	b, err := json.Marshal(ans)
+	if err != nil {
+		writeError(w, Errorf(http.StatusInternalServerError, "internal_error", err.Error()))
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write(b)
+}
+
+func writeError(w http.ResponseWriter, e *Error) {
+	info, _ := json.Marshal([]map[string]interface{}{{
+		"i18nKey":  e.Key,
+		"severity": "error",
+		"subject":  e.Subject,
+	}})
+	for key, vals := range e.Header {
+		for _, v := range vals {
+			w.Header().Add(key, v)
+		}
+	}
+	w.Header().Set(StatusHeader, string(info))
+	w.WriteHeader(e.StatusCode)
+}
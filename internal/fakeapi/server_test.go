@@ -0,0 +1,160 @@
+package fakeapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	pc "github.com/paloaltonetworks/prisma-cloud-go"
+	"github.com/paloaltonetworks/prisma-cloud-go/rql/search"
+)
+
+func post(t *testing.T, s *Server, path string, body interface{}) *http.Response {
+	b, _ := json.Marshal(body)
+	req, _ := http.NewRequest("POST", s.URL+path, bytes.NewReader(b))
+	req.Header.Set("x-redlock-auth", s.Token())
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("Error in POST %s: %s", path, err)
+	}
+	return resp
+}
+
+func TestUnauthorized(t *testing.T) {
+	s := New()
+	defer s.Close()
+
+	resp, err := http.Get(s.URL + "/cloud/group")
+	if err != nil {
+		t.Fatalf("Error in GET: %s", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("Status is %d, expected %d", resp.StatusCode, http.StatusUnauthorized)
+	}
+}
+
+func TestErrorHeader(t *testing.T) {
+	s := New()
+	defer s.Close()
+
+	resp := post(t, s, "/search/config", map[string]string{"query": ""})
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("Status is %d, expected %d", resp.StatusCode, http.StatusBadRequest)
+	}
+
+	var errs []pc.PrismaCloudError
+	if err := json.Unmarshal([]byte(resp.Header.Get(StatusHeader)), &errs); err != nil {
+		t.Fatalf("Error parsing %s header: %s", StatusHeader, err)
+	}
+	if len(errs) != 1 || errs[0].Message != "invalid_rql" {
+		t.Fatalf("Errors are %v, expected invalid_rql", errs)
+	}
+}
+
+func TestSearchRoutes(t *testing.T) {
+	s := New()
+	defer s.Close()
+
+	for searchType, path := range map[string][]string{
+		"config":  append(append([]string{}, search.BaseSuffix...), search.ConfigSuffix...),
+		"event":   append(append([]string{}, search.BaseSuffix...), search.EventSuffix...),
+		"iam":     search.IamSuffix,
+		"asset":   append(append([]string{}, search.BaseSuffix...), search.AssetSuffix...),
+		"network": append(append([]string{}, search.BaseSuffix...), search.NetworkSuffix...),
+	} {
+		resp := post(t, s, "/"+strings.Join(path, "/"), map[string]string{"query": "q"})
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Errorf("Status of %s search is %d, expected %d", searchType, resp.StatusCode, http.StatusOK)
+		}
+	}
+}
+
+func TestAlertPaging(t *testing.T) {
+	s := New()
+	defer s.Close()
+
+	for _, status := range []string{"open", "dismissed", "open", "open"} {
+		s.Alerts.Put(map[string]interface{}{"status": status})
+	}
+
+	var ids []string
+	token := ""
+	for {
+		resp := post(t, s, "/v2/alert", map[string]interface{}{
+			"limit":     2,
+			"pageToken": token,
+			"filters": []map[string]string{
+				{"name": "alert.status", "operator": "=", "value": "open"},
+			},
+		})
+		var page struct {
+			Total int `json:"totalRows"`
+			Items []struct {
+				Id string `json:"id"`
+			} `json:"items"`
+			Next string `json:"nextPageToken"`
+		}
+		json.NewDecoder(resp.Body).Decode(&page)
+		resp.Body.Close()
+
+		if page.Total != 3 {
+			t.Fatalf("Total is %d, expected 3", page.Total)
+		}
+		for _, item := range page.Items {
+			ids = append(ids, item.Id)
+		}
+		if page.Next == "" {
+			break
+		}
+		token = page.Next
+	}
+
+	if len(ids) != 3 {
+		t.Fatalf("Got %d alerts, expected 3", len(ids))
+	}
+}
+
+func TestAlertDismiss(t *testing.T) {
+	s := New()
+	defer s.Close()
+
+	a := s.Alerts.Put(map[string]interface{}{"status": "open"})
+	b := s.Alerts.Put(map[string]interface{}{"status": "open"})
+
+	resp := post(t, s, "/alert/dismiss", map[string]interface{}{
+		"alerts":             []string{a},
+		"dismissalNote":      "accepted",
+		"dismissalTimeRange": map[string]interface{}{"type": "relative"},
+	})
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Status is %d, expected %d", resp.StatusCode, http.StatusOK)
+	}
+	if obj, _ := s.Alerts.Get(a); obj["status"] != "snoozed" {
+		t.Errorf("Alert status is %v, expected snoozed", obj["status"])
+	}
+	if obj, _ := s.Alerts.Get(b); obj["status"] != "open" {
+		t.Errorf("Alert not listed has status %v", obj["status"])
+	}
+
+	resp = post(t, s, "/alert/reopen", map[string]interface{}{"alerts": []string{a}})
+	resp.Body.Close()
+	obj, _ := s.Alerts.Get(a)
+	if obj["status"] != "open" {
+		t.Errorf("Alert status is %v after reopen", obj["status"])
+	}
+	if history, _ := obj["history"].([]interface{}); len(history) != 2 {
+		t.Errorf("Got %d history entries, expected 2", len(history))
+	}
+
+	resp = post(t, s, "/alert/dismiss", map[string]interface{}{"alerts": []string{a}})
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("Dismissing without a note returned status %d", resp.StatusCode)
+	}
+}
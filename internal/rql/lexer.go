@@ -0,0 +1,190 @@
+package rql
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// Kind is the kind of a token.
+type Kind int
+
+// Token kinds.
+const (
+	EOF Kind = iota
+
+	// Word is anything that is not one of the other kinds: keywords, field
+	// paths such as "json.rule" or "tags[*].key", numbers, CIDRs and so on.
+	Word
+
+	// String is a single or double quoted string, with the quotes.
+	String
+
+	// Operator is one of "=", "==", "!=", "<>", "<", "<=", ">" or ">=".
+	Operator
+
+	// Punct is one of "(", ")", "[", "]", "{", "}", "," or ";".
+	Punct
+)
+
+func (k Kind) String() string {
+	switch k {
+	case EOF:
+		return "end of query"
+	case Word:
+		return "word"
+	case String:
+		return "string"
+	case Operator:
+		return "operator"
+	case Punct:
+		return "punctuation"
+	}
+	return fmt.Sprintf("Kind(%d)", int(k))
+}
+
+// Pos is a position in a query.  Line and Column start at 1, and columns
+// count characters, not bytes.
+type Pos struct {
+	Offset int
+	Line   int
+	Column int
+}
+
+// Token is a lexical token of a query.
+type Token struct {
+	Kind Kind
+	Text string
+	Pos  Pos
+}
+
+// Is returns true if the token is a word that matches the given keyword,
+// ignoring case.
+func (t Token) Is(keyword string) bool {
+	return t.Kind == Word && strings.EqualFold(t.Text, keyword)
+}
+
+func (t Token) String() string {
+	if t.Kind == EOF {
+		return t.Kind.String()
+	}
+	return fmt.Sprintf("%q", t.Text)
+}
+
+// Error is a syntax error in a query.
+type Error struct {
+	Pos Pos
+	Msg string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("line %d, column %d: %s", e.Pos.Line, e.Pos.Column, e.Msg)
+}
+
+func errorf(pos Pos, format string, a ...interface{}) *Error {
+	return &Error{Pos: pos, Msg: fmt.Sprintf(format, a...)}
+}
+
+/*
+Lex splits a query into tokens, ending with an EOF token.
+
+The lexer is deliberately lenient: RQL has many embedded grammars (JSON rules,
+functions, CIDRs, wildcards), so any run of characters that is not a string,
+an operator or punctuation is a single word.  The only lexical error is an
+unterminated string.
+*/
+func Lex(query string) ([]Token, error) {
+	l := lexer{src: []rune(query), pos: Pos{Line: 1, Column: 1}}
+	var ans []Token
+
+	for {
+		l.skipSpace()
+		if l.i >= len(l.src) {
+			ans = append(ans, Token{Kind: EOF, Pos: l.pos})
+			return ans, nil
+		}
+
+		tok, err := l.next()
+		if err != nil {
+			return nil, err
+		}
+		ans = append(ans, tok)
+	}
+}
+
+type lexer struct {
+	src []rune
+	i   int
+	pos Pos
+}
+
+func (l *lexer) peek(n int) rune {
+	if l.i+n < len(l.src) {
+		return l.src[l.i+n]
+	}
+	return 0
+}
+
+func (l *lexer) advance() rune {
+	r := l.src[l.i]
+	l.i++
+	l.pos.Offset += len(string(r))
+	if r == '\n' {
+		l.pos.Line++
+		l.pos.Column = 1
+	} else {
+		l.pos.Column++
+	}
+	return r
+}
+
+func (l *lexer) skipSpace() {
+	for l.i < len(l.src) && unicode.IsSpace(l.src[l.i]) {
+		l.advance()
+	}
+}
+
+func (l *lexer) next() (Token, error) {
+	start := l.pos
+	var b strings.Builder
+
+	switch r := l.peek(0); {
+	case r == '\'' || r == '"':
+		b.WriteRune(l.advance())
+		for {
+			if l.i >= len(l.src) {
+				return Token{}, errorf(start, "unterminated string")
+			}
+			c := l.advance()
+			b.WriteRune(c)
+			if c == '\\' && l.i < len(l.src) {
+				b.WriteRune(l.advance())
+			} else if c == r {
+				return Token{Kind: String, Text: b.String(), Pos: start}, nil
+			}
+		}
+	case isPunct(r):
+		b.WriteRune(l.advance())
+		return Token{Kind: Punct, Text: b.String(), Pos: start}, nil
+	case r == '=' || r == '<' || r == '>' || (r == '!' && l.peek(1) == '='):
+		b.WriteRune(l.advance())
+		if c := l.peek(0); c == '=' || (r == '<' && c == '>') {
+			b.WriteRune(l.advance())
+		}
+		return Token{Kind: Operator, Text: b.String(), Pos: start}, nil
+	}
+
+	for l.i < len(l.src) {
+		r := l.peek(0)
+		if unicode.IsSpace(r) || isPunct(r) || r == '\'' || r == '"' || r == '=' || r == '<' || r == '>' || (r == '!' && l.peek(1) == '=') {
+			break
+		}
+		b.WriteRune(l.advance())
+	}
+
+	return Token{Kind: Word, Text: b.String(), Pos: start}, nil
+}
+
+func isPunct(r rune) bool {
+	return strings.ContainsRune("()[]{},;", r)
+}
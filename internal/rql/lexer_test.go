@@ -0,0 +1,69 @@
+package rql
+
+import (
+	"testing"
+)
+
+func TestLex(t *testing.T) {
+	toks, err := Lex("config from cloud.resource where\n  tags[*].key != \"it's\" AND port >= 22;")
+	if err != nil {
+		t.Fatalf("Error lexing: %s", err)
+	}
+
+	want := []Token{
+		{Word, "config", Pos{0, 1, 1}},
+		{Word, "from", Pos{7, 1, 8}},
+		{Word, "cloud.resource", Pos{12, 1, 13}},
+		{Word, "where", Pos{27, 1, 28}},
+		{Word, "tags", Pos{35, 2, 3}},
+		{Punct, "[", Pos{39, 2, 7}},
+		{Word, "*", Pos{40, 2, 8}},
+		{Punct, "]", Pos{41, 2, 9}},
+		{Word, ".key", Pos{42, 2, 10}},
+		{Operator, "!=", Pos{47, 2, 15}},
+		{String, `"it's"`, Pos{50, 2, 18}},
+		{Word, "AND", Pos{57, 2, 25}},
+		{Word, "port", Pos{61, 2, 29}},
+		{Operator, ">=", Pos{66, 2, 34}},
+		{Word, "22", Pos{69, 2, 37}},
+		{Punct, ";", Pos{71, 2, 39}},
+		{EOF, "", Pos{72, 2, 40}},
+	}
+
+	if len(toks) != len(want) {
+		t.Fatalf("Got %d tokens, expected %d: %v", len(toks), len(want), toks)
+	}
+	for i := range want {
+		if toks[i] != want[i] {
+			t.Errorf("Token %d is %#v, expected %#v", i, toks[i], want[i])
+		}
+	}
+}
+
+func TestLexEscapedQuote(t *testing.T) {
+	toks, err := Lex(`a = 'it\'s' ok`)
+	if err != nil {
+		t.Fatalf("Error lexing: %s", err)
+	}
+	if len(toks) != 5 || toks[2].Text != `'it\'s'` || toks[3].Text != "ok" {
+		t.Errorf("Got tokens %v", toks)
+	}
+}
+
+func TestLexUnterminatedString(t *testing.T) {
+	_, err := Lex("config from cloud.resource where\napi.name = 'aws-ec2")
+	if err == nil {
+		t.Fatalf("Expected an error")
+	}
+
+	e, ok := err.(*Error)
+	if !ok {
+		t.Fatalf("Error is a %T, expected *Error", err)
+	}
+	if e.Pos.Line != 2 || e.Pos.Column != 12 {
+		t.Errorf("Error is at line %d, column %d, expected line 2, column 12", e.Pos.Line, e.Pos.Column)
+	}
+	if got := err.Error(); got != "line 2, column 12: unterminated string" {
+		t.Errorf("Error is %q", got)
+	}
+}
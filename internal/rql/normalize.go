@@ -0,0 +1,98 @@
+package rql
+
+import (
+	"strings"
+)
+
+// keywords are the RQL words that are not case sensitive.
+var keywords = map[string]bool{
+	"addcolumn": true,
+	"all":       true,
+	"and":       true,
+	"any":       true,
+	"as":        true,
+	"asset":     true,
+	"config":    true,
+	"contains":  true,
+	"does":      true,
+	"empty":     true,
+	"ends":      true,
+	"equals":    true,
+	"event":     true,
+	"exists":    true,
+	"false":     true,
+	"filter":    true,
+	"from":      true,
+	"in":        true,
+	"is":        true,
+	"member":    true,
+	"network":   true,
+	"none":      true,
+	"not":       true,
+	"of":        true,
+	"or":        true,
+	"show":      true,
+	"size":      true,
+	"starts":    true,
+	"true":      true,
+	"where":     true,
+	"with":      true,
+}
+
+/*
+Normalize returns a canonical form of a query, in which tokens are separated
+by a single space, keywords are lower case and strings are single quoted.
+
+Two queries with the same canonical form only differ in formatting.
+*/
+func Normalize(query string) (string, error) {
+	toks, err := Lex(query)
+	if err != nil {
+		return "", err
+	}
+
+	parts := make([]string, 0, len(toks))
+	for _, t := range toks {
+		switch t.Kind {
+		case EOF:
+		case Word:
+			if lower := strings.ToLower(t.Text); keywords[lower] {
+				parts = append(parts, lower)
+			} else {
+				parts = append(parts, t.Text)
+			}
+		case String:
+			parts = append(parts, normalizeString(t.Text))
+		default:
+			parts = append(parts, t.Text)
+		}
+	}
+
+	return strings.Join(parts, " "), nil
+}
+
+// normalizeString single quotes a quoted string.
+func normalizeString(s string) string {
+	s = s[1 : len(s)-1]
+	s = strings.NewReplacer(`\'`, `'`, `\"`, `"`).Replace(s)
+	return "'" + strings.ReplaceAll(s, "'", `\'`) + "'"
+}
+
+// Equivalent returns true if two queries only differ in formatting.  Queries
+// that do not lex are only equivalent if they are identical.
+func Equivalent(a, b string) bool {
+	if a == b {
+		return true
+	}
+
+	na, err := Normalize(a)
+	if err != nil {
+		return false
+	}
+	nb, err := Normalize(b)
+	if err != nil {
+		return false
+	}
+
+	return na == nb
+}
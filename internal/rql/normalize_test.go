@@ -0,0 +1,54 @@
+package rql
+
+import (
+	"testing"
+)
+
+func TestNormalize(t *testing.T) {
+	got, err := Normalize("CONFIG From cloud.resource\n\twhere api.name=\"aws-iam-get-account-summary\" AND json.rule = AccountMFAEnabled Is False")
+	if err != nil {
+		t.Fatalf("Error normalizing: %s", err)
+	}
+
+	want := "config from cloud.resource where api.name = 'aws-iam-get-account-summary' and json.rule = AccountMFAEnabled is false"
+	if got != want {
+		t.Errorf("Got %q, expected %q", got, want)
+	}
+}
+
+func TestEquivalent(t *testing.T) {
+	for _, tc := range []struct {
+		a, b string
+		want bool
+	}{
+		{
+			"config from cloud.resource where api.name = 'a'",
+			"config  from cloud.resource\nwhere api.name = \"a\"\n",
+			true,
+		},
+		{
+			`event from cloud.audit_logs where operation = "it's"`,
+			`event from cloud.audit_logs where operation = 'it\'s'`,
+			true,
+		},
+		{
+			"config from cloud.resource where json.rule = Tags[*].key Exists",
+			"config from cloud.resource where json.rule = tags[*].key exists",
+			false,
+		},
+		{
+			"config from cloud.resource where api.name = 'a'",
+			"config from cloud.resource where api.name = 'A'",
+			false,
+		},
+		{
+			"config from cloud.resource where api.name = 'a",
+			"config from cloud.resource where api.name = 'a ",
+			false,
+		},
+	} {
+		if got := Equivalent(tc.a, tc.b); got != tc.want {
+			t.Errorf("Equivalent(%q, %q) is %t, expected %t", tc.a, tc.b, got, tc.want)
+		}
+	}
+}
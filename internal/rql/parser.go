@@ -0,0 +1,328 @@
+/*
+Package rql checks Prisma Cloud RQL queries for syntax errors.
+
+It does not aim to understand every RQL construct.  Instead it checks the
+parts that all queries share, so that mistakes are reported with their line
+and column when Terraform plans instead of by the API during the apply:
+
+	config from cloud.resource where ...
+	config from iam where ...
+	config from network where ...
+	event from cloud.audit_logs where ...
+	network from vpc.flow_record where ...
+	asset where ...
+
+Conditions are checked for balanced parentheses and brackets, and for
+boolean and comparison operators that are missing an operand.  Multi-part
+config queries joined with "as", "filter" and "show" are supported.
+*/
+package rql
+
+import (
+	"strings"
+)
+
+// Search types, as used by the RQL search API.
+const (
+	SearchConfig  = "config"
+	SearchEvent   = "event"
+	SearchNetwork = "network"
+	SearchIam     = "iam"
+	SearchAsset   = "asset"
+)
+
+// SearchTypes are the valid search types.
+var SearchTypes = []string{SearchConfig, SearchEvent, SearchNetwork, SearchIam, SearchAsset}
+
+// sources maps "<keyword> from <source>" to the search type it selects.
+// Sources not listed here search the keyword's own type.
+var sources = map[string]map[string]string{
+	"config": {
+		"cloud.resource": SearchConfig,
+		"iam":            SearchIam,
+		"network":        SearchNetwork,
+	},
+	"event": {
+		"cloud.audit_logs": SearchEvent,
+	},
+	"network": {
+		"vpc.flow_record": SearchNetwork,
+	},
+}
+
+// Query is a parsed query.
+type Query struct {
+	// SearchType is the search type of the first part of the query.
+	SearchType string
+
+	// Source is the data source of the first part of the query, such as
+	// "cloud.resource", if it has one.
+	Source string
+
+	// Tokens are the tokens of the query, without the final EOF.
+	Tokens []Token
+}
+
+// Parse checks the syntax of the given query.  Errors are returned as an
+// *Error.
+func Parse(query string) (*Query, error) {
+	toks, err := Lex(query)
+	if err != nil {
+		return nil, err
+	}
+	eof := toks[len(toks)-1]
+
+	if err = checkNesting(toks); err != nil {
+		return nil, err
+	}
+
+	// Split on semicolons, which are always at the top level.  Each part
+	// is kept along with the token that ends it.
+	var parts [][]Token
+	var ends []Token
+	start := 0
+	for i, t := range toks {
+		if t.Kind == EOF || (t.Kind == Punct && t.Text == ";") {
+			parts = append(parts, toks[start:i])
+			ends = append(ends, t)
+			start = i + 1
+		}
+	}
+
+	var ans *Query
+	for i, part := range parts {
+		if len(part) == 0 {
+			switch {
+			case i == 0 && len(parts) == 1:
+				return nil, errorf(eof.Pos, "query is empty")
+			case i > 0 && i == len(parts)-1:
+				// Trailing semicolon.
+				continue
+			}
+			return nil, errorf(ends[i].Pos, "expected a query before \";\"")
+		}
+
+		q, err := parsePart(part, ends[i], i == 0)
+		if err != nil {
+			return nil, err
+		}
+		if i == 0 {
+			ans = q
+		}
+	}
+
+	ans.Tokens = toks[:len(toks)-1]
+	return ans, nil
+}
+
+// parsePart parses one part of a query, ended by the given token.
+func parsePart(part []Token, end Token, first bool) (*Query, error) {
+	head := part[0]
+
+	switch {
+	case head.Is("config") || head.Is("event") || head.Is("network"):
+		keyword := strings.ToLower(head.Text)
+		if len(part) < 2 || !part[1].Is("from") {
+			return nil, errorf(at(part, 1, end).Pos, "expected \"from\" after %q, found %s", head.Text, at(part, 1, end))
+		}
+		if len(part) < 3 || part[2].Kind != Word {
+			return nil, errorf(at(part, 2, end).Pos, "expected a data source after \"from\", found %s", at(part, 2, end))
+		}
+		source := strings.ToLower(part[2].Text)
+		searchType := keyword
+		if st, ok := sources[keyword][source]; ok {
+			searchType = st
+		}
+
+		rest := part[3:]
+		if len(rest) > 0 {
+			if !rest[0].Is("where") {
+				return nil, errorf(rest[0].Pos, "expected \"where\" after %q, found %s", part[2].Text, rest[0])
+			}
+			if err := checkCondition(rest[1:], rest[0], end); err != nil {
+				return nil, err
+			}
+		}
+
+		return &Query{SearchType: searchType, Source: part[2].Text}, nil
+	case head.Is("asset"):
+		rest := part[1:]
+		if len(rest) > 0 && rest[0].Is("where") {
+			if err := checkCondition(rest[1:], rest[0], end); err != nil {
+				return nil, err
+			}
+		}
+		return &Query{SearchType: SearchAsset}, nil
+	case !first && head.Is("filter"):
+		if len(part) < 2 || part[1].Kind != String {
+			return nil, errorf(at(part, 1, end).Pos, "expected a quoted expression after \"filter\", found %s", at(part, 1, end))
+		}
+		return nil, nil
+	case !first && (head.Is("show") || head.Is("addcolumn")):
+		if len(part) < 2 {
+			return nil, errorf(end.Pos, "expected a name after %q", head.Text)
+		}
+		return nil, nil
+	}
+
+	expected := "config, event, network or asset"
+	if !first {
+		expected = "config, event, network, filter or show"
+	}
+	return nil, errorf(head.Pos, "unknown search %s, expected %s", head, expected)
+}
+
+// at returns part[i], or end if part is too short.
+func at(part []Token, i int, end Token) Token {
+	if i < len(part) {
+		return part[i]
+	}
+	return end
+}
+
+// closers maps opening punctuation to its closing counterpart.
+var closers = map[string]string{"(": ")", "[": "]", "{": "}"}
+
+// checkNesting checks that parentheses, brackets and braces are balanced,
+// and that semicolons are not inside any of them.
+func checkNesting(toks []Token) error {
+	var stack []Token
+
+	for _, t := range toks {
+		switch {
+		case t.Kind == EOF:
+			if len(stack) > 0 {
+				open := stack[len(stack)-1]
+				return errorf(open.Pos, "%s is never closed", open)
+			}
+		case t.Kind != Punct:
+		case closers[t.Text] != "":
+			stack = append(stack, t)
+		case t.Text == ")" || t.Text == "]" || t.Text == "}":
+			if len(stack) == 0 {
+				return errorf(t.Pos, "unexpected %s, nothing to close", t)
+			}
+			open := stack[len(stack)-1]
+			if closers[open.Text] != t.Text {
+				return errorf(t.Pos, "unexpected %s, expected %q to close %s at line %d, column %d", t, closers[open.Text], open, open.Pos.Line, open.Pos.Column)
+			}
+			stack = stack[:len(stack)-1]
+		case t.Text == ";":
+			if len(stack) > 0 {
+				open := stack[len(stack)-1]
+				return errorf(t.Pos, "unexpected \";\" before %s at line %d, column %d is closed", open, open.Pos.Line, open.Pos.Column)
+			}
+		}
+	}
+
+	return nil
+}
+
+// booleanOperators are the words joining conditions.
+var booleanOperators = []string{"and", "or"}
+
+func isBoolean(t Token) bool {
+	for _, op := range booleanOperators {
+		if t.Is(op) {
+			return true
+		}
+	}
+	return false
+}
+
+// endsOperand returns true if the token can be the last token of an operand.
+func endsOperand(t Token) bool {
+	switch t.Kind {
+	case Word:
+		return !isBoolean(t)
+	case String:
+		return true
+	case Punct:
+		return t.Text == ")" || t.Text == "]" || t.Text == "}"
+	}
+	return false
+}
+
+// startsOperand returns true if the token can be the first token of an
+// operand.
+func startsOperand(t Token) bool {
+	switch t.Kind {
+	case Word:
+		return !isBoolean(t)
+	case String:
+		return true
+	case Punct:
+		return t.Text == "(" || t.Text == "[" || t.Text == "{"
+	}
+	return false
+}
+
+// checkCondition checks the condition following "where".
+func checkCondition(toks []Token, where, end Token) error {
+	if len(toks) == 0 {
+		return errorf(end.Pos, "expected a condition after %s, found %s", where, end)
+	}
+
+	for i, t := range toks {
+		if t.Kind != Operator && !isBoolean(t) && !(t.Kind == Punct && t.Text == ",") {
+			continue
+		}
+
+		prev := where
+		if i > 0 {
+			prev = toks[i-1]
+		}
+		next := at(toks, i+1, end)
+
+		what := "a value"
+		if isBoolean(t) {
+			what = "a condition"
+		}
+		if i == 0 || !endsOperand(prev) {
+			return errorf(t.Pos, "expected %s before %s, found %s", what, strings.ToUpper(t.Text), prev)
+		}
+		if !startsOperand(next) {
+			return errorf(next.Pos, "expected %s after %s, found %s", what, strings.ToUpper(t.Text), next)
+		}
+	}
+
+	return nil
+}
+
+// SearchTypesFor returns the search types that a policy of the given type
+// may use, or nil if the policy type does not use RQL.
+func SearchTypesFor(policyType string) []string {
+	switch policyType {
+	case "config":
+		return []string{SearchConfig}
+	case "audit_event":
+		return []string{SearchEvent}
+	case "network":
+		return []string{SearchNetwork}
+	case "iam":
+		return []string{SearchIam}
+	}
+	return nil
+}
+
+/*
+LooksLikeQuery returns true if s appears to be an RQL query rather than, for
+example, the ID of a saved search.
+
+This is the case if s starts with a search keyword or if its second word is
+"from", so that misspelled search types are still reported.
+*/
+func LooksLikeQuery(s string) bool {
+	words := strings.Fields(s)
+	if len(words) == 0 {
+		return false
+	}
+
+	for _, kw := range []string{"config", "event", "network", "asset"} {
+		if strings.EqualFold(words[0], kw) {
+			return true
+		}
+	}
+
+	return len(words) > 1 && strings.EqualFold(words[1], "from")
+}
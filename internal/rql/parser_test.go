@@ -0,0 +1,135 @@
+package rql
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	for _, tc := range []struct {
+		query      string
+		searchType string
+		source     string
+	}{
+		{
+			"config from cloud.resource where cloud.type = 'aws' AND api.name = 'aws-ec2-describe-security-groups' AND json.rule = ipPermissions[?any((ipRanges[*] contains 0.0.0.0/0 or ipv6Ranges[*].cidrIpv6 contains ::/0) and (fromPort == 22 or toPort == 22))] exists",
+			SearchConfig, "cloud.resource",
+		},
+		{
+			"CONFIG FROM cloud.resource WHERE api.name = \"azure-storage-account-list\" AND json.rule = properties.supportsHttpsTrafficOnly is false",
+			SearchConfig, "cloud.resource",
+		},
+		{
+			"config from cloud.resource",
+			SearchConfig, "cloud.resource",
+		},
+		{
+			"event from cloud.audit_logs where operation IN ( 'DeleteTrail', 'StopLogging' )",
+			SearchEvent, "cloud.audit_logs",
+		},
+		{
+			"network from vpc.flow_record where source.publicnetwork IN ( 'Internet IPs' , 'Suspicious IPs' ) and dest.resource IN ( resource where role = 'Database' )",
+			SearchNetwork, "vpc.flow_record",
+		},
+		{
+			"config from network where source.network = '0.0.0.0/0' and address.match.criteria = 'full_match' and protocol.ports in ( 'tcp/22' )",
+			SearchNetwork, "network",
+		},
+		{
+			"config from iam where source.cloud.service.name = 'ec2' and grantedby.cloud.policy.type != 'AWS Managed Policy'",
+			SearchIam, "iam",
+		},
+		{
+			"asset where cloud.type = 'aws'",
+			SearchAsset, "",
+		},
+		{
+			`config from cloud.resource where api.name = 'aws-ec2-describe-instances' as X;
+config from cloud.resource where api.name = 'aws-ec2-describe-security-groups' as Y;
+filter '$.X.securityGroups[*].groupId == $.Y.groupId';
+show X;`,
+			SearchConfig, "cloud.resource",
+		},
+	} {
+		q, err := Parse(tc.query)
+		if err != nil {
+			t.Errorf("Error parsing %q: %s", tc.query, err)
+			continue
+		}
+		if q.SearchType != tc.searchType {
+			t.Errorf("%q: search type is %q, expected %q", tc.query, q.SearchType, tc.searchType)
+		}
+		if q.Source != tc.source {
+			t.Errorf("%q: source is %q, expected %q", tc.query, q.Source, tc.source)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, tc := range []struct {
+		query  string
+		line   int
+		column int
+		msg    string
+	}{
+		{"", 1, 1, "query is empty"},
+		{"confg from cloud.resource", 1, 1, `unknown search "confg"`},
+		{"config cloud.resource where a = 'b'", 1, 8, `expected "from" after "config"`},
+		{"config from", 1, 12, "expected a data source"},
+		{"config from cloud.resource api.name = 'b'", 1, 28, `expected "where" after "cloud.resource"`},
+		{"config from cloud.resource where ", 1, 34, "expected a condition"},
+		{"config from cloud.resource where\n  a = 'b' and", 2, 14, "expected a condition after AND"},
+		{"config from cloud.resource where a = 'b' or or c = 'd'", 1, 45, "expected a condition after OR"},
+		{"config from cloud.resource where and a = 'b'", 1, 34, "expected a condition before AND"},
+		{"config from cloud.resource where a = = 'b'", 1, 38, "expected a value after ="},
+		{"config from cloud.resource where a in ('b', )", 1, 45, "expected a value after ,"},
+		{"config from cloud.resource where (a = 'b'", 1, 34, `"(" is never closed`},
+		{"config from cloud.resource where a = 'b')", 1, 41, `unexpected ")"`},
+		{"config from cloud.resource where json.rule = x[?any(y == 1])", 1, 59, `unexpected "]"`},
+		{"config from cloud.resource where a = 'b;", 1, 38, "unterminated string"},
+		{"config from cloud.resource where a = 'b';; show X", 1, 42, `expected a query before ";"`},
+		{"config from cloud.resource where a = 'b' as X; display X", 1, 48, `unknown search "display"`},
+		{"config from cloud.resource where a = 'b' as X; filter $.X", 1, 55, "expected a quoted expression"},
+	} {
+		_, err := Parse(tc.query)
+		if err == nil {
+			t.Errorf("%q: expected an error", tc.query)
+			continue
+		}
+		e, ok := err.(*Error)
+		if !ok {
+			t.Errorf("%q: error is a %T, expected *Error", tc.query, err)
+			continue
+		}
+		if e.Pos.Line != tc.line || e.Pos.Column != tc.column || !strings.Contains(e.Msg, tc.msg) {
+			t.Errorf("%q: got %q, expected line %d, column %d: %s", tc.query, err, tc.line, tc.column, tc.msg)
+		}
+	}
+}
+
+func TestLooksLikeQuery(t *testing.T) {
+	for _, tc := range []struct {
+		s    string
+		want bool
+	}{
+		{"config from cloud.resource where a = 'b'", true},
+		{"  Event from cloud.audit_logs", true},
+		{"asset", true},
+		{"confg from cloud.resource", true},
+		{"ba3ebbd7-4f8a-4b54-9b7c-e0b7a8e5b0a1", false},
+		{"", false},
+	} {
+		if got := LooksLikeQuery(tc.s); got != tc.want {
+			t.Errorf("LooksLikeQuery(%q) is %t, expected %t", tc.s, got, tc.want)
+		}
+	}
+}
+
+func TestSearchTypesFor(t *testing.T) {
+	if got := SearchTypesFor("audit_event"); len(got) != 1 || got[0] != SearchEvent {
+		t.Errorf("Search types for audit_event are %v", got)
+	}
+	if got := SearchTypesFor("anomaly"); got != nil {
+		t.Errorf("Search types for anomaly are %v, expected none", got)
+	}
+}
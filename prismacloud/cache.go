@@ -0,0 +1,195 @@
+package prismacloud
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+/*
+cachingTransport is an opt-in read-through cache of collection listings.
+
+Functions such as policy.Identify or group.Identify fetch a whole collection
+to look up a single name, so a plan with many resources of the same type
+makes the same list call over and over.  Successful GET responses that are
+JSON arrays, which is what the API returns for collection listings, are kept
+for the configured TTL, keyed by path and query.
+
+Any mutating call drops the cached entries for the collection it touches, and
+for the collections that list the same objects, and these are not cached
+again until the TTL has passed without further changes, so that polling for
+eventually consistent results sees fresh data.
+*/
+type cachingTransport struct {
+	next http.RoundTripper
+	ttl  time.Duration
+
+	mu       sync.Mutex
+	entries  map[string]*cacheEntry
+	inflight map[string]*cacheCall
+	dirty    map[string]time.Time
+}
+
+type cacheEntry struct {
+	collection string
+	expires    time.Time
+	status     int
+	header     http.Header
+	body       []byte
+}
+
+type cacheCall struct {
+	done  chan struct{}
+	entry *cacheEntry
+}
+
+func newCachingTransport(next http.RoundTripper, ttl time.Duration) *cachingTransport {
+	return &cachingTransport{
+		next:     next,
+		ttl:      ttl,
+		entries:  make(map[string]*cacheEntry),
+		inflight: make(map[string]*cacheCall),
+		dirty:    make(map[string]time.Time),
+	}
+}
+
+func (t *cachingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	coll := cacheCollection(req.URL.Path)
+
+	if req.Method != http.MethodGet {
+		resp, err := t.next.RoundTrip(req)
+		t.invalidate(cacheRelatedCollections(coll))
+		return resp, err
+	}
+
+	key := req.URL.Path + "?" + req.URL.RawQuery
+	now := time.Now()
+
+	t.mu.Lock()
+	if until, ok := t.dirty[coll]; ok && now.Before(until) {
+		t.mu.Unlock()
+		return t.next.RoundTrip(req)
+	}
+	if e, ok := t.entries[key]; ok && now.Before(e.expires) {
+		t.mu.Unlock()
+		return e.response(req), nil
+	}
+	if c, ok := t.inflight[key]; ok {
+		t.mu.Unlock()
+		select {
+		case <-c.done:
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		}
+		if c.entry != nil {
+			return c.entry.response(req), nil
+		}
+		return t.next.RoundTrip(req)
+	}
+	c := &cacheCall{done: make(chan struct{})}
+	t.inflight[key] = c
+	t.mu.Unlock()
+
+	resp, err := t.next.RoundTrip(req)
+	if err == nil && resp.StatusCode == http.StatusOK {
+		var body []byte
+		body, err = ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+		if err == nil && isJsonArray(body) {
+			c.entry = &cacheEntry{
+				collection: coll,
+				expires:    time.Now().Add(t.ttl),
+				status:     resp.StatusCode,
+				header:     resp.Header.Clone(),
+				body:       body,
+			}
+		}
+	}
+
+	t.mu.Lock()
+	delete(t.inflight, key)
+	if until, ok := t.dirty[coll]; c.entry != nil && (!ok || time.Now().After(until)) {
+		t.entries[key] = c.entry
+	}
+	t.mu.Unlock()
+	close(c.done)
+
+	return resp, err
+}
+
+// invalidate drops the cached entries of the given collections.
+func (t *cachingTransport) invalidate(colls []string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	until := time.Now().Add(t.ttl)
+	for _, coll := range colls {
+		t.dirty[coll] = until
+	}
+	for key, e := range t.entries {
+		for _, coll := range colls {
+			if e.collection == coll {
+				delete(t.entries, key)
+				break
+			}
+		}
+	}
+}
+
+func (e *cacheEntry) response(req *http.Request) *http.Response {
+	return &http.Response{
+		Status:        http.StatusText(e.status),
+		StatusCode:    e.status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        e.header.Clone(),
+		Body:          ioutil.NopCloser(bytes.NewReader(e.body)),
+		ContentLength: int64(len(e.body)),
+		Request:       req,
+	}
+}
+
+// cacheCollection returns the collection a path belongs to, which is the
+// first path element after any API version prefix.  For example, both
+// "/v2/policy" and "/policy/{id}" belong to "policy".
+func cacheCollection(path string) string {
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+	if len(parts) > 2 && parts[0] == "api" {
+		parts = parts[1:]
+	}
+	if len(parts) > 1 && len(parts[0]) > 1 && parts[0][0] == 'v' && strings.Trim(parts[0][1:], "0123456789") == "" {
+		parts = parts[1:]
+	}
+	return parts[0]
+}
+
+// cacheSharedCollections are groups of collections that list the same
+// objects under different paths.  The v2 cloud accounts, for example, are
+// written to "/cas/v1/...", deleted from "/cloud/..." and listed from
+// "/v1/cloudAccounts/...".
+var cacheSharedCollections = [][]string{
+	{"cas", "cloud", "cloudAccounts"},
+}
+
+// cacheRelatedCollections returns the collections whose listings are changed
+// by a write to the given collection, which includes the collection itself.
+func cacheRelatedCollections(coll string) []string {
+	for _, group := range cacheSharedCollections {
+		for _, v := range group {
+			if v == coll {
+				return group
+			}
+		}
+	}
+	return []string{coll}
+}
+
+func isJsonArray(b []byte) bool {
+	b = bytes.TrimSpace(b)
+	return len(b) > 0 && b[0] == '['
+}
@@ -0,0 +1,142 @@
+package prismacloud
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	pc "github.com/paloaltonetworks/prisma-cloud-go"
+	accountv2 "github.com/paloaltonetworks/prisma-cloud-go/cloud/account-v2"
+	"github.com/paloaltonetworks/prisma-cloud-go/cloud/account/group"
+	"github.com/terraform-providers/terraform-provider-prismacloud/internal/fakeapi"
+)
+
+func TestCacheIdentify(t *testing.T) {
+	s, client := testFakeClient(t, map[string]interface{}{"cache_ttl": 60})
+
+	id := s.AccountGroups.Put(map[string]interface{}{"name": "a"})
+	for i := 0; i < 5; i++ {
+		if got, err := group.Identify(client, "a"); err != nil {
+			t.Fatalf("Error in identify: %s", err)
+		} else if got != id {
+			t.Fatalf("Identified %q, expected %q", got, id)
+		}
+	}
+	if n := s.Count("GET", "/cloud/group/name"); n != 1 {
+		t.Fatalf("Listed group names %d times, expected 1", n)
+	}
+
+	// Single objects are not cached.
+	for i := 0; i < 2; i++ {
+		if _, err := group.Get(client, id); err != nil {
+			t.Fatalf("Error in get: %s", err)
+		}
+	}
+	if n := s.Count("GET", "/cloud/group/"+id); n != 2 {
+		t.Fatalf("Got group %d times, expected 2", n)
+	}
+}
+
+func TestCacheInvalidatedByMutation(t *testing.T) {
+	s, client := testFakeClient(t, map[string]interface{}{"cache_ttl": 60})
+
+	if _, err := group.Identify(client, "b"); err != pc.ObjectNotFoundError {
+		t.Fatalf("Expected not found, got %v", err)
+	}
+	if err := group.Create(client, group.Group{Name: "b"}); err != nil {
+		t.Fatalf("Error in create: %s", err)
+	}
+
+	// The collection is read fresh while it is being changed.
+	for i := 0; i < 2; i++ {
+		if _, err := group.Identify(client, "b"); err != nil {
+			t.Fatalf("Error in identify after create: %s", err)
+		}
+	}
+	if n := s.Count("GET", "/cloud/group/name"); n != 3 {
+		t.Fatalf("Listed group names %d times, expected 3", n)
+	}
+}
+
+func TestCacheV2CloudAccountCreate(t *testing.T) {
+	s, client := testFakeClient(t, map[string]interface{}{"cache_ttl": 60})
+
+	if _, err := accountv2.Identify(client, accountv2.TypeAws, "prod"); err == nil {
+		t.Fatalf("Identified the account before it was created")
+	}
+
+	// Creates go to /cas and deletes to /cloud, while the accounts are
+	// listed from /v1/cloudAccounts.
+	obj := accountv2.Aws{AccountId: "111111111111", AccountType: "account", Name: "prod", RoleArn: "arn:aws:iam::111111111111:role/prisma"}
+	if err := accountv2.Create(client, obj); err != nil {
+		t.Fatalf("Error in create: %s", err)
+	}
+	if got, err := accountv2.Identify(client, accountv2.TypeAws, "prod"); err != nil || got != obj.AccountId {
+		t.Fatalf("Identified %q, %v after create", got, err)
+	}
+
+	if err := accountv2.Delete(client, accountv2.TypeAws, obj.AccountId); err != nil {
+		t.Fatalf("Error in delete: %s", err)
+	}
+	if _, err := accountv2.Identify(client, accountv2.TypeAws, "prod"); err == nil {
+		t.Errorf("Identified the account after it was deleted")
+	}
+	if n := s.Count("GET", "/v1/cloudAccounts/awsAccounts"); n != 3 {
+		t.Errorf("Listed AWS accounts %d times, expected 3", n)
+	}
+}
+
+func TestCacheConcurrentMisses(t *testing.T) {
+	s, client := testFakeClient(t, map[string]interface{}{"cache_ttl": 60})
+
+	s.Handle("GET", "/cloud/group", func(r *fakeapi.Request) (interface{}, error) {
+		time.Sleep(20 * time.Millisecond)
+		return []interface{}{}, nil
+	})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := group.List(client); err != nil {
+				t.Errorf("Error in list: %s", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if n := s.Count("GET", "/cloud/group"); n != 1 {
+		t.Fatalf("Listed groups %d times, expected 1", n)
+	}
+}
+
+func TestCacheDisabledByDefault(t *testing.T) {
+	s, client := testFakeClient(t, nil)
+
+	for i := 0; i < 2; i++ {
+		if _, err := group.List(client); err != nil {
+			t.Fatalf("Error in list: %s", err)
+		}
+	}
+	if n := s.Count("GET", "/cloud/group"); n != 2 {
+		t.Fatalf("Listed groups %d times, expected 2", n)
+	}
+}
+
+func TestCacheCollection(t *testing.T) {
+	for path, want := range map[string]string{
+		"/policy":                    "policy",
+		"/v2/policy":                 "policy",
+		"/policy/abc":                "policy",
+		"/v2/alert/rule":             "alert",
+		"/cloud/group/name":          "cloud",
+		"/api/v1/permission":         "permission",
+		"/authz/v1/permission_group": "authz",
+		"/v":                         "v",
+	} {
+		if got := cacheCollection(path); got != want {
+			t.Errorf("cacheCollection(%q) = %q, expected %q", path, got, want)
+		}
+	}
+}
@@ -0,0 +1,141 @@
+package prismacloud
+
+import (
+	"fmt"
+
+	"golang.org/x/net/context"
+)
+
+/*
+The v2 cloud account resources are at schema version 1.
+
+Version 0 is the v2 layout as it was before versioning, which is unchanged, so
+the upgrade from version 0 also accepts state written by the v1 resources.
+This is how accounts move from prismacloud_cloud_account to
+prismacloud_cloud_account_v2 (and from prismacloud_org_cloud_account to
+prismacloud_org_cloud_account_v2) without being imported again: the state
+entry is given the v2 resource type at version 0, and the upgrade below turns
+the v1 params into their v2 equivalents on the next plan.  The upgrade is done
+offline, and keeps the credentials and group IDs of the account.
+*/
+
+// cloudAccountV1Renames maps v1 params to their v2 names, per cloud type.
+var cloudAccountV1Renames = map[string]map[string]string{
+	"gcp": {
+		"credentials_json": "credentials",
+	},
+}
+
+// orgCloudAccountV1Removed are the v1 params that have no v2 equivalent in
+// prismacloud_org_cloud_account_v2, per cloud type.
+var orgCloudAccountV1Removed = map[string][]string{
+	"aws": {"member_external_id", "member_role_name", "member_role_status"},
+}
+
+// cloudAccountV1Keys are params that only the v1 resources have, per cloud
+// type, which mark a block as written by a v1 resource.  Azure blocks have
+// none, but the v1 resources don't have environment_type.
+var cloudAccountV1Keys = map[string][]string{
+	"aws": {"member_external_id", "member_role_name", "member_role_status"},
+	"gcp": {"credentials_json"},
+}
+
+// isCloudAccountV1Block returns if a block of raw state was written by a v1
+// resource.
+func isCloudAccountV1Block(cloudType string, x map[string]interface{}) bool {
+	for _, key := range cloudAccountV1Keys[cloudType] {
+		if _, ok := x[key]; ok {
+			return true
+		}
+	}
+	if cloudType == "azure" {
+		_, ok := x["environment_type"]
+		return !ok
+	}
+	return false
+}
+
+func upgradeV2CloudAccountV0(ctx context.Context, raw map[string]interface{}, meta interface{}) (map[string]interface{}, error) {
+	for _, x := range cloudAccountBlocks(raw, "gcp") {
+		// The v1 account ID of a GCP account is the project ID.
+		if isCloudAccountV1Block("gcp", x) && x["account_type"] == "account" {
+			if s, _ := x["project_id"].(string); s == "" {
+				x["project_id"] = x["account_id"]
+			}
+		}
+	}
+
+	upgradeCloudAccountV1Blocks(raw, nil)
+
+	return raw, nil
+}
+
+func upgradeOrgV2CloudAccountV0(ctx context.Context, raw map[string]interface{}, meta interface{}) (map[string]interface{}, error) {
+	if len(cloudAccountBlocks(raw, "oci")) > 0 {
+		return nil, fmt.Errorf("OCI accounts cannot be moved to prismacloud_org_cloud_account_v2, which does not support OCI")
+	}
+	delete(raw, "oci")
+
+	for _, x := range cloudAccountBlocks(raw, "gcp") {
+		// There is no v1 equivalent to guess the required default account
+		// group from.
+		if s, _ := x["default_account_group_id"].(string); isCloudAccountV1Block("gcp", x) && s == "" {
+			return nil, fmt.Errorf("GCP account %v cannot be moved to prismacloud_org_cloud_account_v2 without a default_account_group_id, add it to the gcp block of the moved state entry", x["account_id"])
+		}
+	}
+
+	upgradeCloudAccountV1Blocks(raw, orgCloudAccountV1Removed)
+
+	return raw, nil
+}
+
+/*
+upgradeCloudAccountV1Blocks turns the cloud type blocks of raw that were
+written by a v1 resource into v2 blocks.
+
+The v1 params are renamed, the given params are removed, and v2 params that
+have no v1 equivalent are filled in with their default.  Blocks written by
+the v2 resources are left as they are.
+*/
+func upgradeCloudAccountV1Blocks(raw map[string]interface{}, removed map[string][]string) {
+	for _, cloudType := range []string{"aws", "azure", "gcp"} {
+		for _, x := range cloudAccountBlocks(raw, cloudType) {
+			if !isCloudAccountV1Block(cloudType, x) {
+				continue
+			}
+
+			for from, to := range cloudAccountV1Renames[cloudType] {
+				v, ok := x[from]
+				if !ok {
+					continue
+				}
+				if s, _ := x[to].(string); s == "" {
+					x[to] = v
+				}
+				delete(x, from)
+			}
+
+			for _, key := range removed[cloudType] {
+				delete(x, key)
+			}
+
+			if cloudType == "azure" {
+				x["environment_type"] = "azure"
+			}
+		}
+	}
+}
+
+// cloudAccountBlocks returns the blocks of the given cloud type in raw state.
+func cloudAccountBlocks(raw map[string]interface{}, cloudType string) []map[string]interface{} {
+	list, _ := raw[cloudType].([]interface{})
+
+	ans := make([]map[string]interface{}, 0, len(list))
+	for _, v := range list {
+		if x, ok := v.(map[string]interface{}); ok {
+			ans = append(ans, x)
+		}
+	}
+
+	return ans
+}
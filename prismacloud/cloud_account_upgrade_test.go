@@ -0,0 +1,253 @@
+package prismacloud
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/go-cty/cty/msgpack"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"golang.org/x/net/context"
+)
+
+// testUpgradeState upgrades raw state of the given resource type from
+// version 0 the way Terraform does.
+func testUpgradeState(t *testing.T, typeName string, raw map[string]interface{}) (cty.Value, error) {
+	t.Helper()
+
+	b, err := json.Marshal(raw)
+	if err != nil {
+		t.Fatalf("Error marshaling state: %s", err)
+	}
+
+	p := Provider()
+	resp, err := schema.NewGRPCProviderServer(p).UpgradeResourceState(context.Background(), &tfprotov5.UpgradeResourceStateRequest{
+		TypeName: typeName,
+		Version:  0,
+		RawState: &tfprotov5.RawState{JSON: b},
+	})
+	if err != nil {
+		t.Fatalf("Error upgrading state: %s", err)
+	}
+	for _, d := range resp.Diagnostics {
+		if d.Severity == tfprotov5.DiagnosticSeverityError {
+			return cty.NilVal, errors.New(d.Summary)
+		}
+	}
+
+	return msgpack.Unmarshal(resp.UpgradedState.MsgPack, p.ResourcesMap[typeName].CoreConfigSchema().ImpliedType())
+}
+
+func TestUpgradeV1CloudAccountState(t *testing.T) {
+	for _, tc := range []struct {
+		cloudType string
+		v1        map[string]interface{}
+		want      map[string]string
+	}{
+		{
+			"aws",
+			map[string]interface{}{
+				"account_id":      "123456789012",
+				"enabled":         true,
+				"external_id":     "external-id",
+				"group_ids":       []interface{}{"group-1", "group-2"},
+				"name":            "prod",
+				"role_arn":        "arn:aws:iam::123456789012:role/prisma",
+				"account_type":    "account",
+				"protection_mode": "MONITOR",
+			},
+			map[string]string{
+				"account_id":  "123456789012",
+				"external_id": "external-id",
+				"role_arn":    "arn:aws:iam::123456789012:role/prisma",
+			},
+		},
+		{
+			"azure",
+			map[string]interface{}{
+				"account_id":           "00000000-0000-0000-0000-000000000001",
+				"enabled":              true,
+				"group_ids":            []interface{}{"group-1", "group-2"},
+				"name":                 "prod",
+				"client_id":            "client-id",
+				"key":                  "secret-key",
+				"monitor_flow_logs":    true,
+				"tenant_id":            "tenant-id",
+				"service_principal_id": "principal-id",
+				"account_type":         "account",
+				"protection_mode":      "MONITOR",
+			},
+			map[string]string{
+				"key":              "secret-key",
+				"client_id":        "client-id",
+				"environment_type": "azure",
+			},
+		},
+		{
+			"gcp",
+			map[string]interface{}{
+				"account_id":               "my-project",
+				"enabled":                  true,
+				"group_ids":                []interface{}{"group-1", "group-2"},
+				"name":                     "prod",
+				"compression_enabled":      false,
+				"dataflow_enabled_project": "",
+				"flow_log_storage_bucket":  "",
+				"credentials_json":         `{"type":"service_account","project_id":"my-project"}`,
+				"account_type":             "account",
+				"protection_mode":          "MONITOR",
+			},
+			map[string]string{
+				"credentials": `{"type":"service_account","project_id":"my-project"}`,
+				"project_id":  "my-project",
+			},
+		},
+	} {
+		v1 := map[string]interface{}{
+			"id":                 TwoStringsToId(tc.cloudType, tc.v1["account_id"].(string)),
+			"disable_on_destroy": false,
+			tc.cloudType:         []interface{}{tc.v1},
+		}
+
+		val, err := testUpgradeState(t, "prismacloud_cloud_account_v2", v1)
+		if err != nil {
+			t.Fatalf("Error upgrading %s state: %s", tc.cloudType, err)
+		}
+
+		if got := val.GetAttr("id").AsString(); got != v1["id"] {
+			t.Errorf("%s: id is %q, expected %q", tc.cloudType, got, v1["id"])
+		}
+		blocks := val.GetAttr(tc.cloudType)
+		if blocks.LengthInt() != 1 {
+			t.Fatalf("%s: got %d blocks", tc.cloudType, blocks.LengthInt())
+		}
+		x := blocks.Index(cty.NumberIntVal(0))
+		for key, want := range tc.want {
+			if got := x.GetAttr(key); got.IsNull() || got.AsString() != want {
+				t.Errorf("%s: %s is %#v, expected %q", tc.cloudType, key, got, want)
+			}
+		}
+		if n := x.GetAttr("group_ids").LengthInt(); n != 2 {
+			t.Errorf("%s: got %d group IDs, expected 2", tc.cloudType, n)
+		}
+	}
+}
+
+func TestUpgradeV1OrgCloudAccountState(t *testing.T) {
+	v1 := map[string]interface{}{
+		"id":                 "aws:123456789012",
+		"disable_on_destroy": false,
+		"aws": []interface{}{map[string]interface{}{
+			"account_id":         "123456789012",
+			"enabled":            true,
+			"external_id":        "external-id",
+			"group_ids":          []interface{}{"group-1"},
+			"member_external_id": "member-external-id",
+			"member_role_name":   "member-role",
+			"member_role_status": true,
+			"name":               "org",
+			"role_arn":           "arn:aws:iam::123456789012:role/prisma",
+			"account_type":       "organization",
+			"protection_mode":    "MONITOR",
+		}},
+	}
+
+	val, err := testUpgradeState(t, "prismacloud_org_cloud_account_v2", v1)
+	if err != nil {
+		t.Fatalf("Error upgrading state: %s", err)
+	}
+	x := val.GetAttr("aws").Index(cty.NumberIntVal(0))
+	if got := x.GetAttr("role_arn").AsString(); got != "arn:aws:iam::123456789012:role/prisma" {
+		t.Errorf("role_arn is %q", got)
+	}
+	if n := x.GetAttr("group_ids").LengthInt(); n != 1 {
+		t.Errorf("Got %d group IDs, expected 1", n)
+	}
+
+	// GCP accounts need a default account group, which v1 doesn't have.
+	gcp := map[string]interface{}{
+		"id":                 "gcp:org-id",
+		"disable_on_destroy": false,
+		"gcp": []interface{}{map[string]interface{}{
+			"account_id":       "org-id",
+			"name":             "org",
+			"group_ids":        []interface{}{"group-1"},
+			"credentials_json": `{"type":"service_account"}`,
+			"account_type":     "organization",
+		}},
+	}
+	if _, err = testUpgradeState(t, "prismacloud_org_cloud_account_v2", gcp); err == nil {
+		t.Errorf("Expected an error upgrading a GCP account without a default account group")
+	}
+	gcp["gcp"].([]interface{})[0].(map[string]interface{})["default_account_group_id"] = "group-1"
+	if val, err = testUpgradeState(t, "prismacloud_org_cloud_account_v2", gcp); err != nil {
+		t.Errorf("Error upgrading a GCP account with a default account group: %s", err)
+	} else if got := val.GetAttr("gcp").Index(cty.NumberIntVal(0)).GetAttr("credentials").AsString(); got != `{"type":"service_account"}` {
+		t.Errorf("credentials is %q", got)
+	}
+
+	v1["oci"] = []interface{}{map[string]interface{}{"account_id": "ocid1.tenancy"}}
+	if _, err = testUpgradeState(t, "prismacloud_org_cloud_account_v2", v1); err == nil {
+		t.Errorf("Expected an error upgrading an OCI account")
+	}
+}
+
+func TestUpgradeV2CloudAccountStateUnchanged(t *testing.T) {
+	v2 := map[string]interface{}{
+		"id":                 "gcp:my-project",
+		"disable_on_destroy": true,
+		"gcp": []interface{}{map[string]interface{}{
+			"account_id":   "my-project",
+			"account_type": "account",
+			"name":         "prod",
+			"credentials":  `{"type":"service_account"}`,
+			"project_id":   "other-project",
+			"group_ids":    []interface{}{"group-1"},
+		}},
+	}
+
+	val, err := testUpgradeState(t, "prismacloud_cloud_account_v2", v2)
+	if err != nil {
+		t.Fatalf("Error upgrading state: %s", err)
+	}
+	if !val.GetAttr("disable_on_destroy").True() {
+		t.Errorf("disable_on_destroy was lost")
+	}
+	x := val.GetAttr("gcp").Index(cty.NumberIntVal(0))
+	if got := x.GetAttr("project_id").AsString(); got != "other-project" {
+		t.Errorf("project_id is %q, expected other-project", got)
+	}
+	if got := x.GetAttr("credentials").AsString(); got != `{"type":"service_account"}` {
+		t.Errorf("credentials is %q", got)
+	}
+
+	// Params left empty in v2 state are not filled in as if moved from v1.
+	v2 = map[string]interface{}{
+		"id":                 "azure:00000000-0000-0000-0000-000000000001",
+		"disable_on_destroy": false,
+		"azure": []interface{}{map[string]interface{}{
+			"account_id":       "00000000-0000-0000-0000-000000000001",
+			"account_type":     "account",
+			"name":             "prod",
+			"environment_type": nil,
+		}},
+		"gcp": []interface{}{map[string]interface{}{
+			"account_id":   "my-project",
+			"account_type": "account",
+			"name":         "prod",
+			"credentials":  `{"type":"service_account"}`,
+			"project_id":   "",
+		}},
+	}
+	if val, err = testUpgradeState(t, "prismacloud_cloud_account_v2", v2); err != nil {
+		t.Fatalf("Error upgrading state: %s", err)
+	}
+	if got := val.GetAttr("azure").Index(cty.NumberIntVal(0)).GetAttr("environment_type"); !got.IsNull() {
+		t.Errorf("environment_type is %#v, expected null", got)
+	}
+	if got := val.GetAttr("gcp").Index(cty.NumberIntVal(0)).GetAttr("project_id"); got.AsString() != "" {
+		t.Errorf("project_id is %#v, expected empty", got)
+	}
+}
@@ -0,0 +1,142 @@
+package prismacloud
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+
+	pc "github.com/paloaltonetworks/prisma-cloud-go"
+)
+
+// defaultProfile is the profile others inherit from.
+const defaultProfile = "default"
+
+// configProfile holds the params that pc.Client.Initialize reads from the
+// JSON config file.
+type configProfile struct {
+	Url          string          `json:"url"`
+	Username     string          `json:"username"`
+	Password     string          `json:"password"`
+	CustomerName string          `json:"customer_name"`
+	Protocol     string          `json:"protocol"`
+	Port         int             `json:"port"`
+	Timeout      int             `json:"timeout"`
+	Logging      map[string]bool `json:"logging"`
+	JsonWebToken string          `json:"json_web_token"`
+}
+
+// inherit fills in the params not set in p from base.
+func (p configProfile) inherit(base configProfile) configProfile {
+	if p.Url == "" {
+		p.Url = base.Url
+	}
+	if p.Username == "" {
+		p.Username = base.Username
+	}
+	if p.Password == "" {
+		p.Password = base.Password
+	}
+	if p.CustomerName == "" {
+		p.CustomerName = base.CustomerName
+	}
+	if p.Protocol == "" {
+		p.Protocol = base.Protocol
+	}
+	if p.Port == 0 {
+		p.Port = base.Port
+	}
+	if p.Timeout == 0 {
+		p.Timeout = base.Timeout
+	}
+	if len(p.Logging) == 0 {
+		p.Logging = base.Logging
+	}
+	if p.JsonWebToken == "" {
+		p.JsonWebToken = base.JsonWebToken
+	}
+
+	return p
+}
+
+// apply fills in the params not set in the client from the profile.
+func (p configProfile) apply(c *pc.Client) {
+	if c.Url == "" {
+		c.Url = p.Url
+	}
+	if c.Username == "" {
+		c.Username = p.Username
+	}
+	if c.Password == "" {
+		c.Password = p.Password
+	}
+	if c.CustomerName == "" {
+		c.CustomerName = p.CustomerName
+	}
+	if c.Protocol == "" {
+		c.Protocol = p.Protocol
+	}
+	if c.Port == 0 {
+		c.Port = p.Port
+	}
+	if c.Timeout == 0 {
+		c.Timeout = p.Timeout
+	}
+	if len(c.Logging) == 0 && len(p.Logging) > 0 {
+		c.Logging = make(map[string]bool)
+		for key, val := range p.Logging {
+			c.Logging[key] = val
+		}
+	}
+	if c.JsonWebToken == "" {
+		c.JsonWebToken = p.JsonWebToken
+	}
+}
+
+/*
+loadConfigProfile returns the named profile from the JSON config file.
+
+Config files without a "profiles" map are left for pc.Client.Initialize to
+read as before, in which case nil is returned.  Otherwise the named profile,
+or the default profile if no name is given, is returned with any params it
+does not set inherited from the default profile and then from the top level
+of the file.
+*/
+func loadConfigProfile(filename, name string) (*configProfile, error) {
+	if filename == "" {
+		if name != "" {
+			return nil, fmt.Errorf("Profile %q given without a json_config_file", name)
+		}
+		return nil, nil
+	}
+
+	b, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	var f struct {
+		configProfile
+		Profiles map[string]configProfile `json:"profiles"`
+	}
+	if err = json.Unmarshal(b, &f); err != nil {
+		return nil, err
+	}
+
+	if f.Profiles == nil {
+		if name != "" {
+			return nil, fmt.Errorf("Profile %q given but %s has no profiles", name, filename)
+		}
+		return nil, nil
+	}
+
+	if name == "" {
+		name = defaultProfile
+	}
+	p, ok := f.Profiles[name]
+	if !ok && name != defaultProfile {
+		return nil, fmt.Errorf("Profile %q not found in %s", name, filename)
+	}
+	p = p.inherit(f.Profiles[defaultProfile]).inherit(f.configProfile)
+
+	return &p, nil
+}
@@ -0,0 +1,122 @@
+package prismacloud
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	pc "github.com/paloaltonetworks/prisma-cloud-go"
+	"github.com/terraform-providers/terraform-provider-prismacloud/internal/fakeapi"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func testConfigFile(t *testing.T, v interface{}) string {
+	t.Helper()
+
+	b, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf("Error marshaling config: %s", err)
+	}
+	filename := filepath.Join(t.TempDir(), "prismacloud_auth.json")
+	if err = os.WriteFile(filename, b, 0600); err != nil {
+		t.Fatalf("Error writing config: %s", err)
+	}
+
+	return filename
+}
+
+func TestLoadConfigProfile(t *testing.T) {
+	filename := testConfigFile(t, map[string]interface{}{
+		"customer_name": "top",
+		"profiles": map[string]interface{}{
+			"default": map[string]interface{}{
+				"url":      "api.prismacloud.io",
+				"username": "default-user",
+				"password": "default-pass",
+				"timeout":  60,
+				"logging":  map[string]bool{pc.LogQuiet: true},
+			},
+			"eu": map[string]interface{}{
+				"url":      "api.eu.prismacloud.io",
+				"username": "eu-user",
+				"password": "eu-pass",
+			},
+		},
+	})
+
+	p, err := loadConfigProfile(filename, "eu")
+	if err != nil {
+		t.Fatalf("Error loading profile: %s", err)
+	}
+	if p.Url != "api.eu.prismacloud.io" || p.Username != "eu-user" || p.Password != "eu-pass" {
+		t.Errorf("Profile params not used: %#v", p)
+	}
+	if p.Timeout != 60 || !p.Logging[pc.LogQuiet] {
+		t.Errorf("Default profile params not inherited: %#v", p)
+	}
+	if p.CustomerName != "top" {
+		t.Errorf("Top level params not inherited: %#v", p)
+	}
+
+	if p, err = loadConfigProfile(filename, ""); err != nil {
+		t.Fatalf("Error loading default profile: %s", err)
+	} else if p.Username != "default-user" {
+		t.Errorf("Default profile not used: %#v", p)
+	}
+
+	if _, err = loadConfigProfile(filename, "staging"); err == nil {
+		t.Errorf("Expected an error for a missing profile")
+	}
+}
+
+func TestLoadConfigProfileFlatFile(t *testing.T) {
+	filename := testConfigFile(t, map[string]interface{}{"url": "api.prismacloud.io"})
+
+	if p, err := loadConfigProfile(filename, ""); err != nil || p != nil {
+		t.Errorf("Flat config file returned %#v, %v; expected nil, nil", p, err)
+	}
+	if _, err := loadConfigProfile(filename, "eu"); err == nil {
+		t.Errorf("Expected an error for a profile in a flat config file")
+	}
+}
+
+func TestProviderConfigureProfile(t *testing.T) {
+	s := fakeapi.New()
+	s.Username = "sandbox-key"
+	s.Password = "sandbox-secret"
+	defer s.Close()
+
+	filename := testConfigFile(t, map[string]interface{}{
+		"profiles": map[string]interface{}{
+			"default": map[string]interface{}{
+				"url":      s.Host(),
+				"port":     s.Port(),
+				"protocol": "http",
+				"username": "prod-key",
+				"password": "prod-secret",
+				"logging":  map[string]bool{pc.LogQuiet: true},
+			},
+			"sandbox": map[string]interface{}{
+				"username": s.Username,
+				"password": s.Password,
+			},
+		},
+	})
+	t.Setenv("PRISMACLOUD_PROFILE", "sandbox")
+
+	raw := map[string]interface{}{"json_config_file": filename}
+	meta, err := providerConfigure(schema.TestResourceDataRaw(t, Provider().Schema, raw))
+	if err != nil {
+		t.Fatalf("Error configuring provider: %s", err)
+	}
+
+	client := meta.(*pc.Client)
+	if client.Username != s.Username || client.Url != s.Host() {
+		t.Errorf("Profile not applied: username %q, url %q", client.Username, client.Url)
+	}
+	if n := s.Count("POST", "/login"); n != 1 {
+		t.Errorf("Expected 1 login, got %d", n)
+	}
+}
@@ -0,0 +1,130 @@
+package prismacloud
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os/exec"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/net/context"
+)
+
+// credentialExpiryWindow is how long before their expiry credentials from a
+// credential process are considered expired.
+var credentialExpiryWindow = 30 * time.Second
+
+// processCredentials is the JSON output expected from a credential process.
+type processCredentials struct {
+	Username string     `json:"username"`
+	Password string     `json:"password"`
+	Expiry   *time.Time `json:"expiry,omitempty"`
+}
+
+/*
+credentialProcess fetches access keys by running an external command.
+
+The command is run through the shell and must print a JSON object with
+"username" and "password" on stdout, along with an optional "expiry" in
+RFC 3339 format.  The output is kept until it expires, after which the
+command is run again the next time credentials are needed.
+*/
+type credentialProcess struct {
+	command string
+
+	mu    sync.Mutex
+	creds *processCredentials
+}
+
+// Get returns the current credentials, running the command if there are none
+// or they have expired.
+func (p *credentialProcess) Get(ctx context.Context) (processCredentials, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.creds != nil && (p.creds.Expiry == nil || time.Now().Add(credentialExpiryWindow).Before(*p.creds.Expiry)) {
+		return *p.creds, nil
+	}
+
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.CommandContext(ctx, "cmd", "/C", p.command)
+	} else {
+		cmd = exec.CommandContext(ctx, "sh", "-c", p.command)
+	}
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return processCredentials{}, fmt.Errorf("credential_process failed: %s: %s", err, msg)
+		}
+		return processCredentials{}, fmt.Errorf("credential_process failed: %s", err)
+	}
+
+	var ans processCredentials
+	if err = json.Unmarshal(out, &ans); err != nil {
+		return processCredentials{}, fmt.Errorf("credential_process returned invalid JSON: %s", err)
+	}
+	if ans.Username == "" || ans.Password == "" {
+		return processCredentials{}, fmt.Errorf("credential_process did not return a username and password")
+	}
+
+	p.creds = &ans
+	return ans, nil
+}
+
+/*
+credentialTransport supplies the credentials from a credential process to
+every login.
+
+The client logs in again with its saved username and password whenever its
+JSON web token is rejected, so the login request body is rewritten here with
+credentials that are fetched anew once the previous ones have expired.
+*/
+type credentialTransport struct {
+	next    http.RoundTripper
+	process *credentialProcess
+}
+
+func (t *credentialTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method != http.MethodPost || strings.TrimRight(req.URL.Path, "/") != "/login" || req.Body == nil {
+		return t.next.RoundTrip(req)
+	}
+
+	creds, err := t.process.Get(req.Context())
+	if err != nil {
+		return nil, err
+	}
+
+	b, err := ioutil.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	var login map[string]interface{}
+	if err = json.Unmarshal(b, &login); err != nil {
+		return nil, err
+	}
+	login["username"] = creds.Username
+	login["password"] = creds.Password
+	if b, err = json.Marshal(login); err != nil {
+		return nil, err
+	}
+
+	req = req.Clone(req.Context())
+	req.Body = ioutil.NopCloser(bytes.NewReader(b))
+	req.ContentLength = int64(len(b))
+	req.GetBody = func() (io.ReadCloser, error) {
+		return ioutil.NopCloser(bytes.NewReader(b)), nil
+	}
+
+	return t.next.RoundTrip(req)
+}
@@ -0,0 +1,122 @@
+package prismacloud
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/paloaltonetworks/prisma-cloud-go/cloud/account/group"
+	"golang.org/x/net/context"
+)
+
+// testCredentialProcess returns a credential process command that prints the
+// given credentials, along with a function that reports how many times the
+// command has been run.
+func testCredentialProcess(t *testing.T, username, password string, expiry time.Time) (string, func() int) {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("Credential process tests need a POSIX shell")
+	}
+
+	dir := t.TempDir()
+	runs := filepath.Join(dir, "runs")
+	script := filepath.Join(dir, "credentials.sh")
+	out := fmt.Sprintf(`{"username": %q, "password": %q, "expiry": %q}`, username, password, expiry.Format(time.RFC3339))
+	body := fmt.Sprintf("#!/bin/sh\necho run >> '%s'\necho '%s'\n", runs, out)
+	if err := os.WriteFile(script, []byte(body), 0700); err != nil {
+		t.Fatalf("Error writing script: %s", err)
+	}
+
+	return script, func() int {
+		b, _ := os.ReadFile(runs)
+		return strings.Count(string(b), "run")
+	}
+}
+
+func TestCredentialProcessCached(t *testing.T) {
+	cmd, runs := testCredentialProcess(t, "key", "secret", time.Now().Add(time.Hour))
+	p := &credentialProcess{command: cmd}
+
+	for i := 0; i < 3; i++ {
+		creds, err := p.Get(context.Background())
+		if err != nil {
+			t.Fatalf("Error getting credentials: %s", err)
+		}
+		if creds.Username != "key" || creds.Password != "secret" {
+			t.Fatalf("Got credentials %q / %q", creds.Username, creds.Password)
+		}
+	}
+	if n := runs(); n != 1 {
+		t.Fatalf("Credential process ran %d times, expected 1", n)
+	}
+}
+
+func TestCredentialProcessErrors(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Credential process tests need a POSIX shell")
+	}
+
+	for _, cmd := range []string{
+		"echo oops >&2; exit 1",
+		"echo not json",
+		`echo '{"username": "key"}'`,
+	} {
+		p := &credentialProcess{command: cmd}
+		if _, err := p.Get(context.Background()); err == nil {
+			t.Errorf("Expected an error from %q", cmd)
+		}
+	}
+}
+
+func TestProviderConfigureCredentialProcess(t *testing.T) {
+	cmd, runs := testCredentialProcess(t, "fake-access-key", "fake-secret-key", time.Now().Add(-time.Minute))
+	s, client := testFakeClient(t, map[string]interface{}{
+		"username":           "",
+		"password":           "",
+		"credential_process": cmd,
+	})
+
+	if n := s.Count("POST", "/login"); n != 1 {
+		t.Fatalf("Expected 1 login, got %d", n)
+	}
+	before := runs()
+
+	// Expired credentials are fetched again when the client logs back in.
+	s.ExpireTokens()
+	if _, err := group.List(client); err != nil {
+		t.Fatalf("Error in list after token expiry: %s", err)
+	}
+	if n := s.Count("POST", "/login"); n != 2 {
+		t.Fatalf("Expected 2 logins, got %d", n)
+	}
+	if n := runs(); n <= before {
+		t.Fatalf("Credential process was not run again on login")
+	}
+}
+
+func TestProviderConfigureCredentialProcessPrecedence(t *testing.T) {
+	// The credential process wins over both the provider block and the
+	// config file, whose credentials the fake API would reject.
+	cmd, _ := testCredentialProcess(t, "fake-access-key", "fake-secret-key", time.Now().Add(time.Hour))
+	filename := testConfigFile(t, map[string]interface{}{
+		"username": "file-key",
+		"password": "file-secret",
+	})
+	s, client := testFakeClient(t, map[string]interface{}{
+		"username":           "block-key",
+		"password":           "block-secret",
+		"json_config_file":   filename,
+		"credential_process": cmd,
+	})
+
+	if client.Username != "fake-access-key" || client.Password != "fake-secret-key" {
+		t.Errorf("Client has username %q and password %q", client.Username, client.Password)
+	}
+	if n := s.Count("POST", "/login"); n != 1 {
+		t.Errorf("Expected 1 login, got %d", n)
+	}
+}
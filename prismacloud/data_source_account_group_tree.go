@@ -0,0 +1,283 @@
+package prismacloud
+
+import (
+	"log"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	pc "github.com/paloaltonetworks/prisma-cloud-go"
+	"github.com/paloaltonetworks/prisma-cloud-go/cloud/account/group"
+	"golang.org/x/net/context"
+)
+
+func dataSourceAccountGroupTree() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceAccountGroupTreeRead,
+
+		Schema: map[string]*schema.Schema{
+			// Input.
+			"group_id": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				Description:  "Root account group ID",
+				AtLeastOneOf: []string{"group_id", "name"},
+			},
+			"name": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				Description:  "Name of the root account group",
+				AtLeastOneOf: []string{"group_id", "name"},
+			},
+
+			// Output.
+			"total": totalSchema("account groups in the tree"),
+			"groups": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The root account group and its descendants, depth first",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"group_id": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Account group ID",
+						},
+						"name": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Account group name",
+						},
+						"description": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Description",
+						},
+						"depth": {
+							Type:        schema.TypeInt,
+							Computed:    true,
+							Description: "Depth below the root account group, which is at depth 0",
+						},
+						"parent_id": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "ID of the account group this group was reached from, empty for the root",
+						},
+						"child_group_ids": {
+							Type:        schema.TypeList,
+							Computed:    true,
+							Description: "Child account group IDs",
+							Elem: &schema.Schema{
+								Type: schema.TypeString,
+							},
+						},
+						"account_ids": {
+							Type:        schema.TypeList,
+							Computed:    true,
+							Description: "Cloud account IDs in this group",
+							Elem: &schema.Schema{
+								Type: schema.TypeString,
+							},
+						},
+						"transitive_account_ids": {
+							Type:        schema.TypeList,
+							Computed:    true,
+							Description: "Cloud account IDs in this group and all of its descendants",
+							Elem: &schema.Schema{
+								Type: schema.TypeString,
+							},
+						},
+						"accounts":    accountGroupAccountsSchema(),
+						"alert_rules": accountGroupAlertRulesSchema(),
+						"parent_info": accountGroupParentInfoSchema(),
+					},
+				},
+			},
+		},
+	}
+}
+
+// accountGroupTreeNode is an account group as reached by a walk of the tree.
+type accountGroupTreeNode struct {
+	group      group.Group
+	depth      int
+	parentId   string
+	transitive []string
+}
+
+// accountGroupTree walks the account groups below a root group.
+type accountGroupTree struct {
+	client *pc.Client
+	groups map[string]group.Group
+	nodes  []*accountGroupTreeNode
+	seen   map[string]*accountGroupTreeNode
+	path   map[string]bool
+}
+
+/*
+walkAccountGroupTree returns the given account group and its descendants,
+depth first.
+
+The groups come from the listing, as getting a single group does not return
+its accounts or alert rules.  A child group that is missing from the listing
+is got on its own.  Groups reached through more than one parent are returned
+once, and child group IDs that lead back to an ancestor are skipped.
+*/
+func walkAccountGroupTree(client *pc.Client, id string) ([]*accountGroupTreeNode, error) {
+	list, err := group.List(client)
+	if err != nil {
+		return nil, err
+	}
+
+	t := accountGroupTree{
+		client: client,
+		groups: make(map[string]group.Group, len(list)),
+		seen:   make(map[string]*accountGroupTreeNode),
+		path:   make(map[string]bool),
+	}
+	for _, obj := range list {
+		t.groups[obj.Id] = obj
+	}
+
+	if _, err = t.visit(id, "", 0); err != nil {
+		return nil, err
+	}
+
+	return t.nodes, nil
+}
+
+// visit walks the given group, returning its transitive account IDs.
+func (t *accountGroupTree) visit(id, parentId string, depth int) ([]string, error) {
+	if node, ok := t.seen[id]; ok {
+		return node.transitive, nil
+	}
+	if t.path[id] {
+		log.Printf("[WARN] Account group %q is its own descendant, skipping it below %q", id, parentId)
+		return nil, nil
+	}
+
+	obj, ok := t.groups[id]
+	if !ok {
+		var err error
+		if obj, err = group.Get(t.client, id); err != nil {
+			if err == pc.AccountGroupNotFoundError && parentId != "" {
+				log.Printf("[WARN] Child account group %q of %q does not exist", id, parentId)
+				return nil, nil
+			}
+			return nil, err
+		}
+	}
+
+	node := &accountGroupTreeNode{
+		group:    obj,
+		depth:    depth,
+		parentId: parentId,
+	}
+	t.nodes = append(t.nodes, node)
+
+	accounts := make(map[string]bool)
+	for _, v := range accountGroupAccountIds(obj) {
+		accounts[v] = true
+	}
+
+	t.path[id] = true
+	for _, child := range obj.ChildGroupIds {
+		ids, err := t.visit(child, id, depth+1)
+		if err != nil {
+			return nil, err
+		}
+		for _, v := range ids {
+			accounts[v] = true
+		}
+	}
+	delete(t.path, id)
+
+	node.transitive = make([]string, 0, len(accounts))
+	for v := range accounts {
+		node.transitive = append(node.transitive, v)
+	}
+	sort.Strings(node.transitive)
+	t.seen[id] = node
+
+	return node.transitive, nil
+}
+
+// accountGroupAccountIds returns the IDs of the cloud accounts directly in
+// the given group, from either its account IDs or its accounts.
+func accountGroupAccountIds(obj group.Group) []string {
+	seen := make(map[string]bool, len(obj.AccountIds))
+	ans := make([]string, 0, len(obj.AccountIds))
+	for _, v := range obj.AccountIds {
+		if !seen[v] {
+			seen[v] = true
+			ans = append(ans, v)
+		}
+	}
+	for _, a := range obj.Accounts {
+		if !seen[a.Id] {
+			seen[a.Id] = true
+			ans = append(ans, a.Id)
+		}
+	}
+
+	return ans
+}
+
+func dataSourceAccountGroupTreeRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*pc.Client)
+
+	var err error
+	id := d.Get("group_id").(string)
+
+	if id == "" {
+		name := d.Get("name").(string)
+		id, err = group.Identify(client, name)
+		if err != nil {
+			if err == pc.ObjectNotFoundError {
+				d.SetId("")
+				return nil
+			}
+			return diag.FromErr(err)
+		}
+	}
+
+	nodes, err := walkAccountGroupTree(client, id)
+	if err != nil {
+		if err == pc.AccountGroupNotFoundError {
+			d.SetId("")
+			return nil
+		}
+		return diag.FromErr(err)
+	}
+
+	d.SetId(id)
+	d.Set("group_id", id)
+	d.Set("name", nodes[0].group.Name)
+	d.Set("total", len(nodes))
+
+	list := make([]interface{}, 0, len(nodes))
+	for _, node := range nodes {
+		obj := node.group
+		list = append(list, map[string]interface{}{
+			"group_id":               obj.Id,
+			"name":                   obj.Name,
+			"description":            obj.Description,
+			"depth":                  node.depth,
+			"parent_id":              node.parentId,
+			"child_group_ids":        obj.ChildGroupIds,
+			"account_ids":            accountGroupAccountIds(obj),
+			"transitive_account_ids": node.transitive,
+			"accounts":               flattenAccountGroupAccounts(obj.Accounts),
+			"alert_rules":            flattenAccountGroupAlertRules(obj.AlertRules),
+			"parent_info":            flattenAccountGroupParentInfo(obj.ParentInfo),
+		})
+	}
+
+	if err := d.Set("groups", list); err != nil {
+		log.Printf("[WARN] Error setting 'groups' field for %q: %s", d.Id(), err)
+	}
+
+	return nil
+}
@@ -0,0 +1,97 @@
+package prismacloud
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/paloaltonetworks/prisma-cloud-go/cloud/account/group"
+	"golang.org/x/net/context"
+)
+
+func TestDsAccountGroupTreeFakeApi(t *testing.T) {
+	s, client := testFakeClient(t, nil)
+	ds := dataSourceAccountGroupTree()
+
+	leaf := s.AccountGroups.Put(group.Group{
+		Name:       "leaf",
+		AccountIds: []string{"333"},
+		ParentInfo: group.ParentInfo{Id: "org", Name: "Org", AutoCreated: true},
+	})
+	shared := s.AccountGroups.Put(group.Group{Name: "shared", AccountIds: []string{"444"}})
+	mid := s.AccountGroups.Put(group.Group{
+		Name:          "mid",
+		Accounts:      []group.Account{{Id: "222", Name: "dev", Type: "aws"}},
+		ChildGroupIds: []string{leaf, shared},
+	})
+	root := s.AccountGroups.Put(group.Group{
+		Name:          "root",
+		AccountIds:    []string{"111"},
+		AlertRules:    []group.AlertRule{{Id: "rule-1", Name: "All"}},
+		ChildGroupIds: []string{mid, shared, "missing"},
+	})
+
+	// A cycle back to the root.
+	var obj group.Group
+	s.AccountGroups.Load(leaf, &obj)
+	obj.ChildGroupIds = []string{root}
+	s.AccountGroups.Put(obj)
+
+	d := schema.TestResourceDataRaw(t, ds.Schema, map[string]interface{}{"name": "root"})
+	if diags := ds.ReadContext(context.Background(), d, client); diags.HasError() {
+		t.Fatalf("Error in read: %v", diags)
+	}
+
+	if d.Id() != root {
+		t.Errorf("ID is %q, expected %q", d.Id(), root)
+	}
+	if total := d.Get("total").(int); total != 4 {
+		t.Fatalf("Total is %d, expected 4", total)
+	}
+
+	join := func(key string) string {
+		var ans []string
+		for _, v := range d.Get(key).([]interface{}) {
+			ans = append(ans, v.(string))
+		}
+		return strings.Join(ans, ",")
+	}
+	for i, want := range []struct {
+		name       string
+		depth      int
+		parent     string
+		accounts   string
+		transitive string
+	}{
+		{"root", 0, "", "111", "111,222,333,444"},
+		{"mid", 1, root, "222", "222,333,444"},
+		{"leaf", 2, mid, "333", "333"},
+		{"shared", 2, mid, "444", "444"},
+	} {
+		prefix := fmt.Sprintf("groups.%d.", i)
+		if got := d.Get(prefix + "name").(string); got != want.name {
+			t.Errorf("Group %d is %q, expected %q", i, got, want.name)
+			continue
+		}
+		if got := d.Get(prefix + "depth").(int); got != want.depth {
+			t.Errorf("%s: depth is %d, expected %d", want.name, got, want.depth)
+		}
+		if got := d.Get(prefix + "parent_id").(string); got != want.parent {
+			t.Errorf("%s: parent_id is %q, expected %q", want.name, got, want.parent)
+		}
+		if got := join(prefix + "account_ids"); got != want.accounts {
+			t.Errorf("%s: account_ids is %s, expected %s", want.name, got, want.accounts)
+		}
+		if got := join(prefix + "transitive_account_ids"); got != want.transitive {
+			t.Errorf("%s: transitive_account_ids is %s, expected %s", want.name, got, want.transitive)
+		}
+	}
+
+	if got := d.Get("groups.0.alert_rules.0.alert_id"); got != "rule-1" {
+		t.Errorf("Root alert rule is %v", got)
+	}
+	if got := d.Get("groups.2.parent_info.0.auto_created"); got != true {
+		t.Errorf("Leaf auto_created is %v", got)
+	}
+}
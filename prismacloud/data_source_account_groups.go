@@ -34,73 +34,9 @@ func dataSourceAccountGroups() *schema.Resource {
 							Computed:    true,
 							Description: "Account group name",
 						},
-						"accounts": {
-							Type:        schema.TypeList,
-							Computed:    true,
-							Description: "Associated cloud accounts",
-							Elem: &schema.Resource{
-								Schema: map[string]*schema.Schema{
-									"account_id": {
-										Type:        schema.TypeString,
-										Computed:    true,
-										Description: "Associated cloud account ID",
-									},
-									"name": {
-										Type:        schema.TypeString,
-										Computed:    true,
-										Description: "Associated cloud account name",
-									},
-									"account_type": {
-										Type:        schema.TypeString,
-										Computed:    true,
-										Description: "Associated cloud account type",
-									},
-								},
-							},
-						},
-						"alert_rules": {
-							Type:        schema.TypeList,
-							Computed:    true,
-							Description: "Singly associated alert rules which cannot exist in the system without the account group",
-							Elem: &schema.Resource{
-								Schema: map[string]*schema.Schema{
-									"alert_id": {
-										Type:        schema.TypeString,
-										Computed:    true,
-										Description: "The alert ID",
-									},
-									"name": {
-										Type:        schema.TypeString,
-										Computed:    true,
-										Description: "Alert name",
-									},
-								},
-							},
-						},
-						"parent_info": {
-							Type:        schema.TypeList,
-							Computed:    true,
-							Description: "Parent account group info",
-							Elem: &schema.Resource{
-								Schema: map[string]*schema.Schema{
-									"group_id": {
-										Type:        schema.TypeString,
-										Computed:    true,
-										Description: "Parent account group ID",
-									},
-									"name": {
-										Type:        schema.TypeString,
-										Computed:    true,
-										Description: "Parent account group name",
-									},
-									"auto_created": {
-										Type:        schema.TypeBool,
-										Computed:    true,
-										Description: "Boolean to indicate if account group is automatically created",
-									},
-								},
-							},
-						},
+						"accounts":    accountGroupAccountsSchema(),
+						"alert_rules": accountGroupAlertRulesSchema(),
+						"parent_info": accountGroupParentInfoSchema(),
 					},
 				},
 			},
@@ -121,35 +57,12 @@ func dataSourceAccountGroupsRead(ctx context.Context, d *schema.ResourceData, me
 
 	list := make([]interface{}, 0, len(items))
 	for _, i := range items {
-		acts := make([]interface{}, 0, len(i.Accounts))
-		for _, j := range i.Accounts {
-			acts = append(acts, map[string]interface{}{
-				"account_id":   j.Id,
-				"name":         j.Name,
-				"account_type": j.Type,
-			})
-		}
-
-		rules := make([]interface{}, 0, len(i.AlertRules))
-		for _, k := range i.AlertRules {
-			rules = append(rules, map[string]interface{}{
-				"alert_id": k.Id,
-				"name":     k.Name,
-			})
-		}
-
-		pInfo := map[string]interface{}{
-			"group_id":     i.ParentInfo.Id,
-			"name":         i.ParentInfo.Name,
-			"auto_created": i.ParentInfo.AutoCreated,
-		}
-
 		list = append(list, map[string]interface{}{
 			"group_id":    i.Id,
 			"name":        i.Name,
-			"accounts":    acts,
-			"alert_rules": rules,
-			"parent_info": []interface{}{pInfo},
+			"accounts":    flattenAccountGroupAccounts(i.Accounts),
+			"alert_rules": flattenAccountGroupAlertRules(i.AlertRules),
+			"parent_info": flattenAccountGroupParentInfo(i.ParentInfo),
 		})
 	}
 
@@ -159,3 +72,112 @@ func dataSourceAccountGroupsRead(ctx context.Context, d *schema.ResourceData, me
 
 	return nil
 }
+
+func accountGroupAccountsSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeList,
+		Computed:    true,
+		Description: "Associated cloud accounts",
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"account_id": {
+					Type:        schema.TypeString,
+					Computed:    true,
+					Description: "Associated cloud account ID",
+				},
+				"name": {
+					Type:        schema.TypeString,
+					Computed:    true,
+					Description: "Associated cloud account name",
+				},
+				"account_type": {
+					Type:        schema.TypeString,
+					Computed:    true,
+					Description: "Associated cloud account type",
+				},
+			},
+		},
+	}
+}
+
+func accountGroupAlertRulesSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeList,
+		Computed:    true,
+		Description: "Singly associated alert rules which cannot exist in the system without the account group",
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"alert_id": {
+					Type:        schema.TypeString,
+					Computed:    true,
+					Description: "The alert ID",
+				},
+				"name": {
+					Type:        schema.TypeString,
+					Computed:    true,
+					Description: "Alert name",
+				},
+			},
+		},
+	}
+}
+
+func accountGroupParentInfoSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeList,
+		Computed:    true,
+		Description: "Parent account group info",
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"group_id": {
+					Type:        schema.TypeString,
+					Computed:    true,
+					Description: "Parent account group ID",
+				},
+				"name": {
+					Type:        schema.TypeString,
+					Computed:    true,
+					Description: "Parent account group name",
+				},
+				"auto_created": {
+					Type:        schema.TypeBool,
+					Computed:    true,
+					Description: "Boolean to indicate if account group is automatically created",
+				},
+			},
+		},
+	}
+}
+
+func flattenAccountGroupAccounts(list []group.Account) []interface{} {
+	ans := make([]interface{}, 0, len(list))
+	for _, j := range list {
+		ans = append(ans, map[string]interface{}{
+			"account_id":   j.Id,
+			"name":         j.Name,
+			"account_type": j.Type,
+		})
+	}
+
+	return ans
+}
+
+func flattenAccountGroupAlertRules(list []group.AlertRule) []interface{} {
+	ans := make([]interface{}, 0, len(list))
+	for _, k := range list {
+		ans = append(ans, map[string]interface{}{
+			"alert_id": k.Id,
+			"name":     k.Name,
+		})
+	}
+
+	return ans
+}
+
+func flattenAccountGroupParentInfo(info group.ParentInfo) []interface{} {
+	return []interface{}{map[string]interface{}{
+		"group_id":     info.Id,
+		"name":         info.Name,
+		"auto_created": info.AutoCreated,
+	}}
+}
@@ -0,0 +1,70 @@
+package prismacloud
+
+import (
+	"encoding/json"
+	"log"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	pc "github.com/paloaltonetworks/prisma-cloud-go"
+	"github.com/paloaltonetworks/prisma-cloud-go/alert"
+	"golang.org/x/net/context"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataSourceAlert() *schema.Resource {
+	s := alertSchema()
+
+	// Input.
+	s["alert_id"] = &schema.Schema{
+		Type:        schema.TypeString,
+		Required:    true,
+		Description: "Alert ID",
+	}
+
+	// Output.
+	s["resource"].Elem.(*schema.Resource).Schema["data"] = &schema.Schema{
+		Type:        schema.TypeString,
+		Computed:    true,
+		Description: "Raw resource data as JSON",
+	}
+
+	return &schema.Resource{
+		ReadContext: dataSourceAlertRead,
+
+		Schema: s,
+	}
+}
+
+func dataSourceAlertRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*pc.Client)
+	id := d.Get("alert_id").(string)
+
+	o, err := alert.Get(client, id)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(id)
+
+	var data string
+	if o.Resource.Data != nil {
+		b, err := json.Marshal(o.Resource.Data)
+		if err != nil {
+			log.Printf("[WARN] Failed to marshal resource data for alert %q: %s", id, err)
+		}
+		data = string(b)
+	}
+
+	info := flattenAlert(o)
+	info["resource"].([]interface{})[0].(map[string]interface{})["data"] = data
+	delete(info, "alert_id")
+
+	for key, val := range info {
+		if err := d.Set(key, val); err != nil {
+			log.Printf("[WARN] Error setting %q for %q: %s", key, d.Id(), err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,59 @@
+package prismacloud
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"golang.org/x/net/context"
+)
+
+func TestDsAlertFakeApi(t *testing.T) {
+	s, client := testFakeClient(t, nil)
+	ds := dataSourceAlert()
+
+	id := s.Alerts.Put(map[string]interface{}{
+		"status": "dismissed",
+		"policy": map[string]interface{}{"policyId": "policy-1", "policyType": "config", "remediable": true},
+		"resource": map[string]interface{}{
+			"rrn":          "rrn::s3:us-east-1:123456789012::bucket:logs",
+			"resourceTags": map[string]interface{}{"team": "sec"},
+			"data":         map[string]interface{}{"acl": map[string]interface{}{"public": true}},
+		},
+		"history": []interface{}{
+			map[string]interface{}{"status": "open", "modifiedBy": "Prisma Cloud System Admin", "modifiedOn": 1600000000000},
+			map[string]interface{}{"status": "dismissed", "reason": "Accepted risk", "modifiedBy": "admin@example.com", "modifiedOn": 1600000100000},
+		},
+		"investigateOptions": map[string]interface{}{"searchId": "search-1", "startTs": 1, "endTs": 2},
+	})
+
+	d := schema.TestResourceDataRaw(t, ds.Schema, map[string]interface{}{"alert_id": id})
+	if diags := ds.ReadContext(context.Background(), d, client); diags.HasError() {
+		t.Fatalf("Error in read: %v", diags)
+	}
+
+	if d.Id() != id {
+		t.Errorf("ID is %q, expected %q", d.Id(), id)
+	}
+	for key, want := range map[string]interface{}{
+		"status":                          "dismissed",
+		"policy.0.policy_id":              "policy-1",
+		"policy.0.remediable":             true,
+		"resource.0.rrn":                  "rrn::s3:us-east-1:123456789012::bucket:logs",
+		"resource.0.tags.team":            "sec",
+		"resource.0.data":                 `{"acl":{"public":true}}`,
+		"history.#":                       2,
+		"history.1.reason":                "Accepted risk",
+		"history.1.modified_by":           "admin@example.com",
+		"history.1.modified_on":           1600000100000,
+		"investigate_options.0.search_id": "search-1",
+	} {
+		if got := d.Get(key); got != want {
+			t.Errorf("%s is %#v, expected %#v", key, got, want)
+		}
+	}
+
+	d = schema.TestResourceDataRaw(t, ds.Schema, map[string]interface{}{"alert_id": "missing"})
+	if diags := ds.ReadContext(context.Background(), d, client); !diags.HasError() {
+		t.Errorf("Expected an error for a missing alert")
+	}
+}
@@ -1,14 +1,17 @@
 package prismacloud
 
 import (
+	"fmt"
+	"log"
+
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"golang.org/x/net/context"
-	"log"
 
 	pc "github.com/paloaltonetworks/prisma-cloud-go"
 	"github.com/paloaltonetworks/prisma-cloud-go/alert"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
 func dataSourceAlerts() *schema.Resource {
@@ -21,7 +24,7 @@ func dataSourceAlerts() *schema.Resource {
 			"limit": {
 				Type:        schema.TypeInt,
 				Optional:    true,
-				Description: "Max number of alerts to return.  This uses the v2 version of the API, where the default and max is 10,000.",
+				Description: "Max number of alerts to return per page.  This uses the v2 version of the API, where the default and max is 10,000.",
 				Default:     10000,
 			},
 			"filters": {
@@ -57,6 +60,24 @@ func dataSourceAlerts() *schema.Resource {
 					Type: schema.TypeString,
 				},
 			},
+			"detailed": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Description: "Return the full alert details, such as the resource data",
+			},
+			"max_pages": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Description:  "Max number of pages of alerts to get, 0 for no limit",
+				Default:      1,
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+			"max_results": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Description:  "Max number of alerts to return across all pages, 0 for no limit",
+				ValidateFunc: validation.IntAtLeast(0),
+			},
 
 			// Attributes.
 			"page_token": {
@@ -70,57 +91,254 @@ func dataSourceAlerts() *schema.Resource {
 				Description: "Alert listing",
 				Computed:    true,
 				Elem: &schema.Resource{
-					Schema: map[string]*schema.Schema{
-						"alert_id": {
-							Type:        schema.TypeString,
-							Description: "Alert ID",
-							Computed:    true,
-						},
-						"status": {
-							Type:        schema.TypeString,
-							Description: "Alert status",
-							Computed:    true,
-						},
-						"first_seen": {
-							Type:        schema.TypeInt,
-							Description: "First seen",
-							Computed:    true,
-						},
-						"last_seen": {
-							Type:        schema.TypeInt,
-							Description: "Last seen",
-							Computed:    true,
-						},
-						"alert_time": {
-							Type:        schema.TypeInt,
-							Description: "Alert time",
-							Computed:    true,
-						},
-						"event_occurred": {
-							Type:        schema.TypeInt,
-							Description: "Event occurred",
-							Computed:    true,
-						},
-						"triggered_by": {
-							Type:        schema.TypeString,
-							Description: "Triggered by",
-							Computed:    true,
+					Schema: alertSchema(),
+				},
+			},
+		},
+	}
+}
+
+// alertSchema returns the computed schema of an alert.
+func alertSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"alert_id": {
+			Type:        schema.TypeString,
+			Description: "Alert ID",
+			Computed:    true,
+		},
+		"status": {
+			Type:        schema.TypeString,
+			Description: "Alert status",
+			Computed:    true,
+		},
+		"first_seen": {
+			Type:        schema.TypeInt,
+			Description: "First seen",
+			Computed:    true,
+		},
+		"last_seen": {
+			Type:        schema.TypeInt,
+			Description: "Last seen",
+			Computed:    true,
+		},
+		"alert_time": {
+			Type:        schema.TypeInt,
+			Description: "Alert time",
+			Computed:    true,
+		},
+		"event_occurred": {
+			Type:        schema.TypeInt,
+			Description: "Event occurred",
+			Computed:    true,
+		},
+		"triggered_by": {
+			Type:        schema.TypeString,
+			Description: "Triggered by",
+			Computed:    true,
+		},
+		"alert_count": {
+			Type:        schema.TypeInt,
+			Description: "Alert count",
+			Computed:    true,
+		},
+		"policy": {
+			Type:        schema.TypeList,
+			Description: "Policy that raised the alert",
+			Computed:    true,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"policy_id": {
+						Type:        schema.TypeString,
+						Description: "Policy ID",
+						Computed:    true,
+					},
+					"policy_type": {
+						Type:        schema.TypeString,
+						Description: "Policy type",
+						Computed:    true,
+					},
+					"system_default": {
+						Type:        schema.TypeBool,
+						Description: "If the policy is a system default policy",
+						Computed:    true,
+					},
+					"remediable": {
+						Type:        schema.TypeBool,
+						Description: "If the policy is remediable",
+						Computed:    true,
+					},
+				},
+			},
+		},
+		"resource": {
+			Type:        schema.TypeList,
+			Description: "Resource the alert is for",
+			Computed:    true,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"rrn": {
+						Type:        schema.TypeString,
+						Description: "Restricted resource name",
+						Computed:    true,
+					},
+					"resource_id": {
+						Type:        schema.TypeString,
+						Description: "Resource ID",
+						Computed:    true,
+					},
+					"name": {
+						Type:        schema.TypeString,
+						Description: "Resource name",
+						Computed:    true,
+					},
+					"account": {
+						Type:        schema.TypeString,
+						Description: "Cloud account name",
+						Computed:    true,
+					},
+					"account_id": {
+						Type:        schema.TypeString,
+						Description: "Cloud account ID",
+						Computed:    true,
+					},
+					"cloud_account_groups": {
+						Type:        schema.TypeList,
+						Description: "Account groups of the cloud account",
+						Computed:    true,
+						Elem: &schema.Schema{
+							Type: schema.TypeString,
 						},
-						"alert_count": {
-							Type:        schema.TypeInt,
-							Description: "Alert count",
-							Computed:    true,
+					},
+					"region": {
+						Type:        schema.TypeString,
+						Description: "Region name",
+						Computed:    true,
+					},
+					"region_id": {
+						Type:        schema.TypeString,
+						Description: "Region ID",
+						Computed:    true,
+					},
+					"resource_type": {
+						Type:        schema.TypeString,
+						Description: "Resource type",
+						Computed:    true,
+					},
+					"resource_api_name": {
+						Type:        schema.TypeString,
+						Description: "Resource API name",
+						Computed:    true,
+					},
+					"url": {
+						Type:        schema.TypeString,
+						Description: "Resource URL",
+						Computed:    true,
+					},
+					"cloud_type": {
+						Type:        schema.TypeString,
+						Description: "Cloud type",
+						Computed:    true,
+					},
+					"tags": {
+						Type:        schema.TypeMap,
+						Description: "Resource tags",
+						Computed:    true,
+						Elem: &schema.Schema{
+							Type: schema.TypeString,
 						},
 					},
 				},
 			},
 		},
+		"risk_detail": {
+			Type:        schema.TypeList,
+			Description: "Risk detail",
+			Computed:    true,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"rating": {
+						Type:        schema.TypeString,
+						Description: "Risk rating",
+						Computed:    true,
+					},
+					"score": {
+						Type:        schema.TypeString,
+						Description: "Risk score",
+						Computed:    true,
+					},
+					"risk_score": {
+						Type:        schema.TypeInt,
+						Description: "Numeric risk score",
+						Computed:    true,
+					},
+					"max_risk_score": {
+						Type:        schema.TypeInt,
+						Description: "Max numeric risk score",
+						Computed:    true,
+					},
+				},
+			},
+		},
+		"history": {
+			Type:        schema.TypeList,
+			Description: "Status changes of the alert",
+			Computed:    true,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"reason": {
+						Type:        schema.TypeString,
+						Description: "Reason for the change",
+						Computed:    true,
+					},
+					"status": {
+						Type:        schema.TypeString,
+						Description: "Alert status",
+						Computed:    true,
+					},
+					"modified_by": {
+						Type:        schema.TypeString,
+						Description: "Modified by",
+						Computed:    true,
+					},
+					"modified_on": {
+						Type:        schema.TypeInt,
+						Description: "Modified on",
+						Computed:    true,
+					},
+				},
+			},
+		},
+		"investigate_options": {
+			Type:        schema.TypeList,
+			Description: "Search to investigate the alert",
+			Computed:    true,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"search_id": {
+						Type:        schema.TypeString,
+						Description: "Search ID",
+						Computed:    true,
+					},
+					"start_ts": {
+						Type:        schema.TypeInt,
+						Description: "Start time",
+						Computed:    true,
+					},
+					"end_ts": {
+						Type:        schema.TypeInt,
+						Description: "End time",
+						Computed:    true,
+					},
+				},
+			},
+		},
 	}
 }
 
 func parseAlertsRequest(d *schema.ResourceData) *alert.Request {
 	ans := alert.Request{
 		Limit:     d.Get("limit").(int),
+		Detailed:  d.Get("detailed").(bool),
 		SortBy:    ListToStringSlice(d.Get("sort_by").([]interface{})),
 		TimeRange: ParseTimeRange(ResourceDataInterfaceMap(d, "time_range")),
 	}
@@ -143,44 +361,144 @@ func dataSourceAlertsRead(ctx context.Context, d *schema.ResourceData, meta inte
 	client := meta.(*pc.Client)
 
 	req := parseAlertsRequest(d)
-	ans, err := alert.List(client, *req)
-	if err != nil {
-		return diag.FromErr(err)
-	}
+	maxPages := d.Get("max_pages").(int)
+	maxResults := d.Get("max_results").(int)
 
-	d.SetId(client.Url)
-	d.Set("page_token", ans.PageToken)
-	d.Set("total", ans.Total)
-
-	data := make([]interface{}, 0, len(ans.Data))
-	for num, info := range ans.Data {
-		// TODO(shinmog) - Remove this workaround when Prisma Cloud fixes their bug.
-		//
-		// WORKAROUND: Prisma Cloud does not honor the limit for to_now queries, so
-		// enforce it here to prevent resource size overruns in Terraform:
-		//
-		// Error: rpc error: code = ResourceExhausted desc = grpc: received message larger than max (5685945 vs. 4194304)
-		//
-		// The `total` value is being intentionally left as-is so later on it will be
-		// easier to see when they've fixed this on their end.
-		if num >= req.Limit {
-			break
+	var (
+		total int
+		data  []interface{}
+	)
+	for page := 1; ; page++ {
+		ans, err := alert.List(client, *req)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		if page == 1 {
+			total = ans.Total
+		}
+
+		for num, info := range ans.Data {
+			// TODO(shinmog) - Remove this workaround when Prisma Cloud fixes their bug.
+			//
+			// WORKAROUND: Prisma Cloud does not honor the limit for to_now queries, so
+			// enforce it here to prevent resource size overruns in Terraform:
+			//
+			// Error: rpc error: code = ResourceExhausted desc = grpc: received message larger than max (5685945 vs. 4194304)
+			//
+			// The `total` value is being intentionally left as-is so later on it will be
+			// easier to see when they've fixed this on their end.
+			if num >= req.Limit || (maxResults > 0 && len(data) >= maxResults) {
+				break
+			}
+			data = append(data, flattenAlert(info))
 		}
-		item := map[string]interface{}{
-			"alert_id":       info.Id,
-			"status":         info.Status,
-			"first_seen":     info.FirstSeen,
-			"last_seen":      info.LastSeen,
-			"alert_time":     info.AlertTime,
-			"event_occurred": info.EventOccurred,
-			"triggered_by":   info.TriggeredBy,
-			"alert_count":    info.AlertCount,
+
+		req.PageToken = ans.PageToken
+		if req.PageToken == "" || page == maxPages || (maxResults > 0 && len(data) >= maxResults) {
+			break
 		}
-		data = append(data, item)
+		log.Printf("[DEBUG] Getting page %d of alerts, %d alerts so far", page+1, len(data))
 	}
+
+	d.SetId(client.Url)
+	d.Set("page_token", req.PageToken)
+	d.Set("total", total)
+
 	if err := d.Set("listing", data); err != nil {
-		log.Printf("[WARN] Error setting 'data' for %q: %s", d.Id(), err)
+		log.Printf("[WARN] Error setting 'listing' for %q: %s", d.Id(), err)
 	}
 
 	return nil
 }
+
+// flattenAlert returns the given alert for the schema from alertSchema.
+func flattenAlert(o alert.Alert) map[string]interface{} {
+	history := make([]interface{}, 0, len(o.History))
+	for _, h := range o.History {
+		history = append(history, map[string]interface{}{
+			"reason":      h.Reason,
+			"status":      h.Status,
+			"modified_by": h.ModifiedBy,
+			"modified_on": h.ModifiedOn,
+		})
+	}
+
+	return map[string]interface{}{
+		"alert_id":       o.Id,
+		"status":         o.Status,
+		"first_seen":     o.FirstSeen,
+		"last_seen":      o.LastSeen,
+		"alert_time":     o.AlertTime,
+		"event_occurred": o.EventOccurred,
+		"triggered_by":   o.TriggeredBy,
+		"alert_count":    o.AlertCount,
+		"policy": []interface{}{map[string]interface{}{
+			"policy_id":      o.Policy.Id,
+			"policy_type":    o.Policy.Type,
+			"system_default": o.Policy.SystemDefault,
+			"remediable":     o.Policy.Remediable,
+		}},
+		"resource": []interface{}{map[string]interface{}{
+			"rrn":                  o.Resource.Rrn,
+			"resource_id":          o.Resource.Id,
+			"name":                 o.Resource.Name,
+			"account":              o.Resource.Account,
+			"account_id":           o.Resource.AccountId,
+			"cloud_account_groups": o.Resource.CloudAccountGroups,
+			"region":               o.Resource.Region,
+			"region_id":            o.Resource.RegionId,
+			"resource_type":        o.Resource.ResourceType,
+			"resource_api_name":    o.Resource.ResourceApiName,
+			"url":                  o.Resource.Url,
+			"cloud_type":           o.Resource.CloudType,
+			"tags":                 flattenAlertTags(o.Resource.Tags),
+		}},
+		"risk_detail": []interface{}{map[string]interface{}{
+			"rating":         o.Risk.Rating,
+			"score":          o.Risk.Score,
+			"risk_score":     o.Risk.RiskScore.Score,
+			"max_risk_score": o.Risk.RiskScore.MaxScore,
+		}},
+		"history": history,
+		"investigate_options": []interface{}{map[string]interface{}{
+			"search_id": o.InvestigateOptions.SearchId,
+			"start_ts":  o.InvestigateOptions.StartTs,
+			"end_ts":    o.InvestigateOptions.EndTs,
+		}},
+	}
+}
+
+/*
+flattenAlertTags returns resource tags as a map.
+
+Depending on the cloud, the API returns tags either as an object or as a
+list of key / value objects.
+*/
+func flattenAlertTags(v interface{}) map[string]interface{} {
+	ans := make(map[string]interface{})
+
+	switch x := v.(type) {
+	case map[string]interface{}:
+		for key, val := range x {
+			ans[key] = fmt.Sprint(val)
+		}
+	case []interface{}:
+		for _, item := range x {
+			tag, ok := item.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			key, _ := tag["key"].(string)
+			if key == "" {
+				continue
+			}
+			if val, ok := tag["value"]; ok && val != nil {
+				ans[key] = fmt.Sprint(val)
+			} else {
+				ans[key] = ""
+			}
+		}
+	}
+
+	return ans
+}
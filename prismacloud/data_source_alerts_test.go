@@ -1,9 +1,14 @@
 package prismacloud
 
 import (
+	"fmt"
 	"testing"
 
+	"github.com/terraform-providers/terraform-provider-prismacloud/internal/fakeapi"
+
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"golang.org/x/net/context"
 )
 
 func TestAccDsAbsoluteAlerts(t *testing.T) {
@@ -92,3 +97,122 @@ data "prismacloud_alerts" "test" {
 
 	return ""
 }
+
+func testPutAlerts(s *fakeapi.Server, n int) {
+	for i := 0; i < n; i++ {
+		s.Alerts.Put(map[string]interface{}{
+			"status": "open",
+			"policy": map[string]interface{}{
+				"policyId":   fmt.Sprintf("policy-%d", i),
+				"policyType": "config",
+			},
+			"resource": map[string]interface{}{
+				"rrn":                fmt.Sprintf("rrn::ec2:us-east-1:123456789012::instance:i-%d", i),
+				"account":            "prod",
+				"cloudAccountGroups": []string{"group-1"},
+				"region":             "AWS Virginia",
+				"cloudType":          "aws",
+				"resourceTags":       []interface{}{map[string]interface{}{"key": "env", "value": "prod"}},
+			},
+			"riskDetail": map[string]interface{}{
+				"rating":    "F",
+				"riskScore": map[string]interface{}{"score": 40, "maxScore": 100},
+			},
+			"history": []interface{}{
+				map[string]interface{}{"status": "open", "modifiedBy": "Prisma Cloud System Admin", "modifiedOn": 1600000000000},
+			},
+			"investigateOptions": map[string]interface{}{"searchId": fmt.Sprintf("search-%d", i)},
+		})
+	}
+}
+
+func TestDsAlertsPagingFakeApi(t *testing.T) {
+	s, client := testFakeClient(t, nil)
+	testPutAlerts(s, 5)
+	ds := dataSourceAlerts()
+
+	for _, tc := range []struct {
+		params map[string]interface{}
+		count  int
+		pages  int
+		more   bool
+	}{
+		{map[string]interface{}{}, 2, 1, true},
+		{map[string]interface{}{"max_pages": 0}, 5, 3, false},
+		{map[string]interface{}{"max_pages": 2}, 4, 2, true},
+		{map[string]interface{}{"max_pages": 0, "max_results": 3}, 3, 2, true},
+	} {
+		raw := map[string]interface{}{
+			"limit": 2,
+			"time_range": []interface{}{map[string]interface{}{
+				"to_now": []interface{}{map[string]interface{}{"unit": "epoch"}},
+			}},
+		}
+		for k, v := range tc.params {
+			raw[k] = v
+		}
+		before := s.Count("POST", "/v2/alert")
+
+		d := schema.TestResourceDataRaw(t, ds.Schema, raw)
+		if diags := ds.ReadContext(context.Background(), d, client); diags.HasError() {
+			t.Fatalf("%v: error in read: %v", tc.params, diags)
+		}
+
+		if n := d.Get("listing.#").(int); n != tc.count {
+			t.Errorf("%v: got %d alerts, expected %d", tc.params, n, tc.count)
+		}
+		if n := s.Count("POST", "/v2/alert") - before; n != tc.pages {
+			t.Errorf("%v: got %d pages, expected %d", tc.params, n, tc.pages)
+		}
+		if more := d.Get("page_token").(string) != ""; more != tc.more {
+			t.Errorf("%v: page token is %q", tc.params, d.Get("page_token"))
+		}
+		if n := d.Get("total").(int); n != 5 {
+			t.Errorf("%v: total is %d, expected 5", tc.params, n)
+		}
+	}
+}
+
+func TestDsAlertsNestedFakeApi(t *testing.T) {
+	s, client := testFakeClient(t, nil)
+	testPutAlerts(s, 1)
+	ds := dataSourceAlerts()
+
+	d := schema.TestResourceDataRaw(t, ds.Schema, map[string]interface{}{
+		"detailed": true,
+		"time_range": []interface{}{map[string]interface{}{
+			"to_now": []interface{}{map[string]interface{}{"unit": "epoch"}},
+		}},
+	})
+	if diags := ds.ReadContext(context.Background(), d, client); diags.HasError() {
+		t.Fatalf("Error in read: %v", diags)
+	}
+
+	for key, want := range map[string]string{
+		"listing.0.policy.0.policy_id":                "policy-0",
+		"listing.0.policy.0.policy_type":              "config",
+		"listing.0.resource.0.rrn":                    "rrn::ec2:us-east-1:123456789012::instance:i-0",
+		"listing.0.resource.0.cloud_account_groups.0": "group-1",
+		"listing.0.resource.0.tags.env":               "prod",
+		"listing.0.risk_detail.0.rating":              "F",
+		"listing.0.history.0.modified_by":             "Prisma Cloud System Admin",
+		"listing.0.investigate_options.0.search_id":   "search-0",
+	} {
+		if got := fmt.Sprint(d.Get(key)); got != want {
+			t.Errorf("%s is %q, expected %q", key, got, want)
+		}
+	}
+	if got := d.Get("listing.0.risk_detail.0.risk_score").(int); got != 40 {
+		t.Errorf("risk_score is %d, expected 40", got)
+	}
+}
+
+func TestFlattenAlertTags(t *testing.T) {
+	got := flattenAlertTags(map[string]interface{}{"env": "prod", "count": 2.0})
+	if got["env"] != "prod" || got["count"] != "2" {
+		t.Errorf("Got %v from a tag object", got)
+	}
+	if got = flattenAlertTags(nil); len(got) != 0 {
+		t.Errorf("Got %v from no tags", got)
+	}
+}
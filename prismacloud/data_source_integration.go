@@ -5,9 +5,6 @@ import (
 	pc "github.com/paloaltonetworks/prisma-cloud-go"
 	"github.com/paloaltonetworks/prisma-cloud-go/integration"
 	"golang.org/x/net/context"
-	"math/rand"
-	"strings"
-	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
@@ -370,30 +367,28 @@ func dataSourceIntegrationRead(ctx context.Context, d *schema.ResourceData, meta
 
 	if id == "" {
 		name := d.Get("name").(string)
-		id1, err := PollApiUntilSuccessRead(func() (string, error) {
-			id1, err1 := integration.Identify(client, name, prismaIdRequired)
-			return id1, err1
-		})
-		id = id1
-		if err != nil {
+		if diags := PollApiWhileThrottled(ctx, d.Timeout(schema.TimeoutRead), func() error {
+			id, err = integration.Identify(client, name, prismaIdRequired)
+			return err
+		}); diags.HasError() {
 			if err == pc.ObjectNotFoundError {
 				d.SetId("")
 				return nil
 			}
-			return diag.FromErr(err)
+			return diags
 		}
 	}
 
-	o, err := PollApiUntilSuccessReadIntegration(func() (integration.Integration, error) {
-		o, err := integration.Get(client, id, prismaIdRequired)
-		return o, err
-	})
-	if err != nil {
+	var o integration.Integration
+	if diags := PollApiWhileThrottled(ctx, d.Timeout(schema.TimeoutRead), func() error {
+		o, err = integration.Get(client, id, prismaIdRequired)
+		return err
+	}); diags.HasError() {
 		if err == pc.ObjectNotFoundError {
 			d.SetId("")
 			return nil
 		}
-		return diag.FromErr(err)
+		return diags
 	}
 
 	d.SetId(o.Id)
@@ -401,20 +396,3 @@ func dataSourceIntegrationRead(ctx context.Context, d *schema.ResourceData, meta
 
 	return nil
 }
-
-type PollerCustom1 func() (string, error)
-
-func PollApiUntilSuccessRead(p PollerCustom1) (string, error) {
-	for {
-		if o, err := p(); err == nil {
-			return o, nil
-		} else if "429" == strings.Split(err.Error(), " ")[0] {
-			waitingTime := rand.Intn(5) + 1
-			time.Sleep(time.Duration(waitingTime) * time.Second)
-		} else {
-			return o, err
-
-		}
-	}
-	return "", nil
-}
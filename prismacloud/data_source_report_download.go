@@ -0,0 +1,165 @@
+package prismacloud
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	pc "github.com/paloaltonetworks/prisma-cloud-go"
+	"github.com/paloaltonetworks/prisma-cloud-go/report"
+	"golang.org/x/net/context"
+)
+
+// Report statuses that generation ends in.
+const (
+	reportStatusCompleted = "completed"
+	reportStatusFailed    = "failed"
+)
+
+func dataSourceReportDownload() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceReportDownloadRead,
+
+		Timeouts: &schema.ResourceTimeout{
+			Read: schema.DefaultTimeout(20 * time.Minute),
+		},
+
+		Schema: map[string]*schema.Schema{
+			// Input.
+			"report_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Report ID",
+			},
+			"output_path": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Local path to write the report file to",
+			},
+
+			// Output.
+			"name": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Report name",
+			},
+			"report_type": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Report type",
+			},
+			"status": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Report status",
+			},
+			"sha256": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Hex encoded SHA-256 checksum of the report file",
+			},
+			"size": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "Size of the report file in bytes",
+			},
+		},
+	}
+}
+
+// reportNotReadyError is returned while a report is still being generated.
+type reportNotReadyError struct {
+	status string
+}
+
+func (e reportNotReadyError) Error() string {
+	return fmt.Sprintf("report status is %q", e.status)
+}
+
+/*
+waitForReport polls a report until its generation is completed.
+
+Generation that fails is an error.  Any other status, such as a report that
+is queued or in progress, is polled again until the timeout elapses.
+*/
+func waitForReport(ctx context.Context, client *pc.Client, timeout time.Duration, id string) (report.Report, diag.Diagnostics) {
+	var obj report.Report
+	diags := pollApi(ctx, pollConfig{
+		timeout: timeout,
+		retryable: func(err error) bool {
+			var pending reportNotReadyError
+			return errors.As(err, &pending) || isThrottledError(err)
+		},
+	}, func() error {
+		var err error
+		if obj, err = report.Get(client, id); err != nil {
+			return err
+		}
+
+		switch strings.ToLower(obj.Status) {
+		case reportStatusCompleted:
+			return nil
+		case reportStatusFailed:
+			return fmt.Errorf("report %q failed to generate", id)
+		}
+		return reportNotReadyError{status: obj.Status}
+	})
+
+	return obj, diags
+}
+
+// downloadReport returns the generated file of a report.
+func downloadReport(client *pc.Client, id string) ([]byte, error) {
+	path := make([]string, 0, len(report.Suffix)+2)
+	path = append(path, report.Suffix...)
+	path = append(path, id, "download")
+
+	return client.Communicate("GET", path, nil, nil, nil)
+}
+
+func dataSourceReportDownloadRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*pc.Client)
+	id := d.Get("report_id").(string)
+	outputPath := d.Get("output_path").(string)
+	timeout := d.Timeout(schema.TimeoutRead)
+
+	obj, diags := waitForReport(ctx, client, timeout, id)
+	if diags.HasError() {
+		return diags
+	}
+
+	var b []byte
+	if diags = PollApiWhileThrottled(ctx, timeout, func() error {
+		var err error
+		b, err = downloadReport(client, id)
+		return err
+	}); diags.HasError() {
+		return diags
+	}
+
+	if err := os.MkdirAll(filepath.Dir(outputPath), 0755); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := ioutil.WriteFile(outputPath, b, 0644); err != nil {
+		return diag.FromErr(err)
+	}
+
+	sum := sha256.Sum256(b)
+
+	d.SetId(id)
+	d.Set("name", obj.Name)
+	d.Set("report_type", obj.Type)
+	d.Set("status", obj.Status)
+	d.Set("sha256", hex.EncodeToString(sum[:]))
+	d.Set("size", len(b))
+
+	return nil
+}
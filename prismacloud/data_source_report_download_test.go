@@ -0,0 +1,76 @@
+package prismacloud
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/terraform-providers/terraform-provider-prismacloud/internal/fakeapi"
+	"golang.org/x/net/context"
+)
+
+func TestReportDownloadFakeApi(t *testing.T) {
+	shortPollDelays(t)
+	s, client := testFakeClient(t, nil)
+	ds := dataSourceReportDownload()
+
+	id := s.Reports.Put(map[string]interface{}{"name": "evidence", "type": "RIS", "status": "queued"})
+	s.ReportFiles[id] = []byte("report")
+
+	// Generation completes on the third poll.
+	get := s.Reports.GetHandler()
+	var gets int
+	s.Handle("GET", "/report/{id}", func(r *fakeapi.Request) (interface{}, error) {
+		gets++
+		if gets == 2 {
+			s.Reports.Put(map[string]interface{}{"id": id, "name": "evidence", "type": "RIS", "status": "in_progress"})
+		} else if gets == 3 {
+			s.Reports.Put(map[string]interface{}{"id": id, "name": "evidence", "type": "RIS", "status": "completed"})
+		}
+		return get(r)
+	})
+
+	path := filepath.Join(t.TempDir(), "out", "evidence.pdf")
+	d := schema.TestResourceDataRaw(t, ds.Schema, map[string]interface{}{
+		"report_id":   id,
+		"output_path": path,
+	})
+	if diags := ds.ReadContext(context.Background(), d, client); diags.HasError() {
+		t.Fatalf("Error in read: %v", diags)
+	}
+	if gets != 3 {
+		t.Errorf("Report was polled %d times, expected 3", gets)
+	}
+
+	b, err := ioutil.ReadFile(path)
+	if err != nil || string(b) != "report" {
+		t.Fatalf("Report file is %q (%v)", b, err)
+	}
+	if got := d.Get("sha256"); got != "845e91831319e89c4d656bdb80c278ac09a7230d61e5dfd2e1b1fbb436ac8917" {
+		t.Errorf("sha256 is %q", got)
+	}
+	if d.Get("size") != 6 || d.Get("status") != "completed" || d.Get("name") != "evidence" {
+		t.Errorf("size is %v, status is %v and name is %v", d.Get("size"), d.Get("status"), d.Get("name"))
+	}
+}
+
+func TestReportDownloadFailedFakeApi(t *testing.T) {
+	shortPollDelays(t)
+	s, client := testFakeClient(t, nil)
+	ds := dataSourceReportDownload()
+
+	id := s.Reports.Put(map[string]interface{}{"name": "evidence", "status": "failed"})
+
+	path := filepath.Join(t.TempDir(), "evidence.pdf")
+	d := schema.TestResourceDataRaw(t, ds.Schema, map[string]interface{}{
+		"report_id":   id,
+		"output_path": path,
+	})
+	if diags := ds.ReadContext(context.Background(), d, client); !diags.HasError() {
+		t.Fatalf("Read of a failed report did not fail")
+	}
+	if s.Count("GET", "/report/"+id+"/download") != 0 {
+		t.Errorf("Failed report was downloaded")
+	}
+}
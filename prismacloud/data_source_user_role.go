@@ -3,6 +3,7 @@ package prismacloud
 import (
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	pc "github.com/paloaltonetworks/prisma-cloud-go"
 	"github.com/paloaltonetworks/prisma-cloud-go/user/role"
 	"golang.org/x/net/context"
@@ -35,10 +36,11 @@ func dataSourceUserRole() *schema.Resource {
 				Default:     false,
 			},
 			"max_retries": {
-				Type:        schema.TypeInt,
-				Optional:    true,
-				Description: "Maximum number of retries for read API calls",
-				Default:     10,
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Description:  "Maximum number of retries for read API calls",
+				Default:      10,
+				ValidateFunc: validation.IntAtLeast(0),
 			},
 			// Output.
 			"description": {
@@ -137,13 +139,14 @@ func dataSourceUserRoleRead(ctx context.Context, d *schema.ResourceData, meta in
 	var err error
 	id := d.Get("role_id").(string)
 	backoffRetry := d.Get("backoff_retry").(bool)
-	// Helper function to handle backoff retry
+	// Helper function to handle backoff retry.  As before, one more retry
+	// than max_retries is made, so 0 still allows one.
 	executeWithBackoff := func(operation func() error) diag.Diagnostics {
-		maxRetries := d.Get("max_retries").(int)
-		backoff := &BackOffRetry{
-			maxRetries: maxRetries,
-		}
-		return backoff.PollApiByBackoffUntilSuccess(operation)
+		return pollApi(ctx, pollConfig{
+			timeout:    d.Timeout(schema.TimeoutRead),
+			maxRetries: d.Get("max_retries").(int) + 1,
+			retryable:  isRetryableError,
+		}, operation)
 	}
 	if id == "" {
 		name := d.Get("name").(string)
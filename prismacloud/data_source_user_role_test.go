@@ -6,6 +6,8 @@ import (
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"golang.org/x/net/context"
 )
 
 func TestAccDsUserRole(t *testing.T) {
@@ -39,3 +41,25 @@ data "prismacloud_user_role" "test" {
 }
 `, name)
 }
+
+func TestDsUserRoleMaxRetriesFakeApi(t *testing.T) {
+	shortPollDelays(t)
+	s, client := testFakeClient(t, nil)
+	ds := dataSourceUserRole()
+
+	// A max_retries of 0 still allows one retry before the final lookup.
+	d := schema.TestResourceDataRaw(t, ds.Schema, map[string]interface{}{
+		"name":          "missing",
+		"backoff_retry": true,
+		"max_retries":   0,
+	})
+	if diags := ds.ReadContext(context.Background(), d, client); diags.HasError() {
+		t.Fatalf("Error in read: %v", diags)
+	}
+	if n := s.Count("GET", "/user/role/name"); n != 3 {
+		t.Errorf("User role names were listed %d times, expected 3", n)
+	}
+	if d.Id() != "" {
+		t.Errorf("ID is %q for a missing role", d.Id())
+	}
+}
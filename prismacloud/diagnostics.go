@@ -0,0 +1,288 @@
+package prismacloud
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	pc "github.com/paloaltonetworks/prisma-cloud-go"
+	"golang.org/x/net/context"
+)
+
+// apiErrorTranslation is the readable form of a Prisma Cloud i18n key.
+type apiErrorTranslation struct {
+	summary string
+
+	// detail is formatted with the error subject, if any.
+	detail func(subject string) string
+
+	// attributes are the params the error most likely refers to, in order
+	// of preference, used when the subject does not name a param.
+	attributes []string
+}
+
+// apiErrorTranslations maps i18n keys to their translations.  Keys are
+// matched as a suffix of the i18n key, the same way the SDK matches them.
+var apiErrorTranslations = map[string]apiErrorTranslation{
+	"invalid_rql": {
+		summary: "Invalid RQL query",
+		detail: func(subject string) string {
+			return "Prisma Cloud could not parse the RQL query" + quoteSubject(subject) + ".  Check the query syntax, for example by running the query on the Investigate page first."
+		},
+		attributes: []string{"query", "criteria"},
+	},
+	"duplicate_policy_name": {
+		summary: "Policy name is already in use",
+		detail: func(subject string) string {
+			return "A policy named" + quoteSubject(subject) + " already exists.  Policy names must be unique across both custom and system default policies, so either choose another name or import the existing policy."
+		},
+		attributes: []string{"name"},
+	},
+	"_already_exists": {
+		summary: "Object already exists",
+		detail: func(subject string) string {
+			return "An object" + quoteSubject(subject) + " with the same name already exists.  Either choose another name or import the existing object."
+		},
+		attributes: []string{"name"},
+	},
+	"overlapping_cidr": {
+		summary: "CIDR overlaps an existing CIDR",
+		detail: func(subject string) string {
+			return "The CIDR" + quoteSubject(subject) + " overlaps with a CIDR that is already configured.  Remove the overlap from the list, or from the existing configuration."
+		},
+		attributes: []string{"cidrs", "cidr", "ip_cidr"},
+	},
+	"invalid_permission_group_id": {
+		summary: "Permission group not found",
+		detail: func(subject string) string {
+			return "The permission group" + quoteSubject(subject) + " does not exist.  Check that the permission group has not been deleted outside of Terraform."
+		},
+		attributes: []string{"permission_group_id", "role_type"},
+	},
+	"account_group_not_found": {
+		summary: "Account group not found",
+		detail: func(subject string) string {
+			return "The account group" + quoteSubject(subject) + " does not exist.  Check that the account group ID is correct and that the group has not been deleted outside of Terraform."
+		},
+		attributes: []string{"account_group_ids", "account_group_id", "group_ids", "account_groups", "default_account_group_id"},
+	},
+}
+
+// apiErrorKeys are the keys of apiErrorTranslations, longest first, so that
+// the most specific suffix is matched.
+var apiErrorKeys = func() []string {
+	ans := make([]string, 0, len(apiErrorTranslations))
+	for key := range apiErrorTranslations {
+		ans = append(ans, key)
+	}
+	sort.Slice(ans, func(i, j int) bool {
+		if len(ans[i]) != len(ans[j]) {
+			return len(ans[i]) > len(ans[j])
+		}
+		return ans[i] < ans[j]
+	})
+	return ans
+}()
+
+// apiSentinelErrors are the errors the SDK returns in place of the error
+// list for some i18n keys, along with the key they stand for.
+var apiSentinelErrors = []struct {
+	err error
+	key string
+}{
+	{pc.InvalidPermissionGroupIdError, "invalid_permission_group_id"},
+	{pc.AccountGroupNotFoundError, "account_group_not_found"},
+	{pc.OverlappingCIDRError, "overlapping_cidr"},
+	{pc.AlreadyExistsError, "_already_exists"},
+}
+
+// apiErrorPattern matches a single error in the text of a
+// pc.PrismaCloudErrorList, after its leading "Error(msg:".
+var apiErrorPattern = regexp.MustCompile(`(?s)^(\S*) severity:(\S*) subject:(.*)\)`)
+
+// apiError is one error reported by Prisma Cloud.
+type apiError struct {
+	key     string
+	subject string
+}
+
+/*
+parseApiErrors extracts the Prisma Cloud errors from an error message.
+
+Errors reach resources either as a pc.PrismaCloudErrorList, often wrapped by
+the SDK or the provider, or as one of the SDK's sentinel errors.  Both are
+recognized from the message text so that the translation works no matter how
+the error was wrapped on its way up.
+*/
+func parseApiErrors(msg string) []apiError {
+	var ans []apiError
+
+	for _, part := range strings.Split(msg, "Error(msg:")[1:] {
+		if m := apiErrorPattern.FindStringSubmatch(part); m != nil {
+			ans = append(ans, apiError{key: m[1], subject: strings.TrimSpace(m[3])})
+		}
+	}
+
+	if len(ans) == 0 {
+		for _, e := range apiSentinelErrors {
+			if strings.Contains(msg, e.err.Error()) {
+				ans = append(ans, apiError{key: e.key})
+				break
+			}
+		}
+	}
+
+	return ans
+}
+
+// translateApiError returns the translation for the given i18n key.
+func translateApiError(key string) (apiErrorTranslation, bool) {
+	for _, k := range apiErrorKeys {
+		if strings.HasSuffix(key, k) {
+			return apiErrorTranslations[k], true
+		}
+	}
+
+	return apiErrorTranslation{}, false
+}
+
+/*
+apiDiagnostics replaces error diagnostics holding known Prisma Cloud errors
+with readable ones.
+
+The original message is kept at the end of the detail, since it is what Palo
+Alto support will ask for.  If the error subject names a param of the given
+schema, or the error is known to relate to one, the diagnostic points at it.
+*/
+func apiDiagnostics(sm map[string]*schema.Schema, diags diag.Diagnostics) diag.Diagnostics {
+	var ans diag.Diagnostics
+
+	for _, d := range diags {
+		if d.Severity != diag.Error || len(d.AttributePath) > 0 {
+			ans = append(ans, d)
+			continue
+		}
+
+		var translated bool
+		for _, e := range parseApiErrors(d.Summary) {
+			t, ok := translateApiError(e.key)
+			if !ok {
+				continue
+			}
+			translated = true
+
+			nd := diag.Diagnostic{
+				Severity: diag.Error,
+				Summary:  t.summary,
+				Detail:   fmt.Sprintf("%s\n\nPrisma Cloud returned: %s", t.detail(e.subject), d.Summary),
+			}
+			if path := subjectAttributePath(sm, e.subject); path != nil {
+				nd.AttributePath = path
+			} else {
+				for _, name := range t.attributes {
+					if path = findAttributePath(sm, name); path != nil {
+						nd.AttributePath = path
+						break
+					}
+				}
+			}
+			ans = append(ans, nd)
+		}
+
+		if !translated {
+			ans = append(ans, d)
+		}
+	}
+
+	return ans
+}
+
+// subjectAttributePath returns the path of the param named by an error
+// subject such as "name" or "rule.criteria", if the schema has one.
+func subjectAttributePath(sm map[string]*schema.Schema, subject string) cty.Path {
+	if subject == "" || strings.ContainsAny(subject, " =:/") {
+		return nil
+	}
+
+	var path cty.Path
+	for _, part := range strings.Split(subject, ".") {
+		s, ok := sm[toSnakeCase(part)]
+		if !ok {
+			return nil
+		}
+		path = path.GetAttr(toSnakeCase(part))
+
+		sm = nil
+		if r, ok := s.Elem.(*schema.Resource); ok && s.Type == schema.TypeList {
+			path = path.IndexInt(0)
+			sm = r.Schema
+		}
+	}
+
+	return path
+}
+
+// findAttributePath returns the path of the param with the given name,
+// looking at the top level params first and then into the first element of
+// nested list blocks.
+func findAttributePath(sm map[string]*schema.Schema, name string) cty.Path {
+	if _, ok := sm[name]; ok {
+		return cty.GetAttrPath(name)
+	}
+
+	keys := make([]string, 0, len(sm))
+	for k := range sm {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		s := sm[k]
+		r, ok := s.Elem.(*schema.Resource)
+		if !ok || s.Type != schema.TypeList {
+			continue
+		}
+		if sub := findAttributePath(r.Schema, name); sub != nil {
+			return append(cty.GetAttrPath(k).IndexInt(0), sub...)
+		}
+	}
+
+	return nil
+}
+
+func quoteSubject(subject string) string {
+	if subject == "" {
+		return ""
+	}
+	return fmt.Sprintf(" %q", subject)
+}
+
+var snakeCaseBoundary = regexp.MustCompile(`([a-z0-9])([A-Z])`)
+
+func toSnakeCase(s string) string {
+	return strings.ToLower(snakeCaseBoundary.ReplaceAllString(s, "${1}_${2}"))
+}
+
+// withApiDiagnostics makes the CRUD functions of the given resource or data
+// source return readable diagnostics for known Prisma Cloud errors.
+func withApiDiagnostics(r *schema.Resource) *schema.Resource {
+	wrap := func(f func(context.Context, *schema.ResourceData, interface{}) diag.Diagnostics) func(context.Context, *schema.ResourceData, interface{}) diag.Diagnostics {
+		if f == nil {
+			return nil
+		}
+		return func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+			return apiDiagnostics(r.Schema, f(ctx, d, meta))
+		}
+	}
+
+	r.CreateContext = wrap(r.CreateContext)
+	r.ReadContext = wrap(r.ReadContext)
+	r.UpdateContext = wrap(r.UpdateContext)
+	r.DeleteContext = wrap(r.DeleteContext)
+
+	return r
+}
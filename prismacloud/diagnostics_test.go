@@ -0,0 +1,121 @@
+package prismacloud
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	pc "github.com/paloaltonetworks/prisma-cloud-go"
+	"github.com/paloaltonetworks/prisma-cloud-go/policy"
+	"golang.org/x/net/context"
+)
+
+func TestParseApiErrors(t *testing.T) {
+	list := pc.PrismaCloudErrorList{
+		Method:     "POST",
+		StatusCode: 400,
+		Path:       "https://api.prismacloud.io/policy",
+		Errors: []pc.PrismaCloudError{
+			{Message: "invalid_rql", Severity: "error", Subject: "config from (oops"},
+			{Message: "duplicate_policy_name", Severity: "error", Subject: "name"},
+		},
+	}
+
+	for _, tc := range []struct {
+		err  error
+		want []apiError
+	}{
+		{list, []apiError{{"invalid_rql", "config from (oops"}, {"duplicate_policy_name", "name"}}},
+		{fmt.Errorf("create failed: %w", list), []apiError{{"invalid_rql", "config from (oops"}, {"duplicate_policy_name", "name"}}},
+		{pc.AccountGroupNotFoundError, []apiError{{"account_group_not_found", ""}}},
+		{pc.AlreadyExistsError, []apiError{{"_already_exists", ""}}},
+		{errors.New("something else"), nil},
+	} {
+		got := parseApiErrors(tc.err.Error())
+		if fmt.Sprint(got) != fmt.Sprint(tc.want) {
+			t.Errorf("parseApiErrors(%q) = %v, expected %v", tc.err, got, tc.want)
+		}
+	}
+}
+
+func TestApiDiagnosticsAttributePath(t *testing.T) {
+	sm := resourcePolicy().Schema
+
+	for _, tc := range []struct {
+		err  error
+		want cty.Path
+	}{
+		{
+			pc.PrismaCloudErrorList{Errors: []pc.PrismaCloudError{{Message: "duplicate_policy_name", Subject: "name"}}},
+			cty.GetAttrPath("name"),
+		},
+		{
+			pc.PrismaCloudErrorList{Errors: []pc.PrismaCloudError{{Message: "invalid_rql", Subject: "config from cloud.resource where"}}},
+			cty.GetAttrPath("rule").IndexInt(0).GetAttr("criteria"),
+		},
+		{
+			pc.PrismaCloudErrorList{Errors: []pc.PrismaCloudError{{Message: "invalid_rql", Subject: "rule.criteria"}}},
+			cty.GetAttrPath("rule").IndexInt(0).GetAttr("criteria"),
+		},
+		{
+			pc.PrismaCloudErrorList{Errors: []pc.PrismaCloudError{{Message: "policy_invalid_rql", Subject: "policyType"}}},
+			cty.GetAttrPath("policy_type"),
+		},
+	} {
+		diags := apiDiagnostics(sm, diag.FromErr(tc.err))
+		if len(diags) != 1 {
+			t.Fatalf("Got %d diagnostics for %q, expected 1", len(diags), tc.err)
+		}
+		if !diags[0].AttributePath.Equals(tc.want) {
+			t.Errorf("Diagnostic for %q points at %#v, expected %#v", tc.err, diags[0].AttributePath, tc.want)
+		}
+		if !strings.Contains(diags[0].Detail, tc.err.Error()) {
+			t.Errorf("Diagnostic detail %q is missing the original error", diags[0].Detail)
+		}
+	}
+}
+
+func TestApiDiagnosticsUnknownErrors(t *testing.T) {
+	diags := diag.Diagnostics{
+		diag.Diagnostic{Severity: diag.Warning, Summary: "account_group_not_found"},
+		diag.Diagnostic{Severity: diag.Error, Summary: "unrelated failure"},
+	}
+
+	got := apiDiagnostics(resourceAccountGroup().Schema, diags)
+	if fmt.Sprint(got) != fmt.Sprint(diags) {
+		t.Errorf("Diagnostics changed to %v", got)
+	}
+}
+
+func TestApiDiagnosticsFakeApi(t *testing.T) {
+	s, client := testFakeClient(t, nil)
+	s.Policies.Put(map[string]interface{}{"name": "taken"})
+	r := Provider().ResourcesMap["prismacloud_policy"]
+
+	d := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{
+		"name":        "taken",
+		"policy_type": policy.PolicyTypeConfig,
+		"cloud_type":  "aws",
+		"severity":    policy.SeverityLow,
+		"rule": []interface{}{map[string]interface{}{
+			"name":      "taken",
+			"rule_type": policy.RuleTypeConfig,
+			"criteria":  "config from cloud.resource where api.name = 'aws-ec2-describe-instances'",
+		}},
+	})
+
+	diags := r.CreateContext(context.Background(), d, client)
+	if !diags.HasError() {
+		t.Fatalf("Expected an error creating a duplicate policy")
+	}
+	if diags[0].Summary != "Policy name is already in use" {
+		t.Errorf("Got summary %q", diags[0].Summary)
+	}
+	if !diags[0].AttributePath.Equals(cty.GetAttrPath("name")) {
+		t.Errorf("Diagnostic points at %#v, expected name", diags[0].AttributePath)
+	}
+}
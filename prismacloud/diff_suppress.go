@@ -0,0 +1,65 @@
+package prismacloud
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+
+	"github.com/terraform-providers/terraform-provider-prismacloud/internal/rql"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// decodeJson decodes a JSON document, keeping numbers as they are written.
+func decodeJson(s string) (interface{}, bool) {
+	var v interface{}
+
+	dec := json.NewDecoder(bytes.NewReader([]byte(s)))
+	dec.UseNumber()
+	if err := dec.Decode(&v); err != nil || dec.More() {
+		return nil, false
+	}
+
+	return v, true
+}
+
+// jsonEquivalent returns true if both strings are the same JSON document,
+// ignoring formatting and the order of object keys.
+func jsonEquivalent(a, b string) bool {
+	va, ok := decodeJson(a)
+	if !ok {
+		return false
+	}
+	vb, ok := decodeJson(b)
+	if !ok {
+		return false
+	}
+
+	return reflect.DeepEqual(va, vb)
+}
+
+// suppressEquivalentJson suppresses diffs between equivalent JSON documents.
+func suppressEquivalentJson(k, old, new string, d *schema.ResourceData) bool {
+	return old == new || jsonEquivalent(old, new)
+}
+
+// suppressEquivalentRql suppresses diffs between RQL queries that only differ
+// in whitespace, keyword case and quote style.
+func suppressEquivalentRql(k, old, new string, d *schema.ResourceData) bool {
+	return rql.Equivalent(old, new)
+}
+
+// suppressEquivalentCriteria suppresses diffs in policy rule criteria, which
+// are either JSON (network, anomaly and data policies) or a saved search ID
+// or RQL query.
+func suppressEquivalentCriteria(k, old, new string, d *schema.ResourceData) bool {
+	if old == new {
+		return true
+	}
+
+	if _, ok := decodeJson(old); ok {
+		return jsonEquivalent(old, new)
+	}
+
+	return rql.Equivalent(old, new)
+}
@@ -0,0 +1,99 @@
+package prismacloud
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"golang.org/x/net/context"
+)
+
+func TestSuppressEquivalentCriteria(t *testing.T) {
+	for _, tc := range []struct {
+		old, new string
+		want     bool
+	}{
+		{
+			`{"amount":1,"operator":"gt","filters":[{"name":"a","value":"b"}]}`,
+			"{\n  \"filters\": [{\"value\": \"b\", \"name\": \"a\"}],\n  \"operator\": \"gt\",\n  \"amount\": 1\n}",
+			true,
+		},
+		{
+			`{"filters":[{"name":"a"},{"name":"b"}]}`,
+			`{"filters":[{"name":"b"},{"name":"a"}]}`,
+			false,
+		},
+		{
+			`{"amount":1}`,
+			`{"amount":1.5}`,
+			false,
+		},
+		{
+			"config from cloud.resource where api.name = 'aws-ec2-describe-instances' AND json.rule = publicIpAddress exists",
+			"config from cloud.resource\n  where api.name = \"aws-ec2-describe-instances\"\n  and json.rule = publicIpAddress EXISTS\n",
+			true,
+		},
+		{
+			"config from cloud.resource where api.name = 'aws-ec2-describe-instances'",
+			"config from cloud.resource where api.name = 'aws-ec2-describe-images'",
+			false,
+		},
+		{
+			"ba3ebbd7-4f8a-4b54-9b7c-e0b7a8e5b0a1",
+			"ba3ebbd7-4f8a-4b54-9b7c-e0b7a8e5b0a1",
+			true,
+		},
+		{
+			`{"amount":1}`,
+			"config from cloud.resource",
+			false,
+		},
+	} {
+		if got := suppressEquivalentCriteria("rule.0.criteria", tc.old, tc.new, nil); got != tc.want {
+			t.Errorf("%q to %q: suppressed is %t, expected %t", tc.old, tc.new, got, tc.want)
+		}
+	}
+}
+
+func TestSuppressEquivalentJson(t *testing.T) {
+	if !suppressEquivalentJson("k", `{"a":{"b":[1,2]},"c":null}`, `{ "c": null, "a": { "b": [1, 2] } }`, nil) {
+		t.Errorf("Equivalent JSON was not suppressed")
+	}
+	if suppressEquivalentJson("k", `{"a":1}`, `{"a":1} {}`, nil) {
+		t.Errorf("Trailing document was suppressed")
+	}
+	if suppressEquivalentJson("k", "", `{}`, nil) {
+		t.Errorf("Adding JSON was suppressed")
+	}
+}
+
+func TestPolicyCriteriaNoDiff(t *testing.T) {
+	r := resourcePolicy()
+	raw := func(criteria, schemaString string) map[string]interface{} {
+		return map[string]interface{}{
+			"name":        "test",
+			"policy_type": "network",
+			"rule": []interface{}{map[string]interface{}{
+				"name":      "test",
+				"criteria":  criteria,
+				"rule_type": "Network",
+			}},
+			"remediation": []interface{}{map[string]interface{}{
+				"cli_script_json_schema_string": schemaString,
+			}},
+		}
+	}
+
+	d := schema.TestResourceDataRaw(t, r.Schema, raw(`{"amount":1,"operator":"gt"}`, `{"a":1,"b":2}`))
+	d.SetId("policy-id")
+
+	diff, err := r.Diff(context.Background(), d.State(), terraform.NewResourceConfigRaw(raw("{\"operator\": \"gt\", \"amount\": 1}", `{"b": 2, "a": 1}`)), nil)
+	if err != nil {
+		t.Fatalf("Error computing diff: %s", err)
+	}
+	for _, k := range []string{"rule.0.criteria", "remediation.0.cli_script_json_schema_string"} {
+		if diff != nil && diff.Attributes[k] != nil {
+			t.Errorf("Got a diff for %s: %#v", k, diff.Attributes[k])
+		}
+	}
+}
@@ -0,0 +1,410 @@
+package prismacloud
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"golang.org/x/net/context"
+
+	pc "github.com/paloaltonetworks/prisma-cloud-go"
+	"github.com/paloaltonetworks/prisma-cloud-go/alert/rule"
+	"github.com/paloaltonetworks/prisma-cloud-go/cloud/account"
+	accountv2 "github.com/paloaltonetworks/prisma-cloud-go/cloud/account-v2"
+	orgv2 "github.com/paloaltonetworks/prisma-cloud-go/cloud/account-v2/org"
+	"github.com/paloaltonetworks/prisma-cloud-go/cloud/account/group"
+	"github.com/paloaltonetworks/prisma-cloud-go/cloud/account/org"
+	"github.com/paloaltonetworks/prisma-cloud-go/compliance/standard"
+	"github.com/paloaltonetworks/prisma-cloud-go/data-security/datapattern"
+	"github.com/paloaltonetworks/prisma-cloud-go/data-security/dataprofile"
+	"github.com/paloaltonetworks/prisma-cloud-go/integration"
+	"github.com/paloaltonetworks/prisma-cloud-go/ip-address"
+	notification_template "github.com/paloaltonetworks/prisma-cloud-go/notification-template"
+	"github.com/paloaltonetworks/prisma-cloud-go/permission_group"
+	"github.com/paloaltonetworks/prisma-cloud-go/policy"
+	"github.com/paloaltonetworks/prisma-cloud-go/report"
+	"github.com/paloaltonetworks/prisma-cloud-go/rql/history"
+	"github.com/paloaltonetworks/prisma-cloud-go/trusted-alert-ip"
+	"github.com/paloaltonetworks/prisma-cloud-go/user/role"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// importNamePrefix marks an import ID that names the object to import
+// instead of giving its ID.
+const importNamePrefix = "name:"
+
+// importMatch is an object that can be imported by name.
+type importMatch struct {
+	CloudType string
+	Id        string
+	Name      string
+}
+
+// importLister returns the objects that may be imported by name.
+type importLister func(c *pc.Client) ([]importMatch, error)
+
+// importNameLister returns the objects that may be imported by the given
+// name, for APIs that can filter their listing by name.
+type importNameLister func(c *pc.Client, name string) ([]importMatch, error)
+
+/*
+importByName returns an importer that accepts either the ID of an object or
+"name:<name>".
+
+Names are looked up in the listing returned by list.  As names are not always
+unique, importing a name that matches more than one object is an error that
+lists the IDs of the matching objects.
+*/
+func importByName(desc string, list importLister) *schema.ResourceImporter {
+	return importByListedName(desc, func(c *pc.Client, name string) ([]importMatch, error) {
+		return list(c)
+	})
+}
+
+// importByListedName is like importByName, but only lists the objects the
+// API returns for the name being imported.  The listing is still checked for
+// an exact match, as API filters may match more loosely.
+func importByListedName(desc string, list importNameLister) *schema.ResourceImporter {
+	return &schema.ResourceImporter{
+		StateContext: func(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+			if !strings.HasPrefix(d.Id(), importNamePrefix) {
+				return []*schema.ResourceData{d}, nil
+			}
+			name := strings.TrimPrefix(d.Id(), importNamePrefix)
+
+			listing, err := list(meta.(*pc.Client), name)
+			if err != nil {
+				return nil, err
+			}
+
+			id, err := resolveImportName(desc, name, listing)
+			if err != nil {
+				return nil, err
+			}
+			d.SetId(id)
+
+			return []*schema.ResourceData{d}, nil
+		},
+	}
+}
+
+/*
+importCloudAccountByName returns an importer for cloud accounts, whose IDs
+are "<cloud_type>:<account_id>".
+
+In place of the account ID, the account name may be given either on its own
+or as "name:<name>", so "aws:123456789012", "aws:prod" and "aws:name:prod"
+all import the same AWS account named "prod".  If the accounts can't be
+listed, an ID without the "name:" prefix is imported as an account ID.
+*/
+func importCloudAccountByName(list importLister) *schema.ResourceImporter {
+	return &schema.ResourceImporter{
+		StateContext: func(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+			tok := strings.SplitN(d.Id(), IdSeparator, 2)
+			if len(tok) != 2 || tok[0] == "" || tok[1] == "" {
+				return nil, fmt.Errorf("Import ID should be <cloud_type>:<account_id> or <cloud_type>:<name>, not %q", d.Id())
+			}
+			cloudType := tok[0]
+			name := strings.TrimPrefix(tok[1], importNamePrefix)
+			byName := name != tok[1]
+
+			listing, err := list(meta.(*pc.Client))
+			if err != nil {
+				if byName {
+					return nil, err
+				}
+				// Without a listing this can't be told apart from a name,
+				// so take it to be an account ID as before.
+				log.Printf("[WARN] Error listing cloud accounts, importing %q as an account ID: %s", d.Id(), err)
+				return []*schema.ResourceData{d}, nil
+			}
+
+			matches := make([]importMatch, 0, len(listing))
+			for _, o := range listing {
+				if o.CloudType != cloudType {
+					continue
+				}
+				if !byName && o.Id == name {
+					return []*schema.ResourceData{d}, nil
+				}
+				if o.Name == name {
+					matches = append(matches, o)
+				}
+			}
+
+			if !byName && len(matches) == 0 {
+				// Not a known name either, leave it to read to find the
+				// account by ID.
+				return []*schema.ResourceData{d}, nil
+			}
+
+			id, err := resolveImportName(cloudType+" cloud account", name, matches)
+			if err != nil {
+				return nil, err
+			}
+			d.SetId(TwoStringsToId(cloudType, id))
+
+			return []*schema.ResourceData{d}, nil
+		},
+	}
+}
+
+// resolveImportName returns the ID of the only object with the given name.
+func resolveImportName(desc, name string, listing []importMatch) (string, error) {
+	var ids []string
+	for _, o := range listing {
+		if o.Name == name {
+			ids = append(ids, o.Id)
+		}
+	}
+
+	switch len(ids) {
+	case 0:
+		return "", fmt.Errorf("No %s named %q was found", desc, name)
+	case 1:
+		return ids[0], nil
+	}
+
+	return "", fmt.Errorf("Found more than one %s named %q, import by ID instead: %s", desc, name, strings.Join(ids, ", "))
+}
+
+// Listers for importByListedName.
+
+func listPolicyImports(c *pc.Client, name string) ([]importMatch, error) {
+	list, err := policy.List(c, map[string]string{"policy.name": name})
+	if err != nil {
+		return nil, err
+	}
+
+	ans := make([]importMatch, 0, len(list))
+	for _, o := range list {
+		ans = append(ans, importMatch{Id: o.PolicyId, Name: o.Name})
+	}
+	return ans, nil
+}
+
+// Listers for importByName.
+
+func listAlertRuleImports(c *pc.Client) ([]importMatch, error) {
+	list, err := rule.List(c)
+	if err != nil {
+		return nil, err
+	}
+
+	ans := make([]importMatch, 0, len(list))
+	for _, o := range list {
+		ans = append(ans, importMatch{Id: o.PolicyScanConfigId, Name: o.Name})
+	}
+	return ans, nil
+}
+
+func listAccountGroupImports(c *pc.Client) ([]importMatch, error) {
+	list, err := group.List(c)
+	if err != nil {
+		return nil, err
+	}
+
+	ans := make([]importMatch, 0, len(list))
+	for _, o := range list {
+		ans = append(ans, importMatch{Id: o.Id, Name: o.Name})
+	}
+	return ans, nil
+}
+
+func listUserRoleImports(c *pc.Client) ([]importMatch, error) {
+	list, err := role.List(c)
+	if err != nil {
+		return nil, err
+	}
+
+	ans := make([]importMatch, 0, len(list))
+	for _, o := range list {
+		ans = append(ans, importMatch{Id: o.Id, Name: o.Name})
+	}
+	return ans, nil
+}
+
+func listReportImports(c *pc.Client) ([]importMatch, error) {
+	list, err := report.List(c)
+	if err != nil {
+		return nil, err
+	}
+
+	ans := make([]importMatch, 0, len(list))
+	for _, o := range list {
+		ans = append(ans, importMatch{Id: o.Id, Name: o.Name})
+	}
+	return ans, nil
+}
+
+func listComplianceStandardImports(c *pc.Client) ([]importMatch, error) {
+	list, err := standard.List(c)
+	if err != nil {
+		return nil, err
+	}
+
+	ans := make([]importMatch, 0, len(list))
+	for _, o := range list {
+		ans = append(ans, importMatch{Id: o.Id, Name: o.Name})
+	}
+	return ans, nil
+}
+
+func listPermissionGroupImports(c *pc.Client) ([]importMatch, error) {
+	list, err := permission_group.List(c)
+	if err != nil {
+		return nil, err
+	}
+
+	ans := make([]importMatch, 0, len(list))
+	for _, o := range list {
+		ans = append(ans, importMatch{Id: o.Id, Name: o.Name})
+	}
+	return ans, nil
+}
+
+func listNotificationTemplateImports(c *pc.Client) ([]importMatch, error) {
+	list, err := notification_template.List(c)
+	if err != nil {
+		return nil, err
+	}
+
+	ans := make([]importMatch, 0, len(list))
+	for _, o := range list {
+		ans = append(ans, importMatch{Id: o.Id, Name: o.Name})
+	}
+	return ans, nil
+}
+
+func listTrustedAlertIpImports(c *pc.Client) ([]importMatch, error) {
+	list, err := trustedalertip.List(c)
+	if err != nil {
+		return nil, err
+	}
+
+	ans := make([]importMatch, 0, len(list))
+	for _, o := range list {
+		ans = append(ans, importMatch{Id: o.UUID, Name: o.Name})
+	}
+	return ans, nil
+}
+
+func listTrustedLoginIpImports(c *pc.Client) ([]importMatch, error) {
+	list, err := ip_address.List(c)
+	if err != nil {
+		return nil, err
+	}
+
+	ans := make([]importMatch, 0, len(list))
+	for _, o := range list {
+		ans = append(ans, importMatch{Id: o.Id, Name: o.Name})
+	}
+	return ans, nil
+}
+
+func listDataPatternImports(c *pc.Client) ([]importMatch, error) {
+	list, err := datapattern.List(c)
+	if err != nil {
+		return nil, err
+	}
+
+	ans := make([]importMatch, 0, len(list))
+	for _, o := range list {
+		ans = append(ans, importMatch{Id: o.Id, Name: o.Name})
+	}
+	return ans, nil
+}
+
+func listDataProfileImports(c *pc.Client) ([]importMatch, error) {
+	list, err := dataprofile.List(c)
+	if err != nil {
+		return nil, err
+	}
+
+	ans := make([]importMatch, 0, len(list))
+	for _, o := range list {
+		ans = append(ans, importMatch{Id: o.Id, Name: o.Name})
+	}
+	return ans, nil
+}
+
+func listIntegrationImports(c *pc.Client) ([]importMatch, error) {
+	list, err := integration.List(c, "", false)
+	if err != nil {
+		return nil, err
+	}
+
+	ans := make([]importMatch, 0, len(list))
+	for _, o := range list {
+		ans = append(ans, importMatch{Id: o.Id, Name: o.Name})
+	}
+	return ans, nil
+}
+
+func listSavedSearchImports(c *pc.Client) ([]importMatch, error) {
+	list, err := history.List(c, history.Saved, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	ans := make([]importMatch, 0, len(list))
+	for _, o := range list {
+		ans = append(ans, importMatch{Id: o.Model.Id, Name: o.Model.Name})
+	}
+	return ans, nil
+}
+
+// Listers for importCloudAccountByName.
+
+func listCloudAccountImports(c *pc.Client) ([]importMatch, error) {
+	list, err := account.Names(c)
+	if err != nil {
+		return nil, err
+	}
+
+	ans := make([]importMatch, 0, len(list))
+	for _, o := range list {
+		ans = append(ans, importMatch{CloudType: o.CloudType, Id: o.AccountId, Name: o.Name})
+	}
+	return ans, nil
+}
+
+func listOrgCloudAccountImports(c *pc.Client) ([]importMatch, error) {
+	list, err := org.Names(c)
+	if err != nil {
+		return nil, err
+	}
+
+	ans := make([]importMatch, 0, len(list))
+	for _, o := range list {
+		ans = append(ans, importMatch{CloudType: o.CloudType, Id: o.AccountId, Name: o.Name})
+	}
+	return ans, nil
+}
+
+func listV2CloudAccountImports(c *pc.Client) ([]importMatch, error) {
+	list, err := accountv2.Names(c)
+	if err != nil {
+		return nil, err
+	}
+
+	ans := make([]importMatch, 0, len(list))
+	for _, o := range list {
+		ans = append(ans, importMatch{CloudType: o.CloudType, Id: o.AccountId, Name: o.Name})
+	}
+	return ans, nil
+}
+
+func listOrgV2CloudAccountImports(c *pc.Client) ([]importMatch, error) {
+	list, err := orgv2.Names(c)
+	if err != nil {
+		return nil, err
+	}
+
+	ans := make([]importMatch, 0, len(list))
+	for _, o := range list {
+		ans = append(ans, importMatch{CloudType: o.CloudType, Id: o.AccountId, Name: o.Name})
+	}
+	return ans, nil
+}
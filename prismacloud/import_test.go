@@ -0,0 +1,140 @@
+package prismacloud
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	pc "github.com/paloaltonetworks/prisma-cloud-go"
+	"golang.org/x/net/context"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func testImport(t *testing.T, r *schema.Resource, meta interface{}, id string) (string, error) {
+	t.Helper()
+
+	d := r.Data(nil)
+	d.SetId(id)
+	ans, err := r.Importer.StateContext(context.Background(), d, meta)
+	if err != nil {
+		return "", err
+	}
+	if len(ans) != 1 {
+		t.Fatalf("Import of %q returned %d objects", id, len(ans))
+	}
+
+	return ans[0].Id(), nil
+}
+
+func TestImportByName(t *testing.T) {
+	s, client := testFakeClient(t, nil)
+
+	id := s.Policies.Put(map[string]interface{}{"name": "unique"})
+	s.Policies.Put(map[string]interface{}{"name": "twice"})
+	s.Policies.Put(map[string]interface{}{"name": "twice"})
+	r := resourcePolicy()
+
+	if got, err := testImport(t, r, client, "name:unique"); err != nil {
+		t.Errorf("Error importing by name: %s", err)
+	} else if got != id {
+		t.Errorf("Imported %q, expected %q", got, id)
+	}
+	for _, c := range s.Calls() {
+		if c.Path == "/v2/policy" && c.Query != "policy.name=unique" {
+			t.Errorf("Policies were listed with query %q", c.Query)
+		}
+	}
+
+	if got, err := testImport(t, r, client, id); err != nil || got != id {
+		t.Errorf("Import by ID returned %q, %v", got, err)
+	}
+
+	if _, err := testImport(t, r, client, "name:twice"); err == nil {
+		t.Errorf("Expected an error importing an ambiguous name")
+	} else if !strings.Contains(err.Error(), "more than one policy") {
+		t.Errorf("Unexpected error for an ambiguous name: %s", err)
+	}
+
+	if _, err := testImport(t, r, client, "name:missing"); err == nil {
+		t.Errorf("Expected an error importing a missing name")
+	}
+}
+
+func TestImportAccountGroupByName(t *testing.T) {
+	s, client := testFakeClient(t, nil)
+
+	id := s.AccountGroups.Put(map[string]interface{}{"name": "prod accounts"})
+	if got, err := testImport(t, resourceAccountGroup(), client, "name:prod accounts"); err != nil {
+		t.Errorf("Error importing by name: %s", err)
+	} else if got != id {
+		t.Errorf("Imported %q, expected %q", got, id)
+	}
+}
+
+func TestImportCloudAccountByName(t *testing.T) {
+	listing := []importMatch{
+		{CloudType: "aws", Id: "111111111111", Name: "prod"},
+		{CloudType: "aws", Id: "222222222222", Name: "dev"},
+		{CloudType: "aws", Id: "333333333333", Name: "dev"},
+		{CloudType: "azure", Id: "00000000-0000-0000-0000-000000000001", Name: "prod"},
+	}
+	r := &schema.Resource{
+		Importer: importCloudAccountByName(func(c *pc.Client) ([]importMatch, error) {
+			return listing, nil
+		}),
+	}
+
+	for _, tc := range []struct {
+		id   string
+		want string
+		ok   bool
+	}{
+		{"aws:111111111111", "aws:111111111111", true},
+		{"aws:prod", "aws:111111111111", true},
+		{"aws:name:prod", "aws:111111111111", true},
+		{"azure:prod", "azure:00000000-0000-0000-0000-000000000001", true},
+		{"aws:dev", "", false},
+		{"aws:name:missing", "", false},
+		{"aws:444444444444", "aws:444444444444", true},
+		{"prod", "", false},
+	} {
+		got, err := testImport(t, r, (*pc.Client)(nil), tc.id)
+		if (err == nil) != tc.ok || got != tc.want {
+			t.Errorf("Import of %q returned %q, %v; expected %q", tc.id, got, err, tc.want)
+		}
+	}
+}
+
+func TestImportCloudAccountListingError(t *testing.T) {
+	r := &schema.Resource{
+		Importer: importCloudAccountByName(func(c *pc.Client) ([]importMatch, error) {
+			return nil, fmt.Errorf("throttled")
+		}),
+	}
+
+	if got, err := testImport(t, r, (*pc.Client)(nil), "aws:111111111111"); err != nil || got != "aws:111111111111" {
+		t.Errorf("Import by ID returned %q, %v", got, err)
+	}
+	if _, err := testImport(t, r, (*pc.Client)(nil), "aws:name:prod"); err == nil {
+		t.Errorf("Expected an error importing by name without a listing")
+	}
+}
+
+func TestImportRqlSearchId(t *testing.T) {
+	r := resourceRqlSearch()
+	query := "config from cloud.resource where api.name = 'aws-ec2-describe-instances'"
+
+	d := r.Data(nil)
+	d.SetId(buildRqlSearchId("config", query, "search-id"))
+	ans, err := r.Importer.StateContext(context.Background(), d, (*pc.Client)(nil))
+	if err != nil {
+		t.Fatalf("Error importing: %s", err)
+	}
+	if got := ans[0].Get("query").(string); got != query {
+		t.Errorf("Imported query %q, expected %q", got, query)
+	}
+	if got := ans[0].Get("search_type").(string); got != "config" {
+		t.Errorf("Imported search type %q, expected config", got)
+	}
+}
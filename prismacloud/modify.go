@@ -0,0 +1,92 @@
+package prismacloud
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	pc "github.com/paloaltonetworks/prisma-cloud-go"
+	"golang.org/x/net/context"
+)
+
+// modifyMaxRetries is the number of times a read-modify-write cycle is
+// retried after a conflict.
+const modifyMaxRetries = 5
+
+// errConcurrentModification is returned when a change is not live after it
+// was written, because another writer replaced the object at the same time.
+var errConcurrentModification = errors.New("object was modified concurrently")
+
+// objectLocks serializes the read-modify-write cycles on each object made
+// by this provider, such as several memberships of the same account group.
+var objectLocks = keyedMutex{locks: make(map[string]*sync.Mutex)}
+
+type keyedMutex struct {
+	mu    sync.Mutex
+	locks map[string]*sync.Mutex
+}
+
+func (k *keyedMutex) Lock(key string) {
+	k.mu.Lock()
+	l, ok := k.locks[key]
+	if !ok {
+		l = &sync.Mutex{}
+		k.locks[key] = l
+	}
+	k.mu.Unlock()
+
+	l.Lock()
+}
+
+func (k *keyedMutex) Unlock(key string) {
+	k.mu.Lock()
+	l := k.locks[key]
+	k.mu.Unlock()
+
+	l.Unlock()
+}
+
+/*
+readModifyWrite changes part of an object that is also managed elsewhere,
+such as the account IDs of an account group.
+
+The modify func gets the live object, changes it and writes it back, then
+gets it again to check that the change is live, returning an error wrapping
+errConcurrentModification if not.  The whole cycle is retried with backoff on
+such conflicts, on HTTP 409 errors and when throttled.  Cycles on the same
+key are serialized within the provider.
+*/
+func readModifyWrite(ctx context.Context, timeout time.Duration, key string, modify func() error) diag.Diagnostics {
+	objectLocks.Lock(key)
+	defer objectLocks.Unlock(key)
+
+	return pollApi(ctx, pollConfig{
+		timeout:    timeout,
+		maxRetries: modifyMaxRetries,
+		retryable:  isModifyConflictError,
+	}, modify)
+}
+
+// isModifyConflictError returns true if a read-modify-write cycle should be
+// retried after the error.
+func isModifyConflictError(err error) bool {
+	if errors.Is(err, errConcurrentModification) {
+		return true
+	}
+
+	var pcel pc.PrismaCloudErrorList
+	if errors.As(err, &pcel) && pcel.StatusCode == http.StatusConflict {
+		return true
+	}
+
+	return isThrottledError(err)
+}
+
+// concurrentModification returns an error wrapping errConcurrentModification
+// for the given object.
+func concurrentModification(desc, id string) error {
+	return fmt.Errorf("%s %q: %w", desc, id, errConcurrentModification)
+}
@@ -2,63 +2,161 @@ package prismacloud
 
 import (
 	"errors"
-	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
-	pc "github.com/paloaltonetworks/prisma-cloud-go"
+	"fmt"
 	"log"
 	"math/rand"
+	"net/http"
+	"strconv"
 	"strings"
 	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	pc "github.com/paloaltonetworks/prisma-cloud-go"
+	"golang.org/x/net/context"
 )
 
 type Poller func() error
 
-// PollApiUntilSuccess is a function to wait until an API call that should
-// succeed actually does.
-func PollApiUntilSuccess(p Poller) {
-	for {
-		if err := p(); err == nil {
-			break
-		}
-		time.Sleep(1 * time.Second)
-	}
+// Delay bounds for the jittered exponential backoff between polls.
+var (
+	pollMinDelay = 1 * time.Second
+	pollMaxDelay = 30 * time.Second
+)
+
+// pollConfig controls how an API call is retried.
+type pollConfig struct {
+	// timeout bounds the total time spent polling.  Zero means only the
+	// context bounds it.
+	timeout time.Duration
+
+	// maxRetries is the number of retries allowed.  Zero means unlimited.
+	maxRetries int
+
+	// retryable reports if the call should be retried after the error.
+	retryable func(error) bool
+}
+
+/*
+PollApiUntilSuccess is a function to wait until an API call that should
+succeed actually does.
+
+Retryable errors (object not found, rate limiting and server errors) are
+retried with a jittered exponential backoff until the context is done or the
+timeout elapses, normally the resource's d.Timeout() for the operation.  Any
+other error is returned immediately.
+*/
+func PollApiUntilSuccess(ctx context.Context, timeout time.Duration, p Poller) diag.Diagnostics {
+	return pollApi(ctx, pollConfig{timeout: timeout, retryable: isRetryableError}, p)
 }
 
-func PollApiUntilSuccessCustom(p Poller) diag.Diagnostics {
+// PollApiWhileThrottled is like PollApiUntilSuccess, but only retries the API
+// call when it fails due to rate limiting or a server error.  Use this for
+// calls where a missing object should not be waited on.
+func PollApiWhileThrottled(ctx context.Context, timeout time.Duration, p Poller) diag.Diagnostics {
+	return pollApi(ctx, pollConfig{timeout: timeout, retryable: isThrottledError}, p)
+}
+
+func pollApi(ctx context.Context, conf pollConfig, p Poller) diag.Diagnostics {
+	if conf.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, conf.timeout)
+		defer cancel()
+	}
+
+	var retries int
 	for {
-		if err := p(); err == nil {
-			break
-		} else if "429" == strings.Split(err.Error(), " ")[0] {
-			waitingTime := rand.Intn(5) + 1
-			time.Sleep(time.Duration(waitingTime) * time.Second)
-		} else {
+		err := p()
+		if err == nil {
+			return nil
+		} else if !conf.retryable(err) {
+			log.Printf("[DEBUG] Encountered error: %v", err)
 			return diag.FromErr(err)
+		} else if conf.maxRetries > 0 && retries >= conf.maxRetries {
+			log.Printf("[DEBUG] Exhausted all the retries, still encountered error: %v", err)
+			return pollDiagnostics(fmt.Sprintf("Gave up after %d retries", retries), err)
+		}
+
+		delay := backoffDelay(retries)
+		log.Printf("[DEBUG] Re-trying API call in %s after error: %v", delay, err)
+		retries++
+
+		t := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			if ctx.Err() == context.DeadlineExceeded {
+				return pollDiagnostics("Timed out waiting for the API call to succeed", err)
+			}
+			return pollDiagnostics("Stopped waiting for the API call to succeed", err)
+		case <-t.C:
 		}
 	}
-	return nil
 }
 
-func (b *BackOffRetry) PollApiByBackoffUntilSuccess(p Poller) diag.Diagnostics {
-	var delay int
-	var retries = 0
-	for {
-		delay = 1 << retries
-		if err := p(); err == nil {
-			break
-		} else if retries <= b.maxRetries && errors.Is(err, pc.ObjectNotFoundError) {
-			log.Printf("Re-trying API call with exponential backoff, delay of %d seconds", delay)
-			time.Sleep(time.Duration(delay) * time.Second)
-			retries++
-		} else if retries > b.maxRetries {
-			log.Printf("Exhasted all the retries, still encountered error: %v", err)
-			return diag.FromErr(err)
-		} else {
-			log.Printf("Encountered error: %v", err)
-			return diag.FromErr(err)
+func pollDiagnostics(summary string, err error) diag.Diagnostics {
+	return diag.Diagnostics{{
+		Severity: diag.Error,
+		Summary:  summary,
+		Detail:   fmt.Sprintf("Last error: %s", err),
+	}}
+}
+
+// backoffDelay returns the delay before the given retry, doubling from
+// pollMinDelay up to pollMaxDelay with the upper half of it jittered.
+func backoffDelay(retries int) time.Duration {
+	delay := pollMaxDelay
+	if retries < 32 {
+		if d := pollMinDelay << uint(retries); d > 0 && d < pollMaxDelay {
+			delay = d
 		}
 	}
-	return nil
+
+	half := int64(delay / 2)
+	if half <= 0 {
+		return delay
+	}
+	return time.Duration(half + rand.Int63n(half+1))
 }
 
-type BackOffRetry struct {
-	maxRetries int
+// isRetryableError returns true if the error is either transient or may
+// resolve itself once the API becomes eventually consistent.
+func isRetryableError(err error) bool {
+	switch {
+	case errors.Is(err, pc.ObjectNotFoundError),
+		errors.Is(err, pc.AccountGroupNotFoundError),
+		errors.Is(err, pc.ResourceListNotFoundError),
+		errors.Is(err, pc.CollectionNotFoundError):
+		return true
+	}
+
+	return isThrottledError(err)
+}
+
+// isThrottledError returns true if the error was caused by rate limiting or
+// a server side error.
+func isThrottledError(err error) bool {
+	if errors.Is(err, pc.InternalError) {
+		return true
+	}
+
+	var pcel pc.PrismaCloudErrorList
+	if errors.As(err, &pcel) {
+		return retryableStatus(pcel.StatusCode)
+	}
+
+	// Errors returned without the X-Redlock-Status header, or when the
+	// client's own rate limit retries are exhausted.
+	msg := err.Error()
+	if strings.HasPrefix(msg, "max_retries or retry_max_delay insufficient") {
+		return true
+	}
+	if code, cerr := strconv.Atoi(strings.SplitN(msg, " ", 2)[0]); cerr == nil {
+		return retryableStatus(code)
+	}
+
+	return false
+}
+
+func retryableStatus(code int) bool {
+	return code == http.StatusTooManyRequests || code >= http.StatusInternalServerError
 }
@@ -0,0 +1,124 @@
+package prismacloud
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	pc "github.com/paloaltonetworks/prisma-cloud-go"
+	"golang.org/x/net/context"
+)
+
+func shortPollDelays(t *testing.T) {
+	minDelay, maxDelay := pollMinDelay, pollMaxDelay
+	pollMinDelay, pollMaxDelay = time.Millisecond, 5*time.Millisecond
+	t.Cleanup(func() {
+		pollMinDelay, pollMaxDelay = minDelay, maxDelay
+	})
+}
+
+func TestPollApiUntilSuccessRetriesNotFound(t *testing.T) {
+	shortPollDelays(t)
+
+	var calls int
+	diags := PollApiUntilSuccess(context.Background(), time.Minute, func() error {
+		calls++
+		if calls < 3 {
+			return pc.ObjectNotFoundError
+		}
+		return nil
+	})
+	if diags.HasError() {
+		t.Fatalf("Unexpected error: %v", diags)
+	}
+	if calls != 3 {
+		t.Fatalf("Poller called %d times, expected 3", calls)
+	}
+}
+
+func TestPollApiUntilSuccessFatalError(t *testing.T) {
+	shortPollDelays(t)
+
+	var calls int
+	diags := PollApiUntilSuccess(context.Background(), time.Minute, func() error {
+		calls++
+		return pc.InvalidCredentialsError
+	})
+	if !diags.HasError() {
+		t.Fatalf("Expected an error")
+	}
+	if calls != 1 {
+		t.Fatalf("Poller called %d times, expected 1", calls)
+	}
+}
+
+func TestPollApiUntilSuccessTimeout(t *testing.T) {
+	shortPollDelays(t)
+
+	diags := PollApiUntilSuccess(context.Background(), 20*time.Millisecond, func() error {
+		return pc.PrismaCloudErrorList{StatusCode: 503}
+	})
+	if !diags.HasError() {
+		t.Fatalf("Expected an error")
+	}
+	if !strings.Contains(diags[0].Summary, "Timed out") {
+		t.Fatalf("Summary is %q, expected a timeout", diags[0].Summary)
+	}
+	if !strings.Contains(diags[0].Detail, "503") {
+		t.Fatalf("Detail %q does not carry the last error", diags[0].Detail)
+	}
+}
+
+func TestPollApiUntilSuccessCanceled(t *testing.T) {
+	shortPollDelays(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var calls int
+	diags := PollApiUntilSuccess(ctx, 0, func() error {
+		calls++
+		cancel()
+		return pc.ObjectNotFoundError
+	})
+	if !diags.HasError() {
+		t.Fatalf("Expected an error")
+	}
+	if calls != 1 {
+		t.Fatalf("Poller called %d times, expected 1", calls)
+	}
+}
+
+func TestPollApiWhileThrottled(t *testing.T) {
+	shortPollDelays(t)
+
+	var calls int
+	diags := PollApiWhileThrottled(context.Background(), time.Minute, func() error {
+		calls++
+		if calls == 1 {
+			return errors.New("429 error without the \"X-Redlock-Status\" header - returned HTML:\n")
+		}
+		return pc.ObjectNotFoundError
+	})
+	if !diags.HasError() {
+		t.Fatalf("Expected an error")
+	}
+	if calls != 2 {
+		t.Fatalf("Poller called %d times, expected 2", calls)
+	}
+}
+
+func TestPollApiMaxRetries(t *testing.T) {
+	shortPollDelays(t)
+
+	var calls int
+	diags := pollApi(context.Background(), pollConfig{maxRetries: 2, retryable: isRetryableError}, func() error {
+		calls++
+		return pc.ObjectNotFoundError
+	})
+	if !diags.HasError() {
+		t.Fatalf("Expected an error")
+	}
+	if calls != 3 {
+		t.Fatalf("Poller called %d times, expected 3", calls)
+	}
+}
@@ -2,8 +2,14 @@ package prismacloud
 
 import (
 	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
 
 	pc "github.com/paloaltonetworks/prisma-cloud-go"
+	"golang.org/x/net/context"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
@@ -11,7 +17,7 @@ import (
 
 // Provider returns a *schema.Provider.
 func Provider() *schema.Provider {
-	return &schema.Provider{
+	p := &schema.Provider{
 		Schema: map[string]*schema.Schema{
 			"url": {
 				Type:        schema.TypeString,
@@ -32,6 +38,12 @@ func Provider() *schema.Provider {
 				DefaultFunc: schema.EnvDefaultFunc("PRISMACLOUD_PASSWORD", nil),
 				Sensitive:   true,
 			},
+			"credential_process": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Command that prints the access key and secret key as JSON",
+				DefaultFunc: schema.EnvDefaultFunc("PRISMACLOUD_CREDENTIAL_PROCESS", nil),
+			},
 			"customer_name": {
 				Type:        schema.TypeString,
 				Optional:    true,
@@ -66,10 +78,10 @@ func Provider() *schema.Provider {
 			"logging": {
 				Type: schema.TypeMap,
 				Elem: &schema.Schema{
-					Type: schema.TypeBool,
+					Type: schema.TypeString,
 				},
 				Optional:    true,
-				Description: "Logging options for the API connection",
+				Description: "Logging options for the API connection, and trace_file to write every API request and response to, as HAR if it ends in .har or JSON lines otherwise",
 			},
 			"disable_reconnect": {
 				Type:        schema.TypeBool,
@@ -89,6 +101,12 @@ func Provider() *schema.Provider {
 				Description: "Retrieve the provider configuration from this JSON file",
 				DefaultFunc: schema.EnvDefaultFunc("PRISMACLOUD_JSON_CONFIG_FILE", nil),
 			},
+			"profile": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The profile to use from the profiles in json_config_file",
+				DefaultFunc: schema.EnvDefaultFunc("PRISMACLOUD_PROFILE", nil),
+			},
 			"max_retries": {
 				Type:        schema.TypeInt,
 				Optional:    true,
@@ -108,11 +126,40 @@ func Provider() *schema.Provider {
 				Default:      "exponential_backoff",
 				ValidateFunc: validation.StringInSlice([]string{"exponential_backoff", "linear_backoff"}, false),
 			},
+			"max_requests_per_second": {
+				Type:         schema.TypeFloat,
+				Optional:     true,
+				Description:  "Maximum number of API requests sent per second, 0 for no limit",
+				DefaultFunc:  schema.EnvDefaultFunc("PRISMACLOUD_MAX_REQUESTS_PER_SECOND", 0),
+				ValidateFunc: validation.FloatAtLeast(0),
+			},
+			"burst": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Description:  "Maximum number of API requests that may be sent at once above max_requests_per_second",
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+			"max_concurrent_requests": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Description:  "Maximum number of API requests in flight at the same time, 0 for no limit",
+				DefaultFunc:  schema.EnvDefaultFunc("PRISMACLOUD_MAX_CONCURRENT_REQUESTS", 0),
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+			"cache_ttl": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Description:  "Number of seconds to cache collection listings for, 0 to disable caching",
+				DefaultFunc:  schema.EnvDefaultFunc("PRISMACLOUD_CACHE_TTL", 0),
+				ValidateFunc: validation.IntAtLeast(0),
+			},
 		},
 
 		DataSourcesMap: map[string]*schema.Resource{
 			"prismacloud_account_group":                            dataSourceAccountGroup(),
+			"prismacloud_account_group_tree":                       dataSourceAccountGroupTree(),
 			"prismacloud_account_groups":                           dataSourceAccountGroups(),
+			"prismacloud_alert":                                    dataSourceAlert(),
 			"prismacloud_alert_rule":                               dataSourceAlertRule(),
 			"prismacloud_alert_rules":                              dataSourceAlertRules(),
 			"prismacloud_alerts":                                   dataSourceAlerts(),
@@ -148,6 +195,7 @@ func Provider() *schema.Provider {
 			"prismacloud_policies":                                 dataSourcePolicies(),
 			"prismacloud_policy":                                   dataSourcePolicy(),
 			"prismacloud_report":                                   dataSourceReport(),
+			"prismacloud_report_download":                          dataSourceReportDownload(),
 			"prismacloud_reports":                                  dataSourceReports(),
 			"prismacloud_resource_list":                            dataSourceResourceList(),
 			"prismacloud_resource_lists":                           dataSourceResourceLists(),
@@ -171,13 +219,19 @@ func Provider() *schema.Provider {
 
 		ResourcesMap: map[string]*schema.Resource{
 			"prismacloud_account_group":                           resourceAccountGroup(),
+			"prismacloud_account_group_membership":                resourceAccountGroupMembership(),
+			"prismacloud_alert_dismissal":                         resourceAlertDismissal(),
 			"prismacloud_alert_rule":                              resourceAlertRule(),
+			"prismacloud_alert_rule_policy":                       resourceAlertRulePolicy(),
 			"prismacloud_anomaly_settings":                        resourceAnomalySettings(),
 			"prismacloud_anomaly_trusted_list":                    resourceAnomalyTrustedList(),
 			"prismacloud_cloud_account":                           resourceCloudAccount(),
 			"prismacloud_cloud_account_v2":                        resourceV2CloudAccount(),
 			"prismacloud_collection":                              resourceCollection(),
+			"prismacloud_compliance_policy_mapping":               resourceCompliancePolicyMapping(),
 			"prismacloud_compliance_standard":                     resourceComplianceStandard(),
+			"prismacloud_compliance_standard_bundle":              resourceComplianceStandardBundle(),
+			"prismacloud_compliance_standard_clone":               resourceComplianceStandardClone(),
 			"prismacloud_compliance_standard_requirement":         resourceComplianceStandardRequirement(),
 			"prismacloud_compliance_standard_requirement_section": resourceComplianceStandardRequirementSection(),
 			"prismacloud_datapattern":                             resourceDataPattern(),
@@ -186,6 +240,8 @@ func Provider() *schema.Provider {
 			"prismacloud_integration":                             resourceIntegration(),
 			"prismacloud_permission_group":                        resourcePermissionGroup(),
 			"prismacloud_policy":                                  resourcePolicy(),
+			"prismacloud_policy_status":                           resourcePolicyStatus(),
+			"prismacloud_policy_statuses":                         resourcePolicyStatuses(),
 			"prismacloud_report":                                  resourceReport(),
 			"prismacloud_resource_list":                           resourceResourceList(),
 			"prismacloud_rql_search":                              resourceRqlSearch(),
@@ -202,6 +258,15 @@ func Provider() *schema.Provider {
 
 		ConfigureFunc: providerConfigure,
 	}
+
+	for _, r := range p.DataSourcesMap {
+		withApiDiagnostics(r)
+	}
+	for _, r := range p.ResourcesMap {
+		withApiDiagnostics(r)
+	}
+
+	return p
 }
 
 func providerConfigure(d *schema.ResourceData) (interface{}, error) {
@@ -225,11 +290,56 @@ func providerConfigure(d *schema.ResourceData) (interface{}, error) {
 	}
 
 	logSetting := make(map[string]bool)
+	traceFile := os.Getenv("PRISMACLOUD_TRACE_FILE")
 	logConfig := d.Get("logging").(map[string]interface{})
 	for key := range logConfig {
-		logSetting[key] = logConfig[key].(bool)
+		if key == logTraceFile {
+			traceFile = logConfig[key].(string)
+			continue
+		}
+		v, err := strconv.ParseBool(logConfig[key].(string))
+		if err != nil {
+			return nil, fmt.Errorf("Logging option %q should be true or false, not %q", key, logConfig[key])
+		}
+		logSetting[key] = v
+	}
+
+	var base http.RoundTripper = newBaseTransport(d.Get("skip_ssl_cert_verification").(bool))
+	if traceFile != "" {
+		out, err := newTraceWriter(traceFile)
+		if err != nil {
+			return nil, fmt.Errorf("Unable to open trace_file: %s", err)
+		}
+		base = &tracingTransport{next: base, out: out}
+	}
+
+	throttle := &throttledTransport{
+		next:          base,
+		limiter:       newRateLimiter(d.Get("max_requests_per_second").(float64), d.Get("burst").(int)),
+		timeout:       time.Duration(d.Get("timeout").(int)) * time.Second,
+		maxRetries:    d.Get("max_retries").(int),
+		retryMaxDelay: time.Duration(d.Get("retry_max_delay").(int)) * time.Second,
+		retryType:     d.Get("retry_type").(string),
+	}
+	if n := d.Get("max_concurrent_requests").(int); n > 0 {
+		throttle.slots = make(chan struct{}, n)
+	}
+
+	var rt http.RoundTripper = throttle
+	var creds *credentialProcess
+	if cmd := d.Get("credential_process").(string); cmd != "" {
+		creds = &credentialProcess{command: cmd}
+		rt = &credentialTransport{next: rt, process: creds}
+	}
+	if !d.Get("disable_reconnect").(bool) {
+		rt = &tokenTransport{next: rt}
+	}
+	if ttl := d.Get("cache_ttl").(int); ttl > 0 {
+		rt = newCachingTransport(rt, time.Duration(ttl)*time.Second)
 	}
 
+	// Rate limited requests are retried by the throttled transport with a
+	// per request retry budget, so the client's own retries are disabled.
 	con := &pc.Client{
 		Url:                     d.Get("url").(string),
 		Username:                d.Get("username").(string),
@@ -242,11 +352,39 @@ func providerConfigure(d *schema.ResourceData) (interface{}, error) {
 		DisableReconnect:        d.Get("disable_reconnect").(bool),
 		JsonWebToken:            d.Get("json_web_token").(string),
 		Logging:                 logSetting,
-		MaxRetries:              d.Get("max_retries").(int),
+		MaxRetries:              0,
 		RetryMaxDelay:           d.Get("retry_max_delay").(int),
 		RetryType:               d.Get("retry_type").(string),
+		Transport:               newClientTransport(rt),
+	}
+
+	// The credential process takes precedence over the username and
+	// password, including the ones in json_config_file, which only fills in
+	// what is still unset.
+	if creds != nil {
+		ans, err := creds.Get(context.Background())
+		if err != nil {
+			return nil, err
+		}
+		if con.Username != "" || con.Password != "" {
+			log.Printf("[WARN] Both credential_process and username or password are set, using credential_process")
+		}
+		con.Username = ans.Username
+		con.Password = ans.Password
+	}
+
+	// Config files with profiles are resolved here, as the client only
+	// understands a flat config file.
+	filename := d.Get("json_config_file").(string)
+	profile, err := loadConfigProfile(filename, d.Get("profile").(string))
+	if err != nil {
+		return nil, err
+	}
+	if profile != nil {
+		profile.apply(con)
+		filename = ""
 	}
 
-	err := con.Initialize(d.Get("json_config_file").(string))
+	err = con.Initialize(filename)
 	return con, err
 }
@@ -14,6 +14,7 @@ import (
 	"github.com/paloaltonetworks/prisma-cloud-go/settings/enterprise"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/terraform-providers/terraform-provider-prismacloud/internal/fakeapi"
 )
 
 const (
@@ -361,3 +362,76 @@ func testAccPreCheck(t *testing.T) {
 		t.Fatalf("%s must be set for acceptance tests", PrismacloudJsonConfigFileEnvVar)
 	}
 }
+
+// testFakeClient starts a fake Prisma Cloud API and returns a client that is
+// configured against it through the provider schema.  The optional config
+// overrides or extends the provider params used.
+func testFakeClient(t *testing.T, config map[string]interface{}) (*fakeapi.Server, *pc.Client) {
+	t.Helper()
+
+	s := fakeapi.New()
+	s.Username = "fake-access-key"
+	s.Password = "fake-secret-key"
+	t.Cleanup(s.Close)
+
+	raw := map[string]interface{}{
+		"url":      s.Host(),
+		"port":     s.Port(),
+		"protocol": "http",
+		"username": s.Username,
+		"password": s.Password,
+		"logging":  map[string]interface{}{pc.LogQuiet: true},
+	}
+	for key, val := range config {
+		raw[key] = val
+	}
+
+	meta, err := providerConfigure(schema.TestResourceDataRaw(t, Provider().Schema, raw))
+	if err != nil {
+		t.Fatalf("Error configuring provider: %s", err)
+	}
+
+	return s, meta.(*pc.Client)
+}
+
+func TestProviderConfigureFakeApi(t *testing.T) {
+	s, client := testFakeClient(t, nil)
+
+	if client.JsonWebToken == "" {
+		t.Fatalf("JSON web token was not set")
+	}
+	if n := s.Count("POST", "/login"); n != 1 {
+		t.Fatalf("Expected 1 login, got %d", n)
+	}
+}
+
+func TestProviderConfigureFakeApiBadCredentials(t *testing.T) {
+	s := fakeapi.New()
+	s.Username = "fake-access-key"
+	s.Password = "fake-secret-key"
+	defer s.Close()
+
+	raw := map[string]interface{}{
+		"url":      s.Host(),
+		"port":     s.Port(),
+		"protocol": "http",
+		"username": s.Username,
+		"password": "wrong",
+		"logging":  map[string]interface{}{pc.LogQuiet: true},
+	}
+	if _, err := providerConfigure(schema.TestResourceDataRaw(t, Provider().Schema, raw)); err != pc.InvalidCredentialsError {
+		t.Fatalf("Expected invalid credentials error, got %v", err)
+	}
+}
+
+func TestProviderConfigureInvalidLogging(t *testing.T) {
+	raw := map[string]interface{}{
+		"url":      "127.0.0.1",
+		"username": "fake-access-key",
+		"password": "fake-secret-key",
+		"logging":  map[string]interface{}{pc.LogAction: "sometimes"},
+	}
+	if _, err := providerConfigure(schema.TestResourceDataRaw(t, Provider().Schema, raw)); err == nil || !strings.Contains(err.Error(), pc.LogAction) {
+		t.Fatalf("Expected an error for the logging option, got %v", err)
+	}
+}
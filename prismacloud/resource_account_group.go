@@ -18,9 +18,7 @@ func resourceAccountGroup() *schema.Resource {
 		UpdateContext: updateAccountGroup,
 		DeleteContext: deleteAccountGroup,
 
-		Importer: &schema.ResourceImporter{
-			StateContext: schema.ImportStatePassthroughContext,
-		},
+		Importer: importByName("account group", listAccountGroupImports),
 
 		Schema: map[string]*schema.Schema{
 			"name": {
@@ -177,20 +175,24 @@ func createAccountGroup(ctx context.Context, d *schema.ResourceData, meta interf
 		return diag.FromErr(err)
 	}
 
-	PollApiUntilSuccess(func() error {
+	if diags := PollApiUntilSuccess(ctx, d.Timeout(schema.TimeoutCreate), func() error {
 		_, err := group.Identify(client, obj.Name)
 		return err
-	})
+	}); diags.HasError() {
+		return diags
+	}
 
 	id, err := group.Identify(client, obj.Name)
 	if err != nil {
 		return diag.FromErr(err)
 	}
 
-	PollApiUntilSuccess(func() error {
+	if diags := PollApiUntilSuccess(ctx, d.Timeout(schema.TimeoutCreate), func() error {
 		_, err := group.Get(client, id)
 		return err
-	})
+	}); diags.HasError() {
+		return diags
+	}
 
 	d.SetId(id)
 	return readAccountGroup(ctx, d, meta)
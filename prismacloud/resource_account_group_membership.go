@@ -0,0 +1,221 @@
+package prismacloud
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	pc "github.com/paloaltonetworks/prisma-cloud-go"
+	"github.com/paloaltonetworks/prisma-cloud-go/cloud/account/group"
+	"golang.org/x/net/context"
+)
+
+func resourceAccountGroupMembership() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: createAccountGroupMembership,
+		ReadContext:   readAccountGroupMembership,
+		UpdateContext: updateAccountGroupMembership,
+		DeleteContext: deleteAccountGroupMembership,
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(5 * time.Minute),
+			Update: schema.DefaultTimeout(5 * time.Minute),
+			Delete: schema.DefaultTimeout(5 * time.Minute),
+		},
+
+		Importer: &schema.ResourceImporter{
+			StateContext: importAccountGroupMembership,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"group_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "Account group ID",
+			},
+			"account_ids": {
+				Type:        schema.TypeSet,
+				Required:    true,
+				MinItems:    1,
+				Description: "Cloud account IDs to add to the group",
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+		},
+	}
+}
+
+/*
+modifyAccountGroupMembership adds and removes cloud account IDs of an account
+group, leaving its other account IDs alone.
+
+Removing account IDs from a group that no longer exists succeeds.
+*/
+func modifyAccountGroupMembership(ctx context.Context, client *pc.Client, timeout time.Duration, id string, add, remove []string) diag.Diagnostics {
+	return readModifyWrite(ctx, timeout, "account group "+id, func() error {
+		obj, err := group.Get(client, id)
+		if err != nil {
+			if err == pc.AccountGroupNotFoundError && len(add) == 0 {
+				return nil
+			}
+			return err
+		}
+		if membershipApplied(obj.AccountIds, add, remove) {
+			return nil
+		}
+
+		obj.AccountIds = idsAfter(obj.AccountIds, add, remove)
+		if err = group.Update(client, obj); err != nil {
+			return err
+		}
+
+		if obj, err = group.Get(client, id); err != nil {
+			return err
+		}
+		if !membershipApplied(obj.AccountIds, add, remove) {
+			return concurrentModification("account group", id)
+		}
+
+		return nil
+	})
+}
+
+// membershipApplied returns true if ids has all of add and none of remove.
+func membershipApplied(ids, add, remove []string) bool {
+	current := make(map[string]bool, len(ids))
+	for _, v := range ids {
+		current[v] = true
+	}
+
+	for _, v := range add {
+		if !current[v] {
+			return false
+		}
+	}
+	for _, v := range remove {
+		if current[v] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// idsAfter returns the given IDs with add added and remove removed, in a
+// stable order.
+func idsAfter(ids, add, remove []string) []string {
+	removed := make(map[string]bool, len(remove))
+	for _, v := range remove {
+		removed[v] = true
+	}
+
+	seen := make(map[string]bool, len(ids)+len(add))
+	ans := make([]string, 0, len(ids)+len(add))
+	for _, list := range [][]string{ids, add} {
+		for _, v := range list {
+			if !removed[v] && !seen[v] {
+				seen[v] = true
+				ans = append(ans, v)
+			}
+		}
+	}
+
+	return ans
+}
+
+func createAccountGroupMembership(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*pc.Client)
+	id := d.Get("group_id").(string)
+	add := SetToStringSlice(d.Get("account_ids").(*schema.Set))
+
+	if diags := modifyAccountGroupMembership(ctx, client, d.Timeout(schema.TimeoutCreate), id, add, nil); diags.HasError() {
+		return diags
+	}
+
+	d.SetId(resource.UniqueId())
+	return readAccountGroupMembership(ctx, d, meta)
+}
+
+func readAccountGroupMembership(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*pc.Client)
+	id := d.Get("group_id").(string)
+
+	obj, err := group.Get(client, id)
+	if err != nil {
+		if err == pc.AccountGroupNotFoundError {
+			log.Printf("[WARN] Account group %q of membership %q is gone", id, d.Id())
+			d.SetId("")
+			return nil
+		}
+		return diag.FromErr(err)
+	}
+
+	live := make(map[string]bool, len(obj.AccountIds))
+	for _, v := range obj.AccountIds {
+		live[v] = true
+	}
+
+	ids := make([]string, 0)
+	for _, v := range SetToStringSlice(d.Get("account_ids").(*schema.Set)) {
+		if live[v] {
+			ids = append(ids, v)
+		}
+	}
+	if err = d.Set("account_ids", ids); err != nil {
+		log.Printf("[WARN] Error setting 'account_ids' field for %q: %s", d.Id(), err)
+	}
+
+	return nil
+}
+
+func updateAccountGroupMembership(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*pc.Client)
+	id := d.Get("group_id").(string)
+
+	o, n := d.GetChange("account_ids")
+	add := SetToStringSlice(n.(*schema.Set).Difference(o.(*schema.Set)))
+	remove := SetToStringSlice(o.(*schema.Set).Difference(n.(*schema.Set)))
+
+	if diags := modifyAccountGroupMembership(ctx, client, d.Timeout(schema.TimeoutUpdate), id, add, remove); diags.HasError() {
+		return diags
+	}
+
+	return readAccountGroupMembership(ctx, d, meta)
+}
+
+func deleteAccountGroupMembership(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*pc.Client)
+	id := d.Get("group_id").(string)
+	remove := SetToStringSlice(d.Get("account_ids").(*schema.Set))
+
+	if diags := modifyAccountGroupMembership(ctx, client, d.Timeout(schema.TimeoutDelete), id, nil, remove); diags.HasError() {
+		return diags
+	}
+
+	d.SetId("")
+	return nil
+}
+
+// importAccountGroupMembership imports "<group_id>:<account_id>,...".
+func importAccountGroupMembership(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	tok := strings.SplitN(d.Id(), IdSeparator, 2)
+	if len(tok) != 2 || tok[0] == "" || tok[1] == "" {
+		return nil, fmt.Errorf("expected an ID of the form <group_id>%s<account_id>,<account_id>, got %q", IdSeparator, d.Id())
+	}
+
+	ids := strings.Split(tok[1], ",")
+	sort.Strings(ids)
+
+	d.Set("group_id", tok[0])
+	d.Set("account_ids", ids)
+	d.SetId(resource.UniqueId())
+
+	return []*schema.ResourceData{d}, nil
+}
@@ -0,0 +1,131 @@
+package prismacloud
+
+import (
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/terraform-providers/terraform-provider-prismacloud/internal/fakeapi"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/paloaltonetworks/prisma-cloud-go/cloud/account/group"
+	"golang.org/x/net/context"
+)
+
+func testGroupAccountIds(t *testing.T, s *fakeapi.Server, id string) string {
+	t.Helper()
+
+	var obj group.Group
+	if !s.AccountGroups.Load(id, &obj) {
+		t.Fatalf("Account group %q does not exist", id)
+	}
+	sort.Strings(obj.AccountIds)
+	return strings.Join(obj.AccountIds, ",")
+}
+
+func TestAccountGroupMembershipFakeApi(t *testing.T) {
+	s, client := testFakeClient(t, nil)
+	ctx := context.Background()
+	r := resourceAccountGroupMembership()
+
+	id := s.AccountGroups.Put(group.Group{Name: "shared", AccountIds: []string{"owner"}})
+
+	m1 := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{
+		"group_id":    id,
+		"account_ids": []interface{}{"a", "b"},
+	})
+	m2 := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{
+		"group_id":    id,
+		"account_ids": []interface{}{"c"},
+	})
+	for _, d := range []*schema.ResourceData{m1, m2} {
+		if diags := r.CreateContext(ctx, d, client); diags.HasError() {
+			t.Fatalf("Error in create: %v", diags)
+		}
+	}
+	if m1.Id() == m2.Id() {
+		t.Errorf("Memberships have the same ID %q", m1.Id())
+	}
+	if got := testGroupAccountIds(t, s, id); got != "a,b,c,owner" {
+		t.Fatalf("Group account IDs are %s after create", got)
+	}
+
+	// Removed outside of Terraform.
+	var obj group.Group
+	s.AccountGroups.Load(id, &obj)
+	obj.AccountIds = []string{"owner", "a", "c"}
+	s.AccountGroups.Put(obj)
+
+	if diags := r.ReadContext(ctx, m1, client); diags.HasError() {
+		t.Fatalf("Error in read: %v", diags)
+	}
+	if ids := m1.Get("account_ids").(*schema.Set); ids.Len() != 1 || !ids.Contains("a") {
+		t.Errorf("Account IDs are %v after read, expected only a", ids.List())
+	}
+
+	if diags := r.DeleteContext(ctx, m1, client); diags.HasError() {
+		t.Fatalf("Error in delete: %v", diags)
+	}
+	if got := testGroupAccountIds(t, s, id); got != "c,owner" {
+		t.Errorf("Group account IDs are %s after delete", got)
+	}
+
+	s.AccountGroups.Delete(id)
+	if diags := r.ReadContext(ctx, m2, client); diags.HasError() {
+		t.Fatalf("Error in read: %v", diags)
+	}
+	if m2.Id() != "" {
+		t.Errorf("ID was not cleared for a deleted group")
+	}
+}
+
+func TestAccountGroupMembershipConflictFakeApi(t *testing.T) {
+	shortPollDelays(t)
+	s, client := testFakeClient(t, nil)
+
+	id := s.AccountGroups.Put(group.Group{Name: "shared", AccountIds: []string{"owner"}})
+
+	// Another writer replaces the group right after the first update.
+	update := s.AccountGroups.UpdateHandler()
+	var puts int
+	s.Handle("PUT", "/cloud/group/{id}", func(r *fakeapi.Request) (interface{}, error) {
+		puts++
+		ans, err := update(r)
+		if puts == 1 {
+			s.AccountGroups.Put(group.Group{Id: id, Name: "shared", AccountIds: []string{"owner", "other"}})
+		}
+		return ans, err
+	})
+
+	if diags := modifyAccountGroupMembership(context.Background(), client, 0, id, []string{"a"}, nil); diags.HasError() {
+		t.Fatalf("Error adding account: %v", diags)
+	}
+	if puts != 2 {
+		t.Errorf("Group was updated %d times, expected 2", puts)
+	}
+	if got := testGroupAccountIds(t, s, id); got != "a,other,owner" {
+		t.Errorf("Group account IDs are %s", got)
+	}
+}
+
+func TestImportAccountGroupMembership(t *testing.T) {
+	r := resourceAccountGroupMembership()
+
+	d := r.Data(nil)
+	d.SetId("group-1:b,a")
+	ans, err := importAccountGroupMembership(context.Background(), d, nil)
+	if err != nil {
+		t.Fatalf("Error importing: %s", err)
+	}
+	if got := ans[0].Get("group_id").(string); got != "group-1" {
+		t.Errorf("group_id is %q", got)
+	}
+	if n := ans[0].Get("account_ids").(*schema.Set).Len(); n != 2 {
+		t.Errorf("Got %d account IDs, expected 2", n)
+	}
+
+	d.SetId("group-1")
+	if _, err = importAccountGroupMembership(context.Background(), d, nil); err == nil {
+		t.Errorf("Expected an error for an ID without account IDs")
+	}
+}
@@ -9,7 +9,9 @@ import (
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"golang.org/x/net/context"
 )
 
 func TestAccAccountGroup(t *testing.T) {
@@ -105,3 +107,31 @@ resource "prismacloud_account_group" "test" {
 }
 `, name, desc)
 }
+
+func TestAccountGroupFakeApi(t *testing.T) {
+	s, client := testFakeClient(t, nil)
+	ctx := context.Background()
+	r := resourceAccountGroup()
+
+	d := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{
+		"name":        "tf group",
+		"description": "first",
+		"account_ids": []interface{}{"123456789012"},
+	})
+
+	if diags := r.CreateContext(ctx, d, client); diags.HasError() {
+		t.Fatalf("Error in create: %v", diags)
+	}
+	id := d.Id()
+	if v := d.Get("group_id").(string); v != id {
+		t.Fatalf("group_id is %q, expected %q", v, id)
+	}
+
+	s.AccountGroups.Delete(id)
+	if diags := r.ReadContext(ctx, d, client); diags.HasError() {
+		t.Fatalf("Error in read: %v", diags)
+	}
+	if d.Id() != "" {
+		t.Fatalf("ID was not cleared for deleted group")
+	}
+}
@@ -0,0 +1,360 @@
+package prismacloud
+
+import (
+	"log"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	pc "github.com/paloaltonetworks/prisma-cloud-go"
+	"github.com/paloaltonetworks/prisma-cloud-go/alert"
+	"github.com/paloaltonetworks/prisma-cloud-go/timerange"
+	"golang.org/x/net/context"
+)
+
+// Alert statuses.
+const (
+	alertStatusOpen      = "open"
+	alertStatusDismissed = "dismissed"
+	alertStatusSnoozed   = "snoozed"
+)
+
+func resourceAlertDismissal() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: createAlertDismissal,
+		ReadContext:   readAlertDismissal,
+		UpdateContext: updateAlertDismissal,
+		DeleteContext: deleteAlertDismissal,
+
+		CustomizeDiff: customizeAlertDismissalDiff,
+
+		Schema: map[string]*schema.Schema{
+			// Input.
+			"reason": {
+				Type:         schema.TypeString,
+				Required:     true,
+				Description:  "Dismissal note",
+				ValidateFunc: validation.StringIsNotWhiteSpace,
+			},
+			"snooze": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Description: "Snooze the alerts for this long instead of dismissing them",
+				MaxItems:    1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"amount": {
+							Type:         schema.TypeInt,
+							Required:     true,
+							Description:  "The time number",
+							ValidateFunc: validation.IntAtLeast(1),
+						},
+						"unit": {
+							Type:        schema.TypeString,
+							Required:    true,
+							Description: "The time unit",
+							ValidateFunc: validation.StringInSlice(
+								[]string{
+									timerange.Hour,
+									timerange.Day,
+									timerange.Week,
+									timerange.Month,
+									timerange.Year,
+								},
+								false,
+							),
+						},
+					},
+				},
+			},
+			"time_range": timeRangeSchema("resource_alert_dismissal"),
+			"filters": {
+				Type:        schema.TypeList,
+				Required:    true,
+				ForceNew:    true,
+				MinItems:    1,
+				Description: "Filtering parameters that select the alerts",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Type:        schema.TypeString,
+							Required:    true,
+							ForceNew:    true,
+							Description: "Param name to filter on",
+						},
+						"operator": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							ForceNew:    true,
+							Description: "Operator between the name and value params",
+							Default:     "=",
+						},
+						"value": {
+							Type:        schema.TypeString,
+							Required:    true,
+							ForceNew:    true,
+							Description: "Param value for the filter",
+						},
+					},
+				},
+			},
+
+			// Attributes.
+			"alert_ids": {
+				Type:        schema.TypeSet,
+				Computed:    true,
+				Description: "IDs of the alerts dismissed or snoozed by this resource",
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"open_alert_ids": {
+				Type:        schema.TypeSet,
+				Computed:    true,
+				Description: "IDs of the alerts that match the filters but are open, such as alerts that were reopened",
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+		},
+	}
+}
+
+// alertDismissalRequest is the body of the dismiss and reopen calls.
+type alertDismissalRequest struct {
+	Alerts             []string             `json:"alerts,omitempty"`
+	DismissalNote      string               `json:"dismissalNote,omitempty"`
+	DismissalTimeRange *timerange.TimeRange `json:"dismissalTimeRange,omitempty"`
+	Filter             alertDismissalFilter `json:"filter"`
+}
+
+type alertDismissalFilter struct {
+	TimeRange timerange.TimeRange `json:"timeRange"`
+	Filters   []alert.Filter      `json:"filters"`
+}
+
+// dismissAlerts dismisses the given alerts, or snoozes them if a dismissal
+// time range is given.
+func dismissAlerts(c pc.PrismaCloudClient, req alertDismissalRequest) error {
+	c.Log(pc.LogAction, "(dismiss) %d alerts", len(req.Alerts))
+
+	_, err := c.Communicate("POST", []string{"alert", "dismiss"}, nil, req, nil)
+	return err
+}
+
+// reopenAlerts reopens the given dismissed or snoozed alerts.
+func reopenAlerts(c pc.PrismaCloudClient, req alertDismissalRequest) error {
+	c.Log(pc.LogAction, "(reopen) %d alerts", len(req.Alerts))
+
+	_, err := c.Communicate("POST", []string{"alert", "reopen"}, nil, req, nil)
+	return err
+}
+
+// listAllAlerts returns the alerts from every page of the given request.
+func listAllAlerts(c pc.PrismaCloudClient, req alert.Request) ([]alert.Alert, error) {
+	var ans []alert.Alert
+
+	for {
+		resp, err := alert.List(c, req)
+		if err != nil {
+			return nil, err
+		}
+		ans = append(ans, resp.Data...)
+		if resp.PageToken == "" || len(resp.Data) == 0 {
+			return ans, nil
+		}
+		req.PageToken = resp.PageToken
+	}
+}
+
+/*
+parseAlertDismissal returns the request for the alerts selected by the
+resource, along with the value of its alert.status filter.
+
+The alert.status filter is left out of the request filters, so that the
+alerts dismissed by the resource are listed too.  The time range defaults to
+all time.
+*/
+func parseAlertDismissal(d *schema.ResourceData) (alertDismissalRequest, string) {
+	ans := alertDismissalRequest{
+		DismissalNote: d.Get("reason").(string),
+		Filter: alertDismissalFilter{
+			TimeRange: ParseTimeRange(ResourceDataInterfaceMap(d, "time_range")),
+		},
+	}
+	if ans.Filter.TimeRange.Value == nil {
+		ans.Filter.TimeRange = timerange.TimeRange{
+			Type:  timerange.TypeToNow,
+			Value: timerange.Epoch,
+		}
+	}
+
+	if snooze := ResourceDataInterfaceMap(d, "snooze"); len(snooze) != 0 {
+		ans.DismissalTimeRange = &timerange.TimeRange{
+			Type: timerange.TypeRelative,
+			Value: timerange.Relative{
+				Amount: snooze["amount"].(int),
+				Unit:   snooze["unit"].(string),
+			},
+		}
+	}
+
+	var status string
+	filters := d.Get("filters").([]interface{})
+	ans.Filter.Filters = make([]alert.Filter, 0, len(filters))
+	for i := range filters {
+		f := filters[i].(map[string]interface{})
+		if f["name"].(string) == "alert.status" {
+			status = f["value"].(string)
+			continue
+		}
+		ans.Filter.Filters = append(ans.Filter.Filters, alert.Filter{
+			Name:     f["name"].(string),
+			Operator: f["operator"].(string),
+			Value:    f["value"].(string),
+		})
+	}
+
+	return ans, status
+}
+
+// listAlertDismissal returns the IDs of the alerts selected by the resource
+// that are dismissed or snoozed by it, and of those that are open.
+func listAlertDismissal(client *pc.Client, d *schema.ResourceData) ([]string, []string, error) {
+	req, status := parseAlertDismissal(d)
+
+	list, err := listAllAlerts(client, alert.Request{
+		TimeRange: req.Filter.TimeRange,
+		Limit:     1000,
+		Filters:   req.Filter.Filters,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	owned := make(map[string]bool)
+	for _, id := range SetToStringSlice(d.Get("alert_ids").(*schema.Set)) {
+		owned[id] = true
+	}
+
+	dismissed := make([]string, 0, len(owned))
+	open := make([]string, 0)
+	for _, a := range list {
+		switch a.Status {
+		case alertStatusDismissed, alertStatusSnoozed:
+			if owned[a.Id] {
+				dismissed = append(dismissed, a.Id)
+			}
+		case alertStatusOpen:
+			if owned[a.Id] || status == "" || status == alertStatusOpen {
+				open = append(open, a.Id)
+			}
+		}
+	}
+
+	return dismissed, open, nil
+}
+
+func customizeAlertDismissalDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if d.Id() == "" {
+		return nil
+	}
+
+	// Open alerts are dismissed in an update.
+	if d.Get("open_alert_ids").(*schema.Set).Len() > 0 {
+		if err := d.SetNewComputed("alert_ids"); err != nil {
+			return err
+		}
+		return d.SetNewComputed("open_alert_ids")
+	}
+
+	return nil
+}
+
+// applyAlertDismissal dismisses the open alerts selected by the resource,
+// along with the ones it has already dismissed if redismiss is true.
+func applyAlertDismissal(client *pc.Client, d *schema.ResourceData, redismiss bool) error {
+	dismissed, open, err := listAlertDismissal(client, d)
+	if err != nil {
+		return err
+	}
+
+	req, _ := parseAlertDismissal(d)
+	req.Alerts = open
+	if redismiss {
+		req.Alerts = append(req.Alerts, dismissed...)
+	}
+
+	if len(req.Alerts) > 0 {
+		sort.Strings(req.Alerts)
+		if err = dismissAlerts(client, req); err != nil {
+			return err
+		}
+	}
+
+	d.Set("alert_ids", append(dismissed, open...))
+	return nil
+}
+
+func createAlertDismissal(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*pc.Client)
+
+	if err := applyAlertDismissal(client, d, false); err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(resource.UniqueId())
+	return readAlertDismissal(ctx, d, meta)
+}
+
+func readAlertDismissal(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*pc.Client)
+
+	dismissed, open, err := listAlertDismissal(client, d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if len(open) > 0 {
+		log.Printf("[DEBUG] Alert dismissal %q has %d open alerts", d.Id(), len(open))
+	}
+	d.Set("alert_ids", dismissed)
+	d.Set("open_alert_ids", open)
+
+	return nil
+}
+
+func updateAlertDismissal(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*pc.Client)
+
+	if err := applyAlertDismissal(client, d, d.HasChanges("reason", "snooze")); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return readAlertDismissal(ctx, d, meta)
+}
+
+func deleteAlertDismissal(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*pc.Client)
+
+	dismissed, _, err := listAlertDismissal(client, d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if len(dismissed) > 0 {
+		req, _ := parseAlertDismissal(d)
+		req.DismissalNote = ""
+		req.DismissalTimeRange = nil
+		req.Alerts = dismissed
+		sort.Strings(req.Alerts)
+		if err = reopenAlerts(client, req); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	d.SetId("")
+	return nil
+}
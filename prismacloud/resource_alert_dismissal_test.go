@@ -0,0 +1,125 @@
+package prismacloud
+
+import (
+	"testing"
+
+	"github.com/terraform-providers/terraform-provider-prismacloud/internal/fakeapi"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"golang.org/x/net/context"
+)
+
+func testAlertStatus(t *testing.T, s *fakeapi.Server, id string) string {
+	t.Helper()
+
+	a, ok := s.Alerts.Get(id)
+	if !ok {
+		t.Fatalf("Alert %q does not exist", id)
+	}
+	status, _ := a["status"].(string)
+	return status
+}
+
+func testAlertDismissalConfig(policyId string, snooze bool) map[string]interface{} {
+	raw := map[string]interface{}{
+		"reason": "Accepted risk",
+		"filters": []interface{}{
+			map[string]interface{}{"name": "policy.id", "value": policyId},
+			map[string]interface{}{"name": "alert.status", "value": "open"},
+		},
+	}
+	if snooze {
+		raw["snooze"] = []interface{}{map[string]interface{}{"amount": 7, "unit": "day"}}
+	}
+	return raw
+}
+
+func TestAlertDismissalFakeApi(t *testing.T) {
+	s, client := testFakeClient(t, nil)
+	ctx := context.Background()
+	r := resourceAlertDismissal()
+
+	a := s.Alerts.Put(map[string]interface{}{"status": "open", "policy": map[string]interface{}{"policyId": "policy-1"}})
+	b := s.Alerts.Put(map[string]interface{}{"status": "open", "policy": map[string]interface{}{"policyId": "policy-1"}})
+	other := s.Alerts.Put(map[string]interface{}{"status": "open", "policy": map[string]interface{}{"policyId": "policy-2"}})
+
+	raw := testAlertDismissalConfig("policy-1", false)
+	d := schema.TestResourceDataRaw(t, r.Schema, raw)
+	if diags := r.CreateContext(ctx, d, client); diags.HasError() {
+		t.Fatalf("Error in create: %v", diags)
+	}
+	if n := d.Get("alert_ids").(*schema.Set).Len(); n != 2 {
+		t.Fatalf("Dismissed %d alerts, expected 2", n)
+	}
+	for _, id := range []string{a, b} {
+		if status := testAlertStatus(t, s, id); status != "dismissed" {
+			t.Errorf("Alert %s is %s, expected dismissed", id, status)
+		}
+	}
+	if status := testAlertStatus(t, s, other); status != "open" {
+		t.Errorf("Alert of another policy is %s", status)
+	}
+
+	// Reopen an alert by hand.
+	obj, _ := s.Alerts.Get(b)
+	obj["status"] = "open"
+	s.Alerts.Put(obj)
+
+	if diags := r.ReadContext(ctx, d, client); diags.HasError() {
+		t.Fatalf("Error in read: %v", diags)
+	}
+	if n := d.Get("alert_ids").(*schema.Set).Len(); n != 1 {
+		t.Errorf("Got %d dismissed alerts after reopen, expected 1", n)
+	}
+	if ids := d.Get("open_alert_ids").(*schema.Set); ids.Len() != 1 || !ids.Contains(b) {
+		t.Errorf("Open alerts are %v, expected %s", ids.List(), b)
+	}
+
+	diff, err := r.Diff(ctx, d.State(), terraform.NewResourceConfigRaw(raw), client)
+	if err != nil {
+		t.Fatalf("Error computing diff: %s", err)
+	}
+	if diff == nil || diff.Attributes["alert_ids.#"] == nil || !diff.Attributes["alert_ids.#"].NewComputed {
+		t.Errorf("Reopened alert did not cause a diff: %#v", diff)
+	}
+
+	if diags := r.UpdateContext(ctx, d, client); diags.HasError() {
+		t.Fatalf("Error in update: %v", diags)
+	}
+	if status := testAlertStatus(t, s, b); status != "dismissed" {
+		t.Errorf("Reopened alert is %s after update, expected dismissed", status)
+	}
+	if n := d.Get("open_alert_ids").(*schema.Set).Len(); n != 0 {
+		t.Errorf("Got %d open alerts after update", n)
+	}
+
+	if diags := r.DeleteContext(ctx, d, client); diags.HasError() {
+		t.Fatalf("Error in delete: %v", diags)
+	}
+	for _, id := range []string{a, b} {
+		if status := testAlertStatus(t, s, id); status != "open" {
+			t.Errorf("Alert %s is %s after delete, expected open", id, status)
+		}
+	}
+}
+
+func TestAlertDismissalSnoozeFakeApi(t *testing.T) {
+	s, client := testFakeClient(t, nil)
+	r := resourceAlertDismissal()
+
+	id := s.Alerts.Put(map[string]interface{}{"status": "open", "policy": map[string]interface{}{"policyId": "policy-1"}})
+	dismissed := s.Alerts.Put(map[string]interface{}{"status": "dismissed", "policy": map[string]interface{}{"policyId": "policy-1"}})
+
+	d := schema.TestResourceDataRaw(t, r.Schema, testAlertDismissalConfig("policy-1", true))
+	if diags := r.CreateContext(context.Background(), d, client); diags.HasError() {
+		t.Fatalf("Error in create: %v", diags)
+	}
+
+	if status := testAlertStatus(t, s, id); status != "snoozed" {
+		t.Errorf("Alert is %s, expected snoozed", status)
+	}
+	if ids := d.Get("alert_ids").(*schema.Set); ids.Len() != 1 || ids.Contains(dismissed) {
+		t.Errorf("Alert IDs are %v, expected only %s", ids.List(), id)
+	}
+}
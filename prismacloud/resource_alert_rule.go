@@ -19,9 +19,7 @@ func resourceAlertRule() *schema.Resource {
 		UpdateContext: updateAlertRule,
 		DeleteContext: deleteAlertRule,
 
-		Importer: &schema.ResourceImporter{
-			StateContext: schema.ImportStatePassthroughContext,
-		},
+		Importer: importByName("alert rule", listAlertRuleImports),
 
 		Schema: map[string]*schema.Schema{
 			"policy_scan_config_id": {
@@ -670,20 +668,24 @@ func createAlertRule(ctx context.Context, d *schema.ResourceData, meta interface
 		return diag.FromErr(err)
 	}
 
-	PollApiUntilSuccess(func() error {
+	if diags := PollApiUntilSuccess(ctx, d.Timeout(schema.TimeoutCreate), func() error {
 		_, err := rule.Identify(client, o.Name)
 		return err
-	})
+	}); diags.HasError() {
+		return diags
+	}
 
 	id, err := rule.Identify(client, o.Name)
 	if err != nil {
 		return diag.FromErr(err)
 	}
 
-	PollApiUntilSuccess(func() error {
+	if diags := PollApiUntilSuccess(ctx, d.Timeout(schema.TimeoutCreate), func() error {
 		_, err := rule.Get(client, id)
 		return err
-	})
+	}); diags.HasError() {
+		return diags
+	}
 
 	d.SetId(id)
 	return readAlertRule(ctx, d, meta)
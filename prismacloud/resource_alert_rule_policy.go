@@ -0,0 +1,166 @@
+package prismacloud
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	pc "github.com/paloaltonetworks/prisma-cloud-go"
+	"github.com/paloaltonetworks/prisma-cloud-go/alert/rule"
+	"golang.org/x/net/context"
+)
+
+func resourceAlertRulePolicy() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: createAlertRulePolicy,
+		ReadContext:   readAlertRulePolicy,
+		DeleteContext: deleteAlertRulePolicy,
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(5 * time.Minute),
+			Delete: schema.DefaultTimeout(5 * time.Minute),
+		},
+
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"alert_rule_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "Alert rule ID",
+			},
+			"policy_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "Policy ID to add to the alert rule",
+			},
+			"scan_all": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "If the alert rule scans all policies, in which case the policy is not added",
+			},
+		},
+	}
+}
+
+// alertRuleScansAll returns the warning for an alert rule policy that is left
+// alone because the alert rule scans all policies.
+func alertRuleScansAll(id, policyId string) diag.Diagnostics {
+	return diag.Diagnostics{{
+		Severity: diag.Warning,
+		Summary:  "Alert rule scans all policies",
+		Detail:   fmt.Sprintf("Alert rule %q has scan_all set, so policy %q is not added to or removed from its policies.", id, policyId),
+	}}
+}
+
+/*
+modifyAlertRulePolicy adds a policy ID to the policies of an alert rule, or
+removes it, leaving its other policies alone.
+
+Alert rules that scan all policies are not changed, and true is returned for
+them.  Removing a policy from an alert rule that no longer exists succeeds.
+*/
+func modifyAlertRulePolicy(ctx context.Context, client *pc.Client, timeout time.Duration, id, policyId string, attach bool) (bool, diag.Diagnostics) {
+	var scanAll bool
+	var add, remove []string
+	if attach {
+		add = []string{policyId}
+	} else {
+		remove = []string{policyId}
+	}
+
+	diags := readModifyWrite(ctx, timeout, "alert rule "+id, func() error {
+		obj, err := rule.Get(client, id)
+		if err != nil {
+			if err == pc.ObjectNotFoundError && !attach {
+				return nil
+			}
+			return err
+		}
+		if scanAll = obj.ScanAll; scanAll || membershipApplied(obj.Policies, add, remove) {
+			return nil
+		}
+
+		obj.Policies = idsAfter(obj.Policies, add, remove)
+		if err = rule.Update(client, obj); err != nil {
+			return err
+		}
+
+		if obj, err = rule.Get(client, id); err != nil {
+			return err
+		}
+		if !membershipApplied(obj.Policies, add, remove) {
+			return concurrentModification("alert rule", id)
+		}
+
+		return nil
+	})
+
+	return scanAll, diags
+}
+
+func createAlertRulePolicy(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*pc.Client)
+	id := d.Get("alert_rule_id").(string)
+	policyId := d.Get("policy_id").(string)
+
+	scanAll, diags := modifyAlertRulePolicy(ctx, client, d.Timeout(schema.TimeoutCreate), id, policyId, true)
+	if diags.HasError() {
+		return diags
+	}
+	if scanAll {
+		diags = alertRuleScansAll(id, policyId)
+	}
+
+	d.SetId(TwoStringsToId(id, policyId))
+	return append(diags, readAlertRulePolicy(ctx, d, meta)...)
+}
+
+func readAlertRulePolicy(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*pc.Client)
+	id, policyId := IdToTwoStrings(d.Id())
+
+	obj, err := rule.Get(client, id)
+	if err != nil {
+		if err == pc.ObjectNotFoundError {
+			log.Printf("[WARN] Alert rule of %q is gone", d.Id())
+			d.SetId("")
+			return nil
+		}
+		return diag.FromErr(err)
+	}
+
+	if !obj.ScanAll && !membershipApplied(obj.Policies, []string{policyId}, nil) {
+		log.Printf("[WARN] Policy %q was removed from alert rule %q", policyId, id)
+		d.SetId("")
+		return nil
+	}
+
+	d.Set("alert_rule_id", id)
+	d.Set("policy_id", policyId)
+	d.Set("scan_all", obj.ScanAll)
+
+	return nil
+}
+
+func deleteAlertRulePolicy(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*pc.Client)
+	id, policyId := IdToTwoStrings(d.Id())
+
+	scanAll, diags := modifyAlertRulePolicy(ctx, client, d.Timeout(schema.TimeoutDelete), id, policyId, false)
+	if diags.HasError() {
+		return diags
+	}
+	if scanAll {
+		diags = alertRuleScansAll(id, policyId)
+	}
+
+	d.SetId("")
+	return diags
+}
@@ -0,0 +1,101 @@
+package prismacloud
+
+import (
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/paloaltonetworks/prisma-cloud-go/alert/rule"
+	"golang.org/x/net/context"
+)
+
+func testAlertRulePolicies(t *testing.T, r *rule.Rule) string {
+	t.Helper()
+
+	sort.Strings(r.Policies)
+	return strings.Join(r.Policies, ",")
+}
+
+func TestAlertRulePolicyFakeApi(t *testing.T) {
+	s, client := testFakeClient(t, nil)
+	ctx := context.Background()
+	r := resourceAlertRulePolicy()
+
+	id := s.AlertRules.Put(rule.Rule{Name: "central", Policies: []string{"owner"}})
+
+	d := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{
+		"alert_rule_id": id,
+		"policy_id":     "custom",
+	})
+	if diags := r.CreateContext(ctx, d, client); diags.HasError() {
+		t.Fatalf("Error in create: %v", diags)
+	}
+	if d.Id() != TwoStringsToId(id, "custom") {
+		t.Errorf("ID is %q", d.Id())
+	}
+
+	var obj rule.Rule
+	s.AlertRules.Load(id, &obj)
+	if got := testAlertRulePolicies(t, &obj); got != "custom,owner" {
+		t.Fatalf("Alert rule policies are %s after create", got)
+	}
+
+	if diags := r.DeleteContext(ctx, d, client); diags.HasError() {
+		t.Fatalf("Error in delete: %v", diags)
+	}
+	s.AlertRules.Load(id, &obj)
+	if got := testAlertRulePolicies(t, &obj); got != "owner" {
+		t.Errorf("Alert rule policies are %s after delete", got)
+	}
+
+	// Removed outside of Terraform.
+	d = schema.TestResourceDataRaw(t, r.Schema, nil)
+	d.SetId(TwoStringsToId(id, "custom"))
+	if diags := r.ReadContext(ctx, d, client); diags.HasError() {
+		t.Fatalf("Error in read: %v", diags)
+	}
+	if d.Id() != "" {
+		t.Errorf("ID was not cleared for a removed policy")
+	}
+}
+
+func TestAlertRulePolicyScanAllFakeApi(t *testing.T) {
+	s, client := testFakeClient(t, nil)
+	ctx := context.Background()
+	r := resourceAlertRulePolicy()
+
+	id := s.AlertRules.Put(rule.Rule{Name: "everything", ScanAll: true})
+
+	d := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{
+		"alert_rule_id": id,
+		"policy_id":     "custom",
+	})
+	diags := r.CreateContext(ctx, d, client)
+	if diags.HasError() {
+		t.Fatalf("Error in create: %v", diags)
+	}
+	if len(diags) != 1 || diags[0].Severity != diag.Warning {
+		t.Errorf("Expected a warning, got %v", diags)
+	}
+	if d.Id() == "" || !d.Get("scan_all").(bool) {
+		t.Errorf("Expected the policy in state with scan_all set")
+	}
+
+	var obj rule.Rule
+	s.AlertRules.Load(id, &obj)
+	if len(obj.Policies) != 0 {
+		t.Errorf("Alert rule policies are %v, expected none", obj.Policies)
+	}
+
+	if diags = r.DeleteContext(ctx, d, client); diags.HasError() {
+		t.Fatalf("Error in delete: %v", diags)
+	}
+
+	s.AlertRules.Delete(id)
+	d.SetId(TwoStringsToId(id, "custom"))
+	if diags = r.DeleteContext(ctx, d, client); diags.HasError() {
+		t.Errorf("Error deleting from a deleted alert rule: %v", diags)
+	}
+}
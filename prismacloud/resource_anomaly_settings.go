@@ -169,10 +169,12 @@ func createAnomalySettings(ctx context.Context, d *schema.ResourceData, meta int
 
 	id := d.Get("policy_id").(string)
 
-	PollApiUntilSuccess(func() error {
+	if diags := PollApiUntilSuccess(ctx, d.Timeout(schema.TimeoutCreate), func() error {
 		_, err := anomalySettings.Get(client, id)
 		return err
-	})
+	}); diags.HasError() {
+		return diags
+	}
 
 	d.SetId(id)
 	return readAnomalySettings(ctx, d, meta)
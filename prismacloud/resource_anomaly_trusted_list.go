@@ -240,20 +240,24 @@ func createAnomalyTrustedList(ctx context.Context, d *schema.ResourceData, meta
 		return diag.FromErr(err)
 	}
 
-	PollApiUntilSuccess(func() error {
+	if diags := PollApiUntilSuccess(ctx, d.Timeout(schema.TimeoutCreate), func() error {
 		_, err := anomalyTrustedList.Identify(client, strconv.Itoa(res))
 		return err
-	})
+	}); diags.HasError() {
+		return diags
+	}
 
 	id, err := anomalyTrustedList.Identify(client, strconv.Itoa(res))
 	if err != nil {
 		return diag.FromErr(err)
 	}
 
-	PollApiUntilSuccess(func() error {
+	if diags := PollApiUntilSuccess(ctx, d.Timeout(schema.TimeoutCreate), func() error {
 		_, err := anomalyTrustedList.Get(client, id)
 		return err
-	})
+	}); diags.HasError() {
+		return diags
+	}
 
 	d.SetId(id)
 	return readAnomalyTrustedList(ctx, d, meta)
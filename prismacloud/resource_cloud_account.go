@@ -28,9 +28,7 @@ func resourceCloudAccount() *schema.Resource {
 			Delete: schema.DefaultTimeout(5 * time.Minute),
 		},
 
-		Importer: &schema.ResourceImporter{
-			StateContext: schema.ImportStatePassthroughContext,
-		},
+		Importer: importCloudAccountByName(listCloudAccountImports),
 
 		Schema: map[string]*schema.Schema{
 
@@ -496,20 +494,24 @@ func createCloudAccount(ctx context.Context, d *schema.ResourceData, meta interf
 		}
 	}
 
-	PollApiUntilSuccess(func() error {
+	if diags := PollApiUntilSuccess(ctx, d.Timeout(schema.TimeoutCreate), func() error {
 		_, err := account.Identify(client, cloudType, name)
 		return err
-	})
+	}); diags.HasError() {
+		return diags
+	}
 
 	id, err := account.Identify(client, cloudType, name)
 	if err != nil {
 		return diag.FromErr(err)
 	}
 
-	PollApiUntilSuccess(func() error {
+	if diags := PollApiUntilSuccess(ctx, d.Timeout(schema.TimeoutCreate), func() error {
 		_, err := account.Get(client, cloudType, id)
 		return err
-	})
+	}); diags.HasError() {
+		return diags
+	}
 
 	d.SetId(TwoStringsToId(cloudType, id))
 	return readCloudAccount(ctx, d, meta)
@@ -0,0 +1,188 @@
+package prismacloud
+
+import (
+	"log"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	pc "github.com/paloaltonetworks/prisma-cloud-go"
+	"github.com/paloaltonetworks/prisma-cloud-go/policy"
+	"golang.org/x/net/context"
+)
+
+func resourceCompliancePolicyMapping() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: createCompliancePolicyMapping,
+		ReadContext:   readCompliancePolicyMapping,
+		DeleteContext: deleteCompliancePolicyMapping,
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(5 * time.Minute),
+			Delete: schema.DefaultTimeout(5 * time.Minute),
+		},
+
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Schema: map[string]*schema.Schema{
+			// Input.
+			"policy_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "Policy ID",
+			},
+			"csrs_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "Compliance standard requirement section ID",
+			},
+
+			// Attributes.
+			"standard_name": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Compliance standard name",
+			},
+			"requirement_id": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Compliance requirement number",
+			},
+			"requirement_name": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Compliance requirement name",
+			},
+			"section_id": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Compliance section ID",
+			},
+			"section_description": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Compliance section description",
+			},
+		},
+	}
+}
+
+// policyComplianceMetadata returns the compliance metadata entry of a policy
+// for the given section ID.
+func policyComplianceMetadata(obj policy.Policy, csrsId string) (policy.ComplianceMetadata, bool) {
+	for _, cm := range obj.ComplianceMetadata {
+		if cm.ComplianceId == csrsId {
+			return cm, true
+		}
+	}
+
+	return policy.ComplianceMetadata{}, false
+}
+
+/*
+modifyCompliancePolicyMapping maps a policy to a compliance section, or
+unmaps it, leaving the other compliance metadata of the policy alone.
+
+Unmapping a policy that no longer exists succeeds.
+*/
+func modifyCompliancePolicyMapping(ctx context.Context, client *pc.Client, timeout time.Duration, policyId, csrsId string, mapped bool) diag.Diagnostics {
+	return readModifyWrite(ctx, timeout, "policy "+policyId, func() error {
+		obj, err := policy.Get(client, policyId)
+		if err != nil {
+			if err == pc.ObjectNotFoundError && !mapped {
+				return nil
+			}
+			return err
+		}
+		if _, ok := policyComplianceMetadata(obj, csrsId); ok == mapped {
+			return nil
+		}
+
+		if mapped {
+			obj.ComplianceMetadata = append(obj.ComplianceMetadata, policy.ComplianceMetadata{
+				ComplianceId: csrsId,
+			})
+		} else {
+			cms := make([]policy.ComplianceMetadata, 0, len(obj.ComplianceMetadata))
+			for _, cm := range obj.ComplianceMetadata {
+				if cm.ComplianceId != csrsId {
+					cms = append(cms, cm)
+				}
+			}
+			obj.ComplianceMetadata = cms
+		}
+		if err = policy.Update(client, obj); err != nil {
+			return err
+		}
+
+		if obj, err = policy.Get(client, policyId); err != nil {
+			return err
+		}
+		if _, ok := policyComplianceMetadata(obj, csrsId); ok != mapped {
+			return concurrentModification("policy", policyId)
+		}
+
+		return nil
+	})
+}
+
+func createCompliancePolicyMapping(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*pc.Client)
+	policyId := d.Get("policy_id").(string)
+	csrsId := d.Get("csrs_id").(string)
+
+	if diags := modifyCompliancePolicyMapping(ctx, client, d.Timeout(schema.TimeoutCreate), policyId, csrsId, true); diags.HasError() {
+		return diags
+	}
+
+	d.SetId(TwoStringsToId(policyId, csrsId))
+	return readCompliancePolicyMapping(ctx, d, meta)
+}
+
+func readCompliancePolicyMapping(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*pc.Client)
+	policyId, csrsId := IdToTwoStrings(d.Id())
+
+	obj, err := policy.Get(client, policyId)
+	if err != nil {
+		if err == pc.ObjectNotFoundError {
+			log.Printf("[WARN] Policy of compliance mapping %q is gone", d.Id())
+			d.SetId("")
+			return nil
+		}
+		return diag.FromErr(err)
+	}
+
+	cm, ok := policyComplianceMetadata(obj, csrsId)
+	if !ok {
+		log.Printf("[WARN] Policy %q is no longer mapped to compliance section %q", policyId, csrsId)
+		d.SetId("")
+		return nil
+	}
+
+	d.Set("policy_id", policyId)
+	d.Set("csrs_id", csrsId)
+	d.Set("standard_name", cm.StandardName)
+	d.Set("requirement_id", cm.RequirementId)
+	d.Set("requirement_name", cm.RequirementName)
+	d.Set("section_id", cm.SectionId)
+	d.Set("section_description", cm.SectionDescription)
+
+	return nil
+}
+
+func deleteCompliancePolicyMapping(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*pc.Client)
+	policyId, csrsId := IdToTwoStrings(d.Id())
+
+	if diags := modifyCompliancePolicyMapping(ctx, client, d.Timeout(schema.TimeoutDelete), policyId, csrsId, false); diags.HasError() {
+		return diags
+	}
+
+	d.SetId("")
+	return nil
+}
@@ -0,0 +1,70 @@
+package prismacloud
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/paloaltonetworks/prisma-cloud-go/policy"
+	"golang.org/x/net/context"
+)
+
+func TestCompliancePolicyMappingFakeApi(t *testing.T) {
+	s, client := testFakeClient(t, nil)
+	ctx := context.Background()
+	r := resourceCompliancePolicyMapping()
+	sr := resourceComplianceStandardRequirementSection()
+
+	csrsId := s.Sections.Put(map[string]interface{}{
+		"requirementId": "req-1",
+		"sectionId":     "1.1",
+		"standardName":  "Custom",
+	})
+	id := s.Policies.Put(policy.Policy{
+		Name:               "system",
+		SystemDefault:      true,
+		ComplianceMetadata: []policy.ComplianceMetadata{{ComplianceId: "other", StandardName: "CIS"}},
+	})
+
+	d := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{
+		"policy_id": id,
+		"csrs_id":   csrsId,
+	})
+	if diags := r.CreateContext(ctx, d, client); diags.HasError() {
+		t.Fatalf("Error in create: %v", diags)
+	}
+	if d.Id() != TwoStringsToId(id, csrsId) {
+		t.Errorf("ID is %q", d.Id())
+	}
+
+	var obj policy.Policy
+	s.Policies.Load(id, &obj)
+	if len(obj.ComplianceMetadata) != 2 || obj.ComplianceMetadata[0].StandardName != "CIS" {
+		t.Fatalf("Compliance metadata is %+v after create", obj.ComplianceMetadata)
+	}
+
+	sd := schema.TestResourceDataRaw(t, sr.Schema, map[string]interface{}{"csr_id": "req-1"})
+	sd.SetId(TwoStringsToId("req-1", csrsId))
+	if diags := sr.ReadContext(ctx, sd, client); diags.HasError() {
+		t.Fatalf("Error reading section: %v", diags)
+	}
+	if ids := sd.Get("associated_policy_ids").([]interface{}); len(ids) != 1 || ids[0] != id {
+		t.Errorf("Section associated_policy_ids is %v, expected [%s]", ids, id)
+	}
+
+	if diags := r.DeleteContext(ctx, d, client); diags.HasError() {
+		t.Fatalf("Error in delete: %v", diags)
+	}
+	s.Policies.Load(id, &obj)
+	if len(obj.ComplianceMetadata) != 1 || obj.ComplianceMetadata[0].ComplianceId != "other" {
+		t.Errorf("Compliance metadata is %+v after delete", obj.ComplianceMetadata)
+	}
+
+	// Unmapped outside of Terraform.
+	d.SetId(TwoStringsToId(id, csrsId))
+	if diags := r.ReadContext(ctx, d, client); diags.HasError() {
+		t.Fatalf("Error in read: %v", diags)
+	}
+	if d.Id() != "" {
+		t.Errorf("ID was not cleared for a removed mapping")
+	}
+}
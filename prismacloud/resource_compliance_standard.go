@@ -18,9 +18,7 @@ func resourceComplianceStandard() *schema.Resource {
 		UpdateContext: updateComplianceStandard,
 		DeleteContext: deleteComplianceStandard,
 
-		Importer: &schema.ResourceImporter{
-			StateContext: schema.ImportStatePassthroughContext,
-		},
+		Importer: importByName("compliance standard", listComplianceStandardImports),
 
 		Schema: map[string]*schema.Schema{
 			"cs_id": {
@@ -113,20 +111,24 @@ func createComplianceStandard(ctx context.Context, d *schema.ResourceData, meta
 		return diag.FromErr(err)
 	}
 
-	PollApiUntilSuccess(func() error {
+	if diags := PollApiUntilSuccess(ctx, d.Timeout(schema.TimeoutCreate), func() error {
 		_, err := standard.Identify(client, o.Name)
 		return err
-	})
+	}); diags.HasError() {
+		return diags
+	}
 
 	csId, err := standard.Identify(client, o.Name)
 	if err != nil {
 		return diag.FromErr(err)
 	}
 
-	PollApiUntilSuccess(func() error {
+	if diags := PollApiUntilSuccess(ctx, d.Timeout(schema.TimeoutCreate), func() error {
 		_, err := standard.Get(client, csId)
 		return err
-	})
+	}); diags.HasError() {
+		return diags
+	}
 
 	d.SetId(csId)
 	return readComplianceStandard(ctx, d, meta)
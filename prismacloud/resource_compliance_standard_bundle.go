@@ -0,0 +1,456 @@
+package prismacloud
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	pc "github.com/paloaltonetworks/prisma-cloud-go"
+	"github.com/paloaltonetworks/prisma-cloud-go/compliance/standard"
+	"github.com/paloaltonetworks/prisma-cloud-go/compliance/standard/requirement"
+	"github.com/paloaltonetworks/prisma-cloud-go/compliance/standard/requirement/section"
+	"github.com/terraform-providers/terraform-provider-prismacloud/internal/compliance"
+	"golang.org/x/net/context"
+)
+
+func resourceComplianceStandardBundle() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: createComplianceStandardBundle,
+		ReadContext:   readComplianceStandardBundle,
+		UpdateContext: updateComplianceStandardBundle,
+		DeleteContext: deleteComplianceStandardBundle,
+
+		CustomizeDiff: customizeComplianceStandardBundleDiff,
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(30 * time.Minute),
+			Update: schema.DefaultTimeout(30 * time.Minute),
+		},
+
+		Importer: importByName("compliance standard", listComplianceStandardImports),
+
+		Schema: map[string]*schema.Schema{
+			// Input.
+			"catalog": {
+				Type:         schema.TypeString,
+				Required:     true,
+				Description:  "OSCAL catalog or simple JSON catalog of the standard, its requirements and their sections",
+				ValidateFunc: validateComplianceCatalog,
+				StateFunc:    normalizeComplianceCatalog,
+			},
+
+			// Attributes.
+			"cs_id": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Compliance standard ID",
+			},
+			"name": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Compliance standard name",
+			},
+			"requirement_ids": {
+				Type:        schema.TypeMap,
+				Computed:    true,
+				Description: "Map of requirement_id to compliance standard requirement ID",
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"section_ids": {
+				Type:        schema.TypeMap,
+				Computed:    true,
+				Description: "Map of requirement_id:section_id to compliance standard requirement section ID",
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+		},
+	}
+}
+
+func validateComplianceCatalog(v interface{}, k string) ([]string, []error) {
+	if _, err := compliance.Parse([]byte(v.(string))); err != nil {
+		return nil, []error{fmt.Errorf("%s: %s", k, err)}
+	}
+
+	return nil, nil
+}
+
+// normalizeComplianceCatalog stores the catalog as a simple catalog, so that
+// it compares equal to the live standard.
+func normalizeComplianceCatalog(v interface{}) string {
+	c, err := compliance.Parse([]byte(v.(string)))
+	if err != nil {
+		return v.(string)
+	}
+
+	return c.Encode()
+}
+
+// complianceStandardBundleIds holds the IDs of the requirements and sections
+// of a standard, keyed by requirement ID and by requirement ID and section ID.
+type complianceStandardBundleIds struct {
+	Requirements map[string]string
+	Sections     map[string]string
+}
+
+// loadComplianceStandardBundle returns the live standard as a catalog.
+func loadComplianceStandardBundle(client *pc.Client, csId string) (compliance.Catalog, complianceStandardBundleIds, error) {
+	ids := complianceStandardBundleIds{
+		Requirements: make(map[string]string),
+		Sections:     make(map[string]string),
+	}
+
+	cs, err := standard.Get(client, csId)
+	if err != nil {
+		return compliance.Catalog{}, ids, err
+	}
+
+	reqs, err := requirement.List(client, csId)
+	if err != nil {
+		return compliance.Catalog{}, ids, err
+	}
+
+	ans := compliance.Catalog{
+		Name:         cs.Name,
+		Description:  cs.Description,
+		Requirements: make([]compliance.Requirement, 0, len(reqs)),
+	}
+	for _, r := range reqs {
+		sections, err := section.List(client, r.Id)
+		if err != nil {
+			return compliance.Catalog{}, ids, err
+		}
+
+		cr := compliance.Requirement{
+			RequirementId: r.RequirementId,
+			Name:          r.Name,
+			Description:   r.Description,
+			ViewOrder:     r.ViewOrder,
+		}
+		for _, s := range sections {
+			cr.Sections = append(cr.Sections, compliance.Section{
+				SectionId:   s.SectionId,
+				Description: s.Description,
+				Label:       s.Label,
+				ViewOrder:   s.ViewOrder,
+			})
+			ids.Sections[TwoStringsToId(r.RequirementId, s.SectionId)] = s.Id
+		}
+		ans.Requirements = append(ans.Requirements, cr)
+		ids.Requirements[r.RequirementId] = r.Id
+	}
+	ans.Normalize()
+
+	return ans, ids, nil
+}
+
+/*
+applyComplianceStandardBundle changes the live standard into the wanted one,
+one requirement or section at a time.
+
+The live IDs are updated as objects are created and deleted.  If a change
+fails, the changes before it are kept, so a later apply picks up where this
+one stopped.
+*/
+func applyComplianceStandardBundle(ctx context.Context, client *pc.Client, timeout time.Duration, csId string, live compliance.Catalog, ids complianceStandardBundleIds, want compliance.Catalog) diag.Diagnostics {
+	if live.Name != want.Name || live.Description != want.Description {
+		if err := standard.Update(client, standard.Standard{Id: csId, Name: want.Name, Description: want.Description}); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	for _, c := range compliance.Diff(live, want) {
+		log.Printf("[DEBUG] Compliance standard %q: %s requirement %q section %q", csId, c.Op, c.RequirementId, c.SectionId)
+
+		r, _ := want.Requirement(c.RequirementId)
+		csrId := ids.Requirements[c.RequirementId]
+		key := TwoStringsToId(c.RequirementId, c.SectionId)
+
+		var err error
+		switch {
+		case c.SectionId == "" && c.Op == compliance.Delete:
+			if err = requirement.Delete(client, csrId); err == nil || err == pc.ObjectNotFoundError {
+				delete(ids.Requirements, c.RequirementId)
+				err = nil
+			}
+		case c.SectionId == "":
+			o := requirement.Requirement{
+				Id:            csrId,
+				ComplianceId:  csId,
+				Name:          r.Name,
+				Description:   r.Description,
+				StandardName:  want.Name,
+				RequirementId: r.RequirementId,
+				ViewOrder:     r.ViewOrder,
+			}
+			if c.Op == compliance.Update {
+				err = requirement.Update(client, o)
+				break
+			}
+			if err = requirement.Create(client, o); err != nil {
+				break
+			}
+			if diags := PollApiUntilSuccess(ctx, timeout, func() error {
+				csrId, err = identifyComplianceRequirement(client, csId, r.RequirementId)
+				return err
+			}); diags.HasError() {
+				return diags
+			}
+			ids.Requirements[c.RequirementId] = csrId
+		case c.Op == compliance.Delete:
+			if err = section.Delete(client, ids.Sections[key]); err == nil || err == pc.ObjectNotFoundError {
+				delete(ids.Sections, key)
+				err = nil
+			}
+		default:
+			s, _ := r.Section(c.SectionId)
+			o := section.Section{
+				Id:            ids.Sections[key],
+				RequirementId: csrId,
+				SectionId:     s.SectionId,
+				Description:   s.Description,
+				Label:         s.Label,
+				ViewOrder:     s.ViewOrder,
+			}
+			if c.Op == compliance.Update {
+				err = section.Update(client, o)
+				break
+			}
+			if err = section.Create(client, o); err != nil {
+				break
+			}
+			var liveObj section.Section
+			if diags := PollApiUntilSuccess(ctx, timeout, func() error {
+				liveObj, err = section.Get(client, csrId, s.SectionId)
+				return err
+			}); diags.HasError() {
+				return diags
+			}
+			ids.Sections[key] = liveObj.Id
+		}
+
+		if err != nil {
+			return diag.Errorf("Error on %s of compliance requirement %q section %q: %s", c.Op, c.RequirementId, c.SectionId, err)
+		}
+	}
+
+	return nil
+}
+
+// identifyComplianceRequirement returns the ID of the requirement with the
+// given requirement ID.
+func identifyComplianceRequirement(client *pc.Client, csId, requirementId string) (string, error) {
+	list, err := requirement.List(client, csId)
+	if err != nil {
+		return "", err
+	}
+
+	for _, o := range list {
+		if o.RequirementId == requirementId {
+			return o.Id, nil
+		}
+	}
+
+	return "", pc.ObjectNotFoundError
+}
+
+/*
+removeComplianceStandardBundle deletes the standard along with its
+requirements and sections.
+
+Objects that are already gone are skipped, so a removal that failed part
+way through can be retried.
+*/
+func removeComplianceStandardBundle(client *pc.Client, csId string) error {
+	reqs, err := requirement.List(client, csId)
+	if err != nil {
+		if err == pc.ObjectNotFoundError {
+			return nil
+		}
+		return err
+	}
+
+	for _, r := range reqs {
+		sections, err := section.List(client, r.Id)
+		if err != nil && err != pc.ObjectNotFoundError {
+			return err
+		}
+		for _, s := range sections {
+			if err = section.Delete(client, s.Id); err != nil && err != pc.ObjectNotFoundError {
+				return err
+			}
+		}
+		if err = requirement.Delete(client, r.Id); err != nil && err != pc.ObjectNotFoundError {
+			return err
+		}
+	}
+
+	if err = standard.Delete(client, csId); err != nil && err != pc.ObjectNotFoundError {
+		return err
+	}
+
+	return nil
+}
+
+func customizeComplianceStandardBundleDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if d.Id() == "" || !d.HasChange("catalog") {
+		return nil
+	}
+
+	if err := d.SetNewComputed("requirement_ids"); err != nil {
+		return err
+	}
+	if err := d.SetNewComputed("section_ids"); err != nil {
+		return err
+	}
+	return d.SetNewComputed("name")
+}
+
+/*
+createComplianceStandardCatalog creates a standard with the requirements and
+sections of the catalog, returning its ID and the IDs of what was created.
+
+If this fails part way through, the standard is removed again.  The standard
+ID is only returned on error if that fails too.
+*/
+func createComplianceStandardCatalog(ctx context.Context, client *pc.Client, timeout time.Duration, want compliance.Catalog) (string, complianceStandardBundleIds, diag.Diagnostics) {
+	ids := complianceStandardBundleIds{
+		Requirements: make(map[string]string),
+		Sections:     make(map[string]string),
+	}
+
+	cs := standard.Standard{
+		Name:        want.Name,
+		Description: want.Description,
+	}
+	if err := standard.Create(client, cs); err != nil {
+		return "", ids, diag.FromErr(err)
+	}
+
+	var csId string
+	if diags := PollApiUntilSuccess(ctx, timeout, func() error {
+		var err error
+		csId, err = standard.Identify(client, cs.Name)
+		return err
+	}); diags.HasError() {
+		// Make one last attempt so that the standard is not orphaned.
+		var err error
+		if csId, err = standard.Identify(client, cs.Name); err == nil {
+			csId, diags = rollbackComplianceStandard(client, csId, diags)
+			return csId, ids, diags
+		}
+		log.Printf("[WARN] Compliance standard %q was created but not found: %s", cs.Name, err)
+		return "", ids, append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Compliance standard may be orphaned",
+			Detail:   fmt.Sprintf("Compliance standard %q was created but its ID was not found, so it was not rolled back.  Remove it, or import it with \"name:%s\".", cs.Name, cs.Name),
+		})
+	}
+
+	live := compliance.Catalog{Name: want.Name, Description: want.Description}
+	if diags := applyComplianceStandardBundle(ctx, client, timeout, csId, live, ids, want); diags.HasError() {
+		csId, diags = rollbackComplianceStandard(client, csId, diags)
+		return csId, ids, diags
+	}
+
+	return csId, ids, nil
+}
+
+// rollbackComplianceStandard removes a standard that failed to be created.
+// The standard ID is returned if the removal fails too, so that what was
+// created is left for the next apply.
+func rollbackComplianceStandard(client *pc.Client, csId string, diags diag.Diagnostics) (string, diag.Diagnostics) {
+	if err := removeComplianceStandardBundle(client, csId); err != nil {
+		log.Printf("[WARN] Error rolling back compliance standard %q: %s", csId, err)
+		return csId, append(diags, diag.Diagnostic{
+			Severity: diag.Warning,
+			Summary:  "Compliance standard was not rolled back",
+			Detail:   fmt.Sprintf("Removing the partially created compliance standard %q failed: %s", csId, err),
+		})
+	}
+
+	return "", diags
+}
+
+func createComplianceStandardBundle(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*pc.Client)
+
+	want, err := compliance.Parse([]byte(d.Get("catalog").(string)))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	csId, _, diags := createComplianceStandardCatalog(ctx, client, d.Timeout(schema.TimeoutCreate), want)
+	if csId == "" {
+		return diags
+	}
+
+	d.SetId(csId)
+	return append(diags, readComplianceStandardBundle(ctx, d, meta)...)
+}
+
+func readComplianceStandardBundle(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*pc.Client)
+	csId := d.Id()
+
+	live, ids, err := loadComplianceStandardBundle(client, csId)
+	if err != nil {
+		if err == pc.ObjectNotFoundError {
+			log.Printf("[WARN] Compliance standard %q is gone", csId)
+			d.SetId("")
+			return nil
+		}
+		return diag.FromErr(err)
+	}
+
+	d.Set("catalog", live.Encode())
+	d.Set("cs_id", csId)
+	d.Set("name", live.Name)
+	if err = d.Set("requirement_ids", ids.Requirements); err != nil {
+		log.Printf("[WARN] Error setting 'requirement_ids' for %q: %s", csId, err)
+	}
+	if err = d.Set("section_ids", ids.Sections); err != nil {
+		log.Printf("[WARN] Error setting 'section_ids' for %q: %s", csId, err)
+	}
+
+	return nil
+}
+
+func updateComplianceStandardBundle(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*pc.Client)
+	csId := d.Id()
+
+	want, err := compliance.Parse([]byte(d.Get("catalog").(string)))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	live, ids, err := loadComplianceStandardBundle(client, csId)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if diags := applyComplianceStandardBundle(ctx, client, d.Timeout(schema.TimeoutUpdate), csId, live, ids, want); diags.HasError() {
+		// Save the live standard, so the next apply resumes from it.
+		readComplianceStandardBundle(ctx, d, meta)
+		return diags
+	}
+
+	return readComplianceStandardBundle(ctx, d, meta)
+}
+
+func deleteComplianceStandardBundle(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*pc.Client)
+
+	if err := removeComplianceStandardBundle(client, d.Id()); err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId("")
+	return nil
+}
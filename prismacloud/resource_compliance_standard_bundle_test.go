@@ -0,0 +1,218 @@
+package prismacloud
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/terraform-providers/terraform-provider-prismacloud/internal/compliance"
+	"github.com/terraform-providers/terraform-provider-prismacloud/internal/fakeapi"
+	"golang.org/x/net/context"
+)
+
+const testBundleCatalog = `{
+    "name": "Bundle",
+    "description": "Made by Terraform",
+    "requirements": [
+        {"requirement_id": "1", "name": "Identity", "sections": [
+            {"section_id": "1.1", "description": "MFA", "label": "mfa"},
+            {"section_id": "1.2", "description": "Keys are rotated"}
+        ]},
+        {"requirement_id": "2", "name": "Logging", "sections": [
+            {"section_id": "2.1", "description": "Flow logs"}
+        ]}
+    ]
+}`
+
+const testBundleCatalogUpdated = `{
+    "name": "Bundle",
+    "description": "Made by Terraform",
+    "requirements": [
+        {"requirement_id": "1", "name": "Identity", "sections": [
+            {"section_id": "1.2", "description": "Keys are rotated", "view_order": 1},
+            {"section_id": "1.1", "description": "MFA", "label": "MFA", "view_order": 2},
+            {"section_id": "1.3", "description": "No root keys", "view_order": 3}
+        ]},
+        {"requirement_id": "3", "name": "Network", "sections": [
+            {"section_id": "3.1", "description": "No open SSH"}
+        ]}
+    ]
+}`
+
+func testComplianceStandardBundle(t *testing.T, client interface{}, id, catalog string) (*schema.ResourceData, bool) {
+	t.Helper()
+
+	r := resourceComplianceStandardBundle()
+	d := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{"catalog": catalog})
+	d.SetId(id)
+
+	if id == "" {
+		return d, !r.CreateContext(context.Background(), d, client).HasError()
+	}
+	return d, !r.UpdateContext(context.Background(), d, client).HasError()
+}
+
+func testLiveCatalog(t *testing.T, d *schema.ResourceData) compliance.Catalog {
+	t.Helper()
+
+	c, err := compliance.Parse([]byte(d.Get("catalog").(string)))
+	if err != nil {
+		t.Fatalf("Error parsing catalog in state: %s", err)
+	}
+	return c
+}
+
+func TestComplianceStandardBundleFakeApi(t *testing.T) {
+	shortPollDelays(t)
+	s, client := testFakeClient(t, nil)
+
+	d, ok := testComplianceStandardBundle(t, client, "", testBundleCatalog)
+	if !ok {
+		t.Fatalf("Error in create")
+	}
+	if d.Get("catalog") != normalizeComplianceCatalog(testBundleCatalog) {
+		t.Errorf("Catalog in state is %s", d.Get("catalog"))
+	}
+	if s.Requirements.Len() != 2 || s.Sections.Len() != 3 {
+		t.Fatalf("Created %d requirements and %d sections", s.Requirements.Len(), s.Sections.Len())
+	}
+	reqIds := d.Get("requirement_ids").(map[string]interface{})
+	sectionIds := d.Get("section_ids").(map[string]interface{})
+	if len(reqIds) != 2 || len(sectionIds) != 3 {
+		t.Fatalf("requirement_ids is %v and section_ids is %v", reqIds, sectionIds)
+	}
+	mfaId := sectionIds[TwoStringsToId("1", "1.1")].(string)
+	if obj, _ := s.Sections.Get(mfaId); obj["label"] != "mfa" || obj["viewOrder"] != float64(1) {
+		t.Errorf("Section 1.1 is %v", obj)
+	}
+
+	id := d.Id()
+	d, ok = testComplianceStandardBundle(t, client, id, testBundleCatalogUpdated)
+	if !ok {
+		t.Fatalf("Error in update")
+	}
+	if d.Get("catalog") != normalizeComplianceCatalog(testBundleCatalogUpdated) {
+		t.Errorf("Catalog in state is %s after update", d.Get("catalog"))
+	}
+	if s.Requirements.Len() != 2 || s.Sections.Len() != 4 {
+		t.Errorf("Have %d requirements and %d sections after update", s.Requirements.Len(), s.Sections.Len())
+	}
+	if got := d.Get("section_ids").(map[string]interface{})[TwoStringsToId("1", "1.1")]; got != mfaId {
+		t.Errorf("Section 1.1 was replaced by %v", got)
+	}
+	if obj, _ := s.Sections.Get(mfaId); obj["label"] != "MFA" || obj["viewOrder"] != float64(2) {
+		t.Errorf("Section 1.1 is %v after update", obj)
+	}
+
+	r := resourceComplianceStandardBundle()
+	if diags := r.DeleteContext(context.Background(), d, client); diags.HasError() {
+		t.Fatalf("Error in delete: %v", diags)
+	}
+	if s.Standards.Len() != 0 || s.Requirements.Len() != 0 || s.Sections.Len() != 0 {
+		t.Errorf("Left %d standards, %d requirements and %d sections", s.Standards.Len(), s.Requirements.Len(), s.Sections.Len())
+	}
+}
+
+func TestComplianceStandardBundleResumeFakeApi(t *testing.T) {
+	shortPollDelays(t)
+	s, client := testFakeClient(t, nil)
+
+	d, ok := testComplianceStandardBundle(t, client, "", testBundleCatalog)
+	if !ok {
+		t.Fatalf("Error in create")
+	}
+	id := d.Id()
+
+	// The second new section fails.
+	create := s.Sections.CreateHandler()
+	var posts int
+	s.Handle("POST", "/compliance/{id}/section", func(r *fakeapi.Request) (interface{}, error) {
+		posts++
+		if posts == 2 {
+			return nil, fakeapi.Errorf(400, "section_create_failed", "")
+		}
+		return create(r)
+	})
+
+	d, ok = testComplianceStandardBundle(t, client, id, testBundleCatalogUpdated)
+	if ok {
+		t.Fatalf("Update did not fail")
+	}
+	live := testLiveCatalog(t, d)
+	if _, ok := live.Requirement("2"); ok {
+		t.Errorf("Requirement 2 is in state after it was deleted")
+	}
+	if r, ok := live.Requirement("3"); !ok || len(r.Sections) != 0 {
+		t.Errorf("Requirement 3 is %+v in state, expected no sections", r)
+	}
+
+	// The next apply only makes the remaining change.
+	d, ok = testComplianceStandardBundle(t, client, id, testBundleCatalogUpdated)
+	if !ok {
+		t.Fatalf("Error resuming update")
+	}
+	if posts != 3 {
+		t.Errorf("Sections were created %d times, expected 3", posts)
+	}
+	if d.Get("catalog") != normalizeComplianceCatalog(testBundleCatalogUpdated) {
+		t.Errorf("Catalog in state is %s after resuming", d.Get("catalog"))
+	}
+}
+
+func TestComplianceStandardBundleRollbackFakeApi(t *testing.T) {
+	shortPollDelays(t)
+	s, client := testFakeClient(t, nil)
+
+	s.Handle("POST", "/compliance/{id}/section", func(r *fakeapi.Request) (interface{}, error) {
+		return nil, fakeapi.Errorf(400, "section_create_failed", "")
+	})
+
+	d, ok := testComplianceStandardBundle(t, client, "", testBundleCatalog)
+	if ok {
+		t.Fatalf("Create did not fail")
+	}
+	if d.Id() != "" {
+		t.Errorf("ID is %q after rollback", d.Id())
+	}
+	if s.Standards.Len() != 0 || s.Requirements.Len() != 0 {
+		t.Errorf("Left %d standards and %d requirements", s.Standards.Len(), s.Requirements.Len())
+	}
+}
+
+func TestComplianceStandardBundleIdentifyFailedFakeApi(t *testing.T) {
+	shortPollDelays(t)
+	s, client := testFakeClient(t, nil)
+
+	// The standard is found on the last attempt and rolled back.
+	list := s.Standards.ListHandler("")
+	var lists int
+	s.Handle("GET", "/compliance", func(r *fakeapi.Request) (interface{}, error) {
+		if lists++; lists == 1 {
+			return nil, fakeapi.Errorf(400, "list_failed", "")
+		}
+		return list(r)
+	})
+
+	d, ok := testComplianceStandardBundle(t, client, "", testBundleCatalog)
+	if ok {
+		t.Fatalf("Create did not fail")
+	}
+	if d.Id() != "" || s.Standards.Len() != 0 {
+		t.Errorf("ID is %q and %d standards are left after rollback", d.Id(), s.Standards.Len())
+	}
+
+	// The standard is never found and is named in the error.
+	s.Handle("GET", "/compliance", func(r *fakeapi.Request) (interface{}, error) {
+		return nil, fakeapi.Errorf(400, "list_failed", "")
+	})
+
+	r := resourceComplianceStandardBundle()
+	d = schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{"catalog": testBundleCatalog})
+	diags := r.CreateContext(context.Background(), d, client)
+	if !diags.HasError() {
+		t.Fatalf("Create did not fail")
+	}
+	if last := diags[len(diags)-1]; !strings.Contains(last.Detail, `"Bundle"`) {
+		t.Errorf("Last diagnostic does not name the standard: %+v", last)
+	}
+}
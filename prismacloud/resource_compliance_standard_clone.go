@@ -0,0 +1,297 @@
+package prismacloud
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	pc "github.com/paloaltonetworks/prisma-cloud-go"
+	"github.com/paloaltonetworks/prisma-cloud-go/compliance/standard"
+	"github.com/paloaltonetworks/prisma-cloud-go/compliance/standard/requirement/section"
+	"github.com/terraform-providers/terraform-provider-prismacloud/internal/compliance"
+	"golang.org/x/net/context"
+)
+
+func resourceComplianceStandardClone() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: createComplianceStandardClone,
+		ReadContext:   readComplianceStandardClone,
+		UpdateContext: updateComplianceStandardClone,
+		DeleteContext: deleteComplianceStandardClone,
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(30 * time.Minute),
+			Delete: schema.DefaultTimeout(30 * time.Minute),
+		},
+
+		Schema: map[string]*schema.Schema{
+			// Input.
+			"source_standard_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "ID of the compliance standard to copy, such as a system default standard",
+			},
+			"name": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Compliance standard name",
+			},
+			"description": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Description",
+			},
+			"include_section_ids": {
+				Type:        schema.TypeSet,
+				Optional:    true,
+				ForceNew:    true,
+				Description: "Section IDs to copy, such as 1.1; all sections are copied if unset",
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"exclude_section_ids": {
+				Type:        schema.TypeSet,
+				Optional:    true,
+				ForceNew:    true,
+				Description: "Section IDs not to copy",
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"copy_policy_mappings": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				ForceNew:    true,
+				Description: "Map the policies of the copied sections to the new sections",
+			},
+
+			// Attributes.
+			"cs_id": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Compliance standard ID",
+			},
+			"requirement_ids": {
+				Type:        schema.TypeMap,
+				Computed:    true,
+				Description: "Map of requirement_id to compliance standard requirement ID",
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"section_ids": {
+				Type:        schema.TypeMap,
+				Computed:    true,
+				Description: "Map of requirement_id:section_id to compliance standard requirement section ID",
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+		},
+	}
+}
+
+// parseComplianceStandardClone returns the catalog of the source standard
+// with only the sections that are to be copied.
+func parseComplianceStandardClone(d *schema.ResourceData, source compliance.Catalog) (compliance.Catalog, error) {
+	include := make(map[string]bool)
+	for _, v := range d.Get("include_section_ids").(*schema.Set).List() {
+		include[v.(string)] = false
+	}
+	exclude := make(map[string]bool)
+	for _, v := range d.Get("exclude_section_ids").(*schema.Set).List() {
+		exclude[v.(string)] = true
+	}
+
+	ans := source.Filter(func(r compliance.Requirement, s compliance.Section) bool {
+		if _, ok := include[s.SectionId]; ok {
+			include[s.SectionId] = true
+		} else if len(include) > 0 {
+			return false
+		}
+		return !exclude[s.SectionId]
+	})
+
+	for id, found := range include {
+		if !found {
+			return compliance.Catalog{}, fmt.Errorf("section_id %q is not in compliance standard %q", id, source.Name)
+		}
+	}
+
+	ans.Name = d.Get("name").(string)
+	ans.Description = d.Get("description").(string)
+	return ans, nil
+}
+
+// listComplianceSectionPolicies returns the IDs of the policies mapped to the
+// sections of the given requirements, keyed by requirement ID and section ID.
+func listComplianceSectionPolicies(client *pc.Client, csrIds map[string]string) (map[string][]string, error) {
+	ans := make(map[string][]string)
+	for requirementId, csrId := range csrIds {
+		list, err := section.List(client, csrId)
+		if err != nil {
+			return nil, err
+		}
+		for _, s := range list {
+			if len(s.AssociatedPolicyIds) > 0 {
+				ans[TwoStringsToId(requirementId, s.SectionId)] = s.AssociatedPolicyIds
+			}
+		}
+	}
+
+	return ans, nil
+}
+
+// mapComplianceStandardClonePolicies maps the policies of each source section
+// to the matching section of the clone.
+func mapComplianceStandardClonePolicies(ctx context.Context, client *pc.Client, timeout time.Duration, policies map[string][]string, csrsIds map[string]string) diag.Diagnostics {
+	keys := make([]string, 0, len(csrsIds))
+	for key := range csrsIds {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		for _, policyId := range policies[key] {
+			if diags := modifyCompliancePolicyMapping(ctx, client, timeout, policyId, csrsIds[key], true); diags.HasError() {
+				return diags
+			}
+		}
+	}
+
+	return nil
+}
+
+// unmapComplianceStandardPolicies removes the policy mappings of all sections
+// of a standard.
+func unmapComplianceStandardPolicies(ctx context.Context, client *pc.Client, timeout time.Duration, ids complianceStandardBundleIds) diag.Diagnostics {
+	policies, err := listComplianceSectionPolicies(client, ids.Requirements)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	for key, list := range policies {
+		for _, policyId := range list {
+			if diags := modifyCompliancePolicyMapping(ctx, client, timeout, policyId, ids.Sections[key], false); diags.HasError() {
+				return diags
+			}
+		}
+	}
+
+	return nil
+}
+
+func createComplianceStandardClone(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*pc.Client)
+	timeout := d.Timeout(schema.TimeoutCreate)
+	sourceId := d.Get("source_standard_id").(string)
+
+	source, sourceIds, err := loadComplianceStandardBundle(client, sourceId)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	want, err := parseComplianceStandardClone(d, source)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	var policies map[string][]string
+	if d.Get("copy_policy_mappings").(bool) {
+		if policies, err = listComplianceSectionPolicies(client, sourceIds.Requirements); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	csId, ids, diags := createComplianceStandardCatalog(ctx, client, timeout, want)
+	if csId == "" {
+		return diags
+	}
+
+	if !diags.HasError() && len(policies) > 0 {
+		// Roll back the mappings made so far along with the standard.
+		if diags = mapComplianceStandardClonePolicies(ctx, client, timeout, policies, ids.Sections); diags.HasError() {
+			if undo := unmapComplianceStandardPolicies(ctx, client, timeout, ids); undo.HasError() {
+				diags = append(diags, undo...)
+			} else if csId, diags = rollbackComplianceStandard(client, csId, diags); csId == "" {
+				return diags
+			}
+		}
+	}
+
+	d.SetId(csId)
+	return append(diags, readComplianceStandardClone(ctx, d, meta)...)
+}
+
+func readComplianceStandardClone(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*pc.Client)
+	csId := d.Id()
+
+	live, ids, err := loadComplianceStandardBundle(client, csId)
+	if err != nil {
+		if err == pc.ObjectNotFoundError {
+			log.Printf("[WARN] Compliance standard %q is gone", csId)
+			d.SetId("")
+			return nil
+		}
+		return diag.FromErr(err)
+	}
+
+	d.Set("cs_id", csId)
+	d.Set("name", live.Name)
+	d.Set("description", live.Description)
+	if err = d.Set("requirement_ids", ids.Requirements); err != nil {
+		log.Printf("[WARN] Error setting 'requirement_ids' for %q: %s", csId, err)
+	}
+	if err = d.Set("section_ids", ids.Sections); err != nil {
+		log.Printf("[WARN] Error setting 'section_ids' for %q: %s", csId, err)
+	}
+
+	return nil
+}
+
+func updateComplianceStandardClone(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*pc.Client)
+	o := standard.Standard{
+		Id:          d.Id(),
+		Name:        d.Get("name").(string),
+		Description: d.Get("description").(string),
+	}
+
+	if err := standard.Update(client, o); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return readComplianceStandardClone(ctx, d, meta)
+}
+
+func deleteComplianceStandardClone(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*pc.Client)
+	csId := d.Id()
+
+	if d.Get("copy_policy_mappings").(bool) {
+		_, ids, err := loadComplianceStandardBundle(client, csId)
+		if err != nil {
+			if err == pc.ObjectNotFoundError {
+				d.SetId("")
+				return nil
+			}
+			return diag.FromErr(err)
+		}
+		if diags := unmapComplianceStandardPolicies(ctx, client, d.Timeout(schema.TimeoutDelete), ids); diags.HasError() {
+			return diags
+		}
+	}
+
+	if err := removeComplianceStandardBundle(client, csId); err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId("")
+	return nil
+}
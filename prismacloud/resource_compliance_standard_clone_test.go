@@ -0,0 +1,110 @@
+package prismacloud
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/paloaltonetworks/prisma-cloud-go/policy"
+	"github.com/terraform-providers/terraform-provider-prismacloud/internal/fakeapi"
+	"golang.org/x/net/context"
+)
+
+// testSourceStandard adds a system default standard with requirements 1 and
+// 2, where section 1.1 has a policy mapped to it.
+func testSourceStandard(s *fakeapi.Server) (string, string) {
+	csId := s.Standards.Put(map[string]interface{}{"name": "CIS", "systemDefault": true})
+	req1 := s.Requirements.Put(map[string]interface{}{"complianceId": csId, "requirementId": "1", "name": "IAM", "viewOrder": 1})
+	req2 := s.Requirements.Put(map[string]interface{}{"complianceId": csId, "requirementId": "2", "name": "Logging", "viewOrder": 2})
+	mfa := s.Sections.Put(map[string]interface{}{"requirementId": req1, "sectionId": "1.1", "description": "MFA", "label": "mfa", "viewOrder": 1})
+	s.Sections.Put(map[string]interface{}{"requirementId": req1, "sectionId": "1.2", "description": "Key rotation", "viewOrder": 2})
+	s.Sections.Put(map[string]interface{}{"requirementId": req2, "sectionId": "2.1", "description": "CloudTrail", "viewOrder": 1})
+
+	policyId := s.Policies.Put(policy.Policy{
+		Name:               "mfa",
+		SystemDefault:      true,
+		ComplianceMetadata: []policy.ComplianceMetadata{{ComplianceId: mfa, StandardName: "CIS"}},
+	})
+
+	return csId, policyId
+}
+
+func TestComplianceStandardCloneFakeApi(t *testing.T) {
+	shortPollDelays(t)
+	s, client := testFakeClient(t, nil)
+	ctx := context.Background()
+	r := resourceComplianceStandardClone()
+
+	sourceId, policyId := testSourceStandard(s)
+
+	d := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{
+		"source_standard_id":   sourceId,
+		"name":                 "CIS minus logging",
+		"exclude_section_ids":  []interface{}{"2.1"},
+		"copy_policy_mappings": true,
+	})
+	if diags := r.CreateContext(ctx, d, client); diags.HasError() {
+		t.Fatalf("Error in create: %v", diags)
+	}
+
+	if got := d.Get("requirement_ids").(map[string]interface{}); len(got) != 1 || got["1"] == nil {
+		t.Errorf("requirement_ids is %v", got)
+	}
+	sectionIds := d.Get("section_ids").(map[string]interface{})
+	if len(sectionIds) != 2 {
+		t.Fatalf("section_ids is %v", sectionIds)
+	}
+	mfaId := sectionIds[TwoStringsToId("1", "1.1")].(string)
+	if obj, _ := s.Sections.Get(mfaId); obj["label"] != "mfa" || obj["description"] != "MFA" {
+		t.Errorf("Copied section 1.1 is %v", obj)
+	}
+
+	var obj policy.Policy
+	s.Policies.Load(policyId, &obj)
+	if len(obj.ComplianceMetadata) != 2 || obj.ComplianceMetadata[1].ComplianceId != mfaId {
+		t.Errorf("Compliance metadata is %+v after create", obj.ComplianceMetadata)
+	}
+
+	if diags := r.DeleteContext(ctx, d, client); diags.HasError() {
+		t.Fatalf("Error in delete: %v", diags)
+	}
+	s.Policies.Load(policyId, &obj)
+	if len(obj.ComplianceMetadata) != 1 || obj.ComplianceMetadata[0].StandardName != "CIS" {
+		t.Errorf("Compliance metadata is %+v after delete", obj.ComplianceMetadata)
+	}
+	if s.Standards.Len() != 1 || s.Requirements.Len() != 2 || s.Sections.Len() != 3 {
+		t.Errorf("Have %d standards, %d requirements and %d sections after delete", s.Standards.Len(), s.Requirements.Len(), s.Sections.Len())
+	}
+}
+
+func TestComplianceStandardCloneIncludeFakeApi(t *testing.T) {
+	shortPollDelays(t)
+	s, client := testFakeClient(t, nil)
+	ctx := context.Background()
+	r := resourceComplianceStandardClone()
+
+	sourceId, _ := testSourceStandard(s)
+
+	d := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{
+		"source_standard_id":  sourceId,
+		"name":                "Logging only",
+		"include_section_ids": []interface{}{"2.1"},
+	})
+	if diags := r.CreateContext(ctx, d, client); diags.HasError() {
+		t.Fatalf("Error in create: %v", diags)
+	}
+	if got := d.Get("section_ids").(map[string]interface{}); len(got) != 1 || got[TwoStringsToId("2", "2.1")] == nil {
+		t.Errorf("section_ids is %v", got)
+	}
+
+	d = schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{
+		"source_standard_id":  sourceId,
+		"name":                "Missing",
+		"include_section_ids": []interface{}{"9.9"},
+	})
+	if diags := r.CreateContext(ctx, d, client); !diags.HasError() {
+		t.Errorf("Create with an unknown section did not fail")
+	}
+	if s.Standards.Len() != 2 {
+		t.Errorf("Have %d standards, expected 2", s.Standards.Len())
+	}
+}
@@ -126,20 +126,24 @@ func createComplianceStandardRequirement(ctx context.Context, d *schema.Resource
 		return diag.FromErr(err)
 	}
 
-	PollApiUntilSuccess(func() error {
+	if diags := PollApiUntilSuccess(ctx, d.Timeout(schema.TimeoutCreate), func() error {
 		_, err := requirement.Identify(client, o.ComplianceId, o.Name)
 		return err
-	})
+	}); diags.HasError() {
+		return diags
+	}
 
 	csrId, err := requirement.Identify(client, o.ComplianceId, o.Name)
 	if err != nil {
 		return diag.FromErr(err)
 	}
 
-	PollApiUntilSuccess(func() error {
+	if diags := PollApiUntilSuccess(ctx, d.Timeout(schema.TimeoutCreate), func() error {
 		_, err := requirement.Get(client, csrId)
 		return err
-	})
+	}); diags.HasError() {
+		return diags
+	}
 
 	d.SetId(TwoStringsToId(o.ComplianceId, csrId))
 	return readComplianceStandardRequirement(ctx, d, meta)
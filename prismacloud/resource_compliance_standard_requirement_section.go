@@ -146,20 +146,24 @@ func createComplianceStandardRequirementSection(ctx context.Context, d *schema.R
 		diag.FromErr(err)
 	}
 
-	PollApiUntilSuccess(func() error {
+	if diags := PollApiUntilSuccess(ctx, d.Timeout(schema.TimeoutCreate), func() error {
 		_, err := section.Get(client, o.RequirementId, o.SectionId)
 		return err
-	})
+	}); diags.HasError() {
+		return diags
+	}
 
 	liveObj, err := section.Get(client, o.RequirementId, o.SectionId)
 	if err != nil {
 		diag.FromErr(err)
 	}
 
-	PollApiUntilSuccess(func() error {
+	if diags := PollApiUntilSuccess(ctx, d.Timeout(schema.TimeoutCreate), func() error {
 		_, err := section.GetId(client, o.RequirementId, liveObj.Id)
 		return err
-	})
+	}); diags.HasError() {
+		return diags
+	}
 
 	d.SetId(TwoStringsToId(o.RequirementId, liveObj.Id))
 	return readComplianceStandardRequirementSection(ctx, d, meta)
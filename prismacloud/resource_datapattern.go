@@ -18,9 +18,7 @@ func resourceDataPattern() *schema.Resource {
 		UpdateContext: updateDataPattern,
 		DeleteContext: deleteDataPattern,
 
-		Importer: &schema.ResourceImporter{
-			StateContext: schema.ImportStatePassthroughContext,
-		},
+		Importer: importByName("data pattern", listDataPatternImports),
 
 		Schema: map[string]*schema.Schema{
 			"pattern_id": {
@@ -192,20 +190,24 @@ func createDataPattern(ctx context.Context, d *schema.ResourceData, meta interfa
 		return diag.FromErr(err)
 	}
 
-	PollApiUntilSuccess(func() error {
+	if diags := PollApiUntilSuccess(ctx, d.Timeout(schema.TimeoutCreate), func() error {
 		_, err := datapattern.Identify(client, obj.Name)
 		return err
-	})
+	}); diags.HasError() {
+		return diags
+	}
 
 	id, err := datapattern.Identify(client, obj.Name)
 	if err != nil {
 		return diag.FromErr(err)
 	}
 
-	PollApiUntilSuccess(func() error {
+	if diags := PollApiUntilSuccess(ctx, d.Timeout(schema.TimeoutCreate), func() error {
 		_, err := datapattern.Get(client, id)
 		return err
-	})
+	}); diags.HasError() {
+		return diags
+	}
 
 	d.SetId(id)
 	return readDataPattern(ctx, d, meta)
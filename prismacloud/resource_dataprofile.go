@@ -19,9 +19,7 @@ func resourceDataProfile() *schema.Resource {
 		UpdateContext: updateDataProfile,
 		DeleteContext: deleteDataProfile,
 
-		Importer: &schema.ResourceImporter{
-			StateContext: schema.ImportStatePassthroughContext,
-		},
+		Importer: importByName("data profile", listDataProfileImports),
 
 		Schema: map[string]*schema.Schema{
 			"profile_id": {
@@ -280,20 +278,24 @@ func createDataProfile(ctx context.Context, d *schema.ResourceData, meta interfa
 		return diag.FromErr(err)
 	}
 
-	PollApiUntilSuccess(func() error {
+	if diags := PollApiUntilSuccess(ctx, d.Timeout(schema.TimeoutCreate), func() error {
 		_, err := dataprofile.Identify(client, obj.Name)
 		return err
-	})
+	}); diags.HasError() {
+		return diags
+	}
 
 	id, err := dataprofile.Identify(client, obj.Name)
 	if err != nil {
 		return diag.FromErr(err)
 	}
 
-	PollApiUntilSuccess(func() error {
+	if diags := PollApiUntilSuccess(ctx, d.Timeout(schema.TimeoutCreate), func() error {
 		_, err := dataprofile.Get(client, id)
 		return err
-	})
+	}); diags.HasError() {
+		return diags
+	}
 
 	d.SetId(id)
 	return readDataProfile(ctx, d, meta)
@@ -4,9 +4,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"golang.org/x/net/context"
 	"log"
-	"math/rand"
 	"strings"
-	"time"
 
 	pc "github.com/paloaltonetworks/prisma-cloud-go"
 	"github.com/paloaltonetworks/prisma-cloud-go/integration"
@@ -22,9 +20,7 @@ func resourceIntegration() *schema.Resource {
 		UpdateContext: updateIntegration,
 		DeleteContext: deleteIntegration,
 
-		Importer: &schema.ResourceImporter{
-			StateContext: schema.ImportStatePassthroughContext,
-		},
+		Importer: importByName("integration", listIntegrationImports),
 
 		Schema: map[string]*schema.Schema{
 			"integration_id": {
@@ -664,23 +660,6 @@ func saveIntegration(d *schema.ResourceData, o integration.Integration) {
 	}
 }
 
-type PollerCustom func() (integration.Integration, error)
-
-func PollApiUntilSuccessReadIntegration(p PollerCustom) (integration.Integration, error) {
-	for {
-		if o, err := p(); err == nil {
-			return o, nil
-		} else if "429" == strings.Split(err.Error(), " ")[0] {
-			waitingTime := rand.Intn(5) + 1
-			time.Sleep(time.Duration(waitingTime) * time.Second)
-		} else {
-			return o, err
-
-		}
-	}
-	return integration.Integration{}, nil
-}
-
 func createIntegration(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := meta.(*pc.Client)
 	o := parseIntegration(d, "")
@@ -691,20 +670,20 @@ func createIntegration(ctx context.Context, d *schema.ResourceData, meta interfa
 		prismaIdRequired = false
 	}
 
-	err := PollApiUntilSuccessCustom(func() error {
-		err := integration.Create(client, o, prismaIdRequired)
-		return err
-	})
-	if err != nil {
-		return err
+	if diags := PollApiWhileThrottled(ctx, d.Timeout(schema.TimeoutCreate), func() error {
+		return integration.Create(client, o, prismaIdRequired)
+	}); diags.HasError() {
+		return diags
 	}
 	var id string
 
-	PollApiUntilSuccess(func() error {
+	if diags := PollApiUntilSuccess(ctx, d.Timeout(schema.TimeoutCreate), func() error {
 		id1, err := integration.Identify(client, o.Name, prismaIdRequired)
 		id = id1
 		return err
-	})
+	}); diags.HasError() {
+		return diags
+	}
 	d.SetId(id)
 	return readIntegration(ctx, d, meta)
 }
@@ -721,17 +700,15 @@ func readIntegration(ctx context.Context, d *schema.ResourceData, meta interface
 
 	var o integration.Integration
 	var err error
-	o, err = PollApiUntilSuccessReadIntegration(func() (integration.Integration, error) {
-		o1, err := integration.Get(client, id, prismaIdRequired)
-		return o1, err
-	})
-
-	if err != nil {
+	if diags := PollApiWhileThrottled(ctx, d.Timeout(schema.TimeoutRead), func() error {
+		o, err = integration.Get(client, id, prismaIdRequired)
+		return err
+	}); diags.HasError() {
 		if err == pc.ObjectNotFoundError {
 			d.SetId("")
 			return nil
 		}
-		return diag.FromErr(err)
+		return diags
 	}
 
 	saveIntegration(d, o)
@@ -750,14 +727,10 @@ func updateIntegration(ctx context.Context, d *schema.ResourceData, meta interfa
 		prismaIdRequired = false
 	}
 
-	err := PollApiUntilSuccessCustom(func() error {
-		if err := integration.Update(client, o, prismaIdRequired); err != nil {
-			return err
-		}
-		return nil
-	})
-	if err != nil {
-		return err
+	if diags := PollApiWhileThrottled(ctx, d.Timeout(schema.TimeoutUpdate), func() error {
+		return integration.Update(client, o, prismaIdRequired)
+	}); diags.HasError() {
+		return diags
 	}
 
 	return readIntegration(ctx, d, meta)
@@ -773,18 +746,14 @@ func deleteIntegration(ctx context.Context, d *schema.ResourceData, meta interfa
 		prismaIdRequired = false
 	}
 
-	err := PollApiUntilSuccessCustom(func() error {
+	if diags := PollApiWhileThrottled(ctx, d.Timeout(schema.TimeoutDelete), func() error {
 		err := integration.Delete(client, id, prismaIdRequired)
-		if err != nil {
-			if err != pc.ObjectNotFoundError {
-				return err
-			}
+		if err == pc.ObjectNotFoundError {
+			return nil
 		}
 		return err
-
-	})
-	if err != nil {
-		return err
+	}); diags.HasError() {
+		return diags
 	}
 
 	d.SetId("")
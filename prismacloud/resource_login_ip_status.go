@@ -40,10 +40,12 @@ func createLoginIpStatus(ctx context.Context, d *schema.ResourceData, meta inter
 		return diag.FromErr(err)
 	}
 
-	PollApiUntilSuccess(func() error {
+	if diags := PollApiUntilSuccess(ctx, d.Timeout(schema.TimeoutCreate), func() error {
 		_, err := ip_address.GetLoginIpStatus(client)
 		return err
-	})
+	}); diags.HasError() {
+		return diags
+	}
 
 	d.SetId("login ip status")
 
@@ -61,10 +63,12 @@ func updateLoginIpStatus(ctx context.Context, d *schema.ResourceData, meta inter
 		return diag.FromErr(err)
 	}
 
-	PollApiUntilSuccess(func() error {
+	if diags := PollApiUntilSuccess(ctx, d.Timeout(schema.TimeoutUpdate), func() error {
 		_, err := ip_address.GetLoginIpStatus(client)
 		return err
-	})
+	}); diags.HasError() {
+		return diags
+	}
 
 	d.SetId("login ip status")
 
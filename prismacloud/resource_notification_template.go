@@ -18,6 +18,8 @@ func resourceNotificationTemplate() *schema.Resource {
 		UpdateContext: updateNotificationTemplate,
 		DeleteContext: deleteNotificationTemplate,
 
+		Importer: importByName("notification template", listNotificationTemplateImports),
+
 		Schema: map[string]*schema.Schema{
 			"id": {
 				Type:        schema.TypeString,
@@ -161,8 +163,8 @@ func updateNotificationTemplate(ctx context.Context, d *schema.ResourceData, met
 	client := meta.(*pc.Client)
 	_, o := parseNotificationTemplate(d)
 	var _ notification_template.NotificationTemplate
-	log.Printf("[INFO]: Updating Notification Template, Id:%+v\n", d.Get("id"))
-	if _, err = notification_template.Update(client, o, d.Get("id").(string)); err != nil {
+	log.Printf("[INFO]: Updating Notification Template, Id:%+v\n", d.Id())
+	if _, err = notification_template.Update(client, o, d.Id()); err != nil {
 		if err == pc.ObjectNotFoundError {
 			d.SetId("")
 			return nil
@@ -170,7 +172,6 @@ func updateNotificationTemplate(ctx context.Context, d *schema.ResourceData, met
 		return diag.FromErr(err)
 	}
 	return readNotificationTemplate(ctx, d, meta)
-	return nil
 }
 
 func createNotificationTemplate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
@@ -185,7 +186,6 @@ func createNotificationTemplate(ctx context.Context, d *schema.ResourceData, met
 	log.Printf("[INFO]: Notification Created Successfully, Id:%+v\n", templateRes.Id)
 	d.SetId(templateRes.Id)
 	return readNotificationTemplate(ctx, d, meta)
-	return nil
 }
 
 func parseNotificationTemplate(d *schema.ResourceData) (string, notification_template.NotificationTemplateRequest) {
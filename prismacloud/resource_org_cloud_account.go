@@ -28,9 +28,7 @@ func resourceOrgCloudAccount() *schema.Resource {
 			Delete: schema.DefaultTimeout(5 * time.Minute),
 		},
 
-		Importer: &schema.ResourceImporter{
-			StateContext: schema.ImportStatePassthroughContext,
-		},
+		Importer: importCloudAccountByName(listOrgCloudAccountImports),
 
 		Schema: map[string]*schema.Schema{
 			"disable_on_destroy": {
@@ -877,20 +875,24 @@ func createOrgCloudAccount(ctx context.Context, d *schema.ResourceData, meta int
 			return diag.FromErr(err)
 		}
 	}
-	PollApiUntilSuccess(func() error {
+	if diags := PollApiUntilSuccess(ctx, d.Timeout(schema.TimeoutCreate), func() error {
 		_, err := org.Identify(client, cloudType, name)
 		return err
-	})
+	}); diags.HasError() {
+		return diags
+	}
 
 	id, err := org.Identify(client, cloudType, name)
 	if err != nil {
 		return diag.FromErr(err)
 	}
 
-	PollApiUntilSuccess(func() error {
+	if diags := PollApiUntilSuccess(ctx, d.Timeout(schema.TimeoutCreate), func() error {
 		_, err := org.Get(client, cloudType, id)
 		return err
-	})
+	}); diags.HasError() {
+		return diags
+	}
 
 	d.SetId(TwoStringsToId(cloudType, id))
 	return readOrgCloudAccount(ctx, d, meta)
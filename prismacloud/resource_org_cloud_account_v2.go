@@ -17,7 +17,7 @@ import (
 )
 
 func resourceOrgV2CloudAccount() *schema.Resource {
-	return &schema.Resource{
+	r := &schema.Resource{
 		CreateContext: createOrgV2CloudAccount,
 		ReadContext:   readOrgV2CloudAccount,
 		UpdateContext: updateOrgV2CloudAccount,
@@ -29,9 +29,9 @@ func resourceOrgV2CloudAccount() *schema.Resource {
 			Delete: schema.DefaultTimeout(5 * time.Minute),
 		},
 
-		Importer: &schema.ResourceImporter{
-			StateContext: schema.ImportStatePassthroughContext,
-		},
+		Importer: importCloudAccountByName(listOrgV2CloudAccountImports),
+
+		SchemaVersion: 1,
 
 		Schema: map[string]*schema.Schema{
 			"disable_on_destroy": {
@@ -706,6 +706,17 @@ func resourceOrgV2CloudAccount() *schema.Resource {
 			},
 		},
 	}
+
+	// Version 0 has the same layout, see cloud_account_upgrade.go.
+	r.StateUpgraders = []schema.StateUpgrader{
+		{
+			Version: 0,
+			Type:    r.CoreConfigSchema().ImpliedType(),
+			Upgrade: upgradeOrgV2CloudAccountV0,
+		},
+	}
+
+	return r
 }
 func gcpOrgv2CredentialsMatch(k, old, new string, d *schema.ResourceData) bool {
 	var (
@@ -1047,10 +1058,12 @@ func createOrgV2CloudAccount(ctx context.Context, d *schema.ResourceData, meta i
 			return diag.FromErr(err)
 		}
 	}
-	PollApiUntilSuccess(func() error {
+	if diags := PollApiUntilSuccess(ctx, d.Timeout(schema.TimeoutCreate), func() error {
 		_, err := org.Identify(client, cloudType, name)
 		return err
-	})
+	}); diags.HasError() {
+		return diags
+	}
 
 	accId, err := org.Identify(client, cloudType, name)
 	if err != nil {
@@ -1058,11 +1071,13 @@ func createOrgV2CloudAccount(ctx context.Context, d *schema.ResourceData, meta i
 	}
 
 	var resp1 interface{}
-	PollApiUntilSuccess(func() error {
+	if diags := PollApiUntilSuccess(ctx, d.Timeout(schema.TimeoutCreate), func() error {
 		resp, err := org.Get(client, cloudType, accId)
 		resp1 = resp
 		return err
-	})
+	}); diags.HasError() {
+		return diags
+	}
 
 	d.SetId(TwoStringsToId(cloudType, accId))
 	saveOrgV2CloudAccount(d, cloudType, resp1)
@@ -1118,11 +1133,13 @@ func updateOrgV2CloudAccount(ctx context.Context, d *schema.ResourceData, meta i
 		return diag.FromErr(err)
 	}
 
-	PollApiUntilSuccess(func() error {
+	if diags := PollApiUntilSuccess(ctx, d.Timeout(schema.TimeoutUpdate), func() error {
 		resp, err := org.Get(client, cloudType, accId)
 		resp1 = resp
 		return err
-	})
+	}); diags.HasError() {
+		return diags
+	}
 
 	d.SetId(TwoStringsToId(cloudType, accId))
 	saveOrgV2CloudAccount(d, cloudType, resp1)
@@ -16,9 +16,7 @@ func resourcePermissionGroup() *schema.Resource {
 		UpdateContext: updatePermissionGroup,
 		DeleteContext: deletePermissionGroup,
 
-		Importer: &schema.ResourceImporter{
-			StateContext: schema.ImportStatePassthroughContext,
-		},
+		Importer: importByName("permission group", listPermissionGroupImports),
 
 		Schema: map[string]*schema.Schema{
 			"name": {
@@ -216,10 +214,12 @@ func createPermissionGroup(ctx context.Context, d *schema.ResourceData, meta int
 		return diag.FromErr(err)
 	}
 
-	PollApiUntilSuccess(func() error {
+	if diags := PollApiUntilSuccess(ctx, d.Timeout(schema.TimeoutCreate), func() error {
 		_, err := permission_group.Identify(client, obj.Name)
 		return err
-	})
+	}); diags.HasError() {
+		return diags
+	}
 
 	id, err := permission_group.Identify(client, obj.Name)
 	if err != nil {
@@ -227,11 +227,13 @@ func createPermissionGroup(ctx context.Context, d *schema.ResourceData, meta int
 	}
 
 	var resp1 permission_group.PermissionGroup
-	PollApiUntilSuccess(func() error {
+	if diags := PollApiUntilSuccess(ctx, d.Timeout(schema.TimeoutCreate), func() error {
 		resp, err := permission_group.Get(client, id)
 		resp1 = resp
 		return err
-	})
+	}); diags.HasError() {
+		return diags
+	}
 
 	d.SetId(id)
 	savePermissionGroup(d, resp1)
@@ -263,11 +265,13 @@ func updatePermissionGroup(ctx context.Context, d *schema.ResourceData, meta int
 		return diag.FromErr(err)
 	}
 	var resp1 permission_group.PermissionGroup
-	PollApiUntilSuccess(func() error {
+	if diags := PollApiUntilSuccess(ctx, d.Timeout(schema.TimeoutUpdate), func() error {
 		resp, err := permission_group.Get(client, obj.Id)
 		resp1 = resp
 		return err
-	})
+	}); diags.HasError() {
+		return diags
+	}
 	d.SetId(obj.Id)
 	savePermissionGroup(d, resp1)
 
@@ -30,9 +30,9 @@ func resourcePolicy() *schema.Resource {
 			Delete: schema.DefaultTimeout(5 * time.Minute),
 		},
 
-		Importer: &schema.ResourceImporter{
-			StateContext: schema.ImportStatePassthroughContext,
-		},
+		Importer: importByListedName("policy", listPolicyImports),
+
+		CustomizeDiff: customizePolicyDiff,
 
 		Schema: map[string]*schema.Schema{
 			"policy_id": {
@@ -242,9 +242,11 @@ func resourcePolicy() *schema.Resource {
 							Description: "Resource ID path",
 						},
 						"criteria": {
-							Type:        schema.TypeString,
-							Optional:    true,
-							Description: "Saved search ID that defines the rule criteria",
+							Type:             schema.TypeString,
+							Optional:         true,
+							Description:      "Saved search ID or RQL query that defines the rule criteria",
+							ValidateFunc:     validateRqlCriteria,
+							DiffSuppressFunc: suppressEquivalentCriteria,
 						},
 						"data_criteria": {
 							Type:        schema.TypeList,
@@ -376,9 +378,10 @@ func resourcePolicy() *schema.Resource {
 							Description: "CLI script template",
 						},
 						"cli_script_json_schema_string": {
-							Type:        schema.TypeString,
-							Optional:    true,
-							Description: "CLI script JSON schema",
+							Type:             schema.TypeString,
+							Optional:         true,
+							Description:      "CLI script JSON schema",
+							DiffSuppressFunc: suppressEquivalentJson,
 						},
 						"actions": {
 							Type:     schema.TypeList,
@@ -735,20 +738,24 @@ func createPolicy(ctx context.Context, d *schema.ResourceData, meta interface{})
 		return diag.FromErr(err)
 	}
 
-	PollApiUntilSuccess(func() error {
+	if diags := PollApiUntilSuccess(ctx, d.Timeout(schema.TimeoutCreate), func() error {
 		_, err := policy.Identify(client, obj.Name)
 		return err
-	})
+	}); diags.HasError() {
+		return diags
+	}
 
 	id, err := policy.Identify(client, obj.Name)
 	if err != nil {
 		return diag.FromErr(err)
 	}
 
-	PollApiUntilSuccess(func() error {
+	if diags := PollApiUntilSuccess(ctx, d.Timeout(schema.TimeoutCreate), func() error {
 		_, err := policy.Get(client, id)
 		return err
-	})
+	}); diags.HasError() {
+		return diags
+	}
 
 	d.SetId(id)
 	return readPolicy(ctx, d, meta)
@@ -0,0 +1,271 @@
+package prismacloud
+
+import (
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	pc "github.com/paloaltonetworks/prisma-cloud-go"
+	"github.com/paloaltonetworks/prisma-cloud-go/policy"
+	"golang.org/x/net/context"
+)
+
+func resourcePolicyStatus() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: createPolicyStatus,
+		ReadContext:   readPolicyStatus,
+		UpdateContext: updatePolicyStatus,
+		DeleteContext: deletePolicyStatus,
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(5 * time.Minute),
+			Update: schema.DefaultTimeout(5 * time.Minute),
+			Delete: schema.DefaultTimeout(5 * time.Minute),
+		},
+
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Schema: map[string]*schema.Schema{
+			// Input.
+			"policy_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "Policy ID",
+			},
+			"enabled": {
+				Type:        schema.TypeBool,
+				Required:    true,
+				Description: "Enabled",
+			},
+			"severity": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Description:  "Severity override, left as is if unset",
+				ValidateFunc: validatePolicySeverity(),
+			},
+			"restrict_alert_dismissal": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Computed:    true,
+				Description: "Restrict alert dismissal, left as is if unset",
+			},
+
+			// Attributes.
+			"name": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Policy name",
+			},
+			"system_default": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "If the policy is a system default policy",
+			},
+			"original_enabled": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Enabled before this resource was created, restored on destroy",
+			},
+			"original_severity": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Severity before this resource was created, restored on destroy",
+			},
+			"original_restrict_alert_dismissal": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Restrict alert dismissal before this resource was created, restored on destroy",
+			},
+		},
+	}
+}
+
+func validatePolicySeverity() schema.SchemaValidateFunc {
+	return validation.StringInSlice(
+		[]string{
+			policy.SeverityLow,
+			policy.SeverityMedium,
+			policy.SeverityHigh,
+			policy.SeverityCritical,
+			policy.SeverityInformational,
+		},
+		false,
+	)
+}
+
+// policyStatus is the part of a policy managed by the policy status
+// resources.
+type policyStatus struct {
+	Enabled                bool
+	Severity               string
+	RestrictAlertDismissal bool
+}
+
+func policyStatusOf(obj policy.Policy) policyStatus {
+	return policyStatus{
+		Enabled:                obj.Enabled,
+		Severity:               obj.Severity,
+		RestrictAlertDismissal: obj.RestrictAlertDismissal,
+	}
+}
+
+// updatePolicyEnabled enables or disables a policy without sending the rest
+// of the policy.
+func updatePolicyEnabled(c pc.PrismaCloudClient, id string, enabled bool) error {
+	c.Log(pc.LogAction, "(update) policy status id:%s enabled:%t", id, enabled)
+
+	_, err := c.Communicate("PATCH", []string{"policy", id, "status", strconv.FormatBool(enabled)}, nil, nil, nil)
+	return err
+}
+
+/*
+setPolicyStatus changes the status of a policy to the one given, leaving the
+rest of the policy alone.
+
+Only enabling or disabling a policy uses the status call.  Other changes get
+the live policy and write it back with the new status.  Setting the status of
+a policy that no longer exists succeeds if remove is true.
+*/
+func setPolicyStatus(ctx context.Context, client *pc.Client, timeout time.Duration, id string, want policyStatus, remove bool) diag.Diagnostics {
+	return readModifyWrite(ctx, timeout, "policy "+id, func() error {
+		obj, err := policy.Get(client, id)
+		if err != nil {
+			if err == pc.ObjectNotFoundError && remove {
+				return nil
+			}
+			return err
+		}
+
+		cur := policyStatusOf(obj)
+		switch {
+		case cur == want:
+			return nil
+		case cur.Severity == want.Severity && cur.RestrictAlertDismissal == want.RestrictAlertDismissal:
+			err = updatePolicyEnabled(client, id, want.Enabled)
+		default:
+			obj.Enabled = want.Enabled
+			obj.Severity = want.Severity
+			obj.RestrictAlertDismissal = want.RestrictAlertDismissal
+			err = policy.Update(client, obj)
+		}
+		if err != nil {
+			return err
+		}
+
+		if obj, err = policy.Get(client, id); err != nil {
+			return err
+		}
+		if policyStatusOf(obj) != want {
+			return concurrentModification("policy", id)
+		}
+
+		return nil
+	})
+}
+
+func createPolicyStatus(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*pc.Client)
+	id := d.Get("policy_id").(string)
+
+	obj, err := policy.Get(client, id)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	orig := policyStatusOf(obj)
+	want := orig
+	want.Enabled = d.Get("enabled").(bool)
+	if v, ok := d.GetOk("severity"); ok {
+		want.Severity = v.(string)
+	}
+	if v, ok := d.GetOkExists("restrict_alert_dismissal"); ok {
+		want.RestrictAlertDismissal = v.(bool)
+	}
+
+	if diags := setPolicyStatus(ctx, client, d.Timeout(schema.TimeoutCreate), id, want, false); diags.HasError() {
+		return diags
+	}
+
+	d.SetId(id)
+	d.Set("original_enabled", orig.Enabled)
+	d.Set("original_severity", orig.Severity)
+	d.Set("original_restrict_alert_dismissal", orig.RestrictAlertDismissal)
+	return readPolicyStatus(ctx, d, meta)
+}
+
+func readPolicyStatus(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*pc.Client)
+	id := d.Id()
+
+	obj, err := policy.Get(client, id)
+	if err != nil {
+		if err == pc.ObjectNotFoundError {
+			log.Printf("[WARN] Policy %q is gone", id)
+			d.SetId("")
+			return nil
+		}
+		return diag.FromErr(err)
+	}
+
+	// Imported resources take the status at import time as the original.
+	if d.Get("original_severity").(string) == "" {
+		d.Set("original_enabled", obj.Enabled)
+		d.Set("original_severity", obj.Severity)
+		d.Set("original_restrict_alert_dismissal", obj.RestrictAlertDismissal)
+	}
+
+	d.Set("policy_id", obj.PolicyId)
+	d.Set("enabled", obj.Enabled)
+	// Only an overridden severity is tracked, so that removing the override
+	// shows as a change.
+	if d.Get("severity").(string) != "" {
+		d.Set("severity", obj.Severity)
+	}
+	d.Set("restrict_alert_dismissal", obj.RestrictAlertDismissal)
+	d.Set("name", obj.Name)
+	d.Set("system_default", obj.SystemDefault)
+
+	return nil
+}
+
+func updatePolicyStatus(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*pc.Client)
+	want := policyStatus{
+		Enabled:                d.Get("enabled").(bool),
+		Severity:               d.Get("severity").(string),
+		RestrictAlertDismissal: d.Get("restrict_alert_dismissal").(bool),
+	}
+
+	// Drop a severity override by restoring the original severity.
+	if want.Severity == "" {
+		want.Severity = d.Get("original_severity").(string)
+	}
+
+	if diags := setPolicyStatus(ctx, client, d.Timeout(schema.TimeoutUpdate), d.Id(), want, false); diags.HasError() {
+		return diags
+	}
+
+	return readPolicyStatus(ctx, d, meta)
+}
+
+func deletePolicyStatus(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*pc.Client)
+	want := policyStatus{
+		Enabled:                d.Get("original_enabled").(bool),
+		Severity:               d.Get("original_severity").(string),
+		RestrictAlertDismissal: d.Get("original_restrict_alert_dismissal").(bool),
+	}
+
+	if diags := setPolicyStatus(ctx, client, d.Timeout(schema.TimeoutDelete), d.Id(), want, true); diags.HasError() {
+		return diags
+	}
+
+	d.SetId("")
+	return nil
+}
@@ -0,0 +1,111 @@
+package prismacloud
+
+import (
+	"testing"
+
+	"github.com/terraform-providers/terraform-provider-prismacloud/internal/fakeapi"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/paloaltonetworks/prisma-cloud-go/policy"
+	"golang.org/x/net/context"
+)
+
+func testPolicyStatus(t *testing.T, s *fakeapi.Server, id string) policyStatus {
+	t.Helper()
+
+	var obj policy.Policy
+	if !s.Policies.Load(id, &obj) {
+		t.Fatalf("Policy %q does not exist", id)
+	}
+	return policyStatusOf(obj)
+}
+
+func TestPolicyStatusFakeApi(t *testing.T) {
+	s, client := testFakeClient(t, nil)
+	ctx := context.Background()
+	r := resourcePolicyStatus()
+
+	var puts int
+	update := s.Policies.UpdateHandler()
+	s.Handle("PUT", "/policy/{id}", func(req *fakeapi.Request) (interface{}, error) {
+		puts++
+		return update(req)
+	})
+
+	id := s.Policies.Put(policy.Policy{
+		Name:          "AWS S3 bucket is public",
+		PolicyType:    policy.PolicyTypeConfig,
+		SystemDefault: true,
+		Severity:      policy.SeverityHigh,
+		Enabled:       true,
+	})
+
+	d := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{
+		"policy_id": id,
+		"enabled":   false,
+	})
+	if diags := r.CreateContext(ctx, d, client); diags.HasError() {
+		t.Fatalf("Error in create: %v", diags)
+	}
+	if got := testPolicyStatus(t, s, id); got != (policyStatus{Severity: policy.SeverityHigh}) {
+		t.Fatalf("Policy status is %+v after create", got)
+	}
+	if puts != 0 {
+		t.Errorf("Policy was updated %d times to disable it, expected the status call", puts)
+	}
+	if d.Get("severity").(string) != "" || d.Get("original_severity").(string) != policy.SeverityHigh || !d.Get("original_enabled").(bool) {
+		t.Errorf("Unexpected state after create: %v", d.State().Attributes)
+	}
+
+	d.Set("severity", policy.SeverityLow)
+	d.Set("restrict_alert_dismissal", true)
+	if diags := r.UpdateContext(ctx, d, client); diags.HasError() {
+		t.Fatalf("Error in update: %v", diags)
+	}
+	want := policyStatus{Severity: policy.SeverityLow, RestrictAlertDismissal: true}
+	if got := testPolicyStatus(t, s, id); got != want {
+		t.Fatalf("Policy status is %+v after update", got)
+	}
+
+	if d.Get("severity").(string) != policy.SeverityLow {
+		t.Errorf("severity is %q after update", d.Get("severity"))
+	}
+
+	// Removing the override restores the original severity.
+	d.Set("severity", "")
+	if diags := r.UpdateContext(ctx, d, client); diags.HasError() {
+		t.Fatalf("Error in update: %v", diags)
+	}
+	want.Severity = policy.SeverityHigh
+	if got := testPolicyStatus(t, s, id); got != want {
+		t.Fatalf("Policy status is %+v after removing severity", got)
+	}
+
+	d.Set("severity", policy.SeverityLow)
+	if diags := r.UpdateContext(ctx, d, client); diags.HasError() {
+		t.Fatalf("Error in update: %v", diags)
+	}
+
+	// Imported resources restore the status at import time.
+	imported := schema.TestResourceDataRaw(t, r.Schema, nil)
+	imported.SetId(id)
+	if diags := r.ReadContext(ctx, imported, client); diags.HasError() {
+		t.Fatalf("Error in read: %v", diags)
+	}
+	if imported.Get("original_severity").(string) != policy.SeverityLow {
+		t.Errorf("Imported original_severity is %q", imported.Get("original_severity"))
+	}
+
+	if diags := r.DeleteContext(ctx, d, client); diags.HasError() {
+		t.Fatalf("Error in delete: %v", diags)
+	}
+	want = policyStatus{Enabled: true, Severity: policy.SeverityHigh}
+	if got := testPolicyStatus(t, s, id); got != want {
+		t.Errorf("Policy status is %+v after delete, expected %+v", got, want)
+	}
+
+	s.Policies.Delete(id)
+	if diags := r.DeleteContext(ctx, d, client); diags.HasError() {
+		t.Errorf("Error deleting the status of a deleted policy: %v", diags)
+	}
+}
@@ -0,0 +1,349 @@
+package prismacloud
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	pc "github.com/paloaltonetworks/prisma-cloud-go"
+	"github.com/paloaltonetworks/prisma-cloud-go/policy"
+	"golang.org/x/net/context"
+)
+
+func resourcePolicyStatuses() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: createPolicyStatuses,
+		ReadContext:   readPolicyStatuses,
+		UpdateContext: updatePolicyStatuses,
+		DeleteContext: deletePolicyStatuses,
+
+		CustomizeDiff: customizePolicyStatusesDiff,
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(20 * time.Minute),
+			Update: schema.DefaultTimeout(20 * time.Minute),
+			Delete: schema.DefaultTimeout(20 * time.Minute),
+		},
+
+		Schema: map[string]*schema.Schema{
+			// Input.
+			"filters": {
+				Type:        schema.TypeMap,
+				Required:    true,
+				Description: "Policy list filters that select the policies, such as policy.label, policy.severity or policy.complianceStandard",
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"enabled": {
+				Type:        schema.TypeBool,
+				Required:    true,
+				Description: "Enabled",
+			},
+			"severity": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Description:  "Severity override, left as is if unset",
+				ValidateFunc: validatePolicySeverity(),
+			},
+
+			// Attributes.
+			"policies": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "Policies managed by this resource",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"policy_id": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Policy ID",
+						},
+						"name": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Policy name",
+						},
+						"original_enabled": {
+							Type:        schema.TypeBool,
+							Computed:    true,
+							Description: "Enabled before this resource managed the policy, restored on destroy",
+						},
+						"original_severity": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Severity before this resource managed the policy, restored on destroy",
+						},
+					},
+				},
+			},
+			"drifted_policy_ids": {
+				Type:        schema.TypeSet,
+				Computed:    true,
+				Description: "IDs of the policies that are changed in the next update, such as policies that were enabled elsewhere or that newly match the filters",
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+		},
+	}
+}
+
+// policyStatusesEntry is a policy managed by a policy statuses resource.
+type policyStatusesEntry struct {
+	Id       string
+	Name     string
+	Original policyStatus
+}
+
+func parsePolicyStatusesEntries(d *schema.ResourceData) []policyStatusesEntry {
+	list := d.Get("policies").([]interface{})
+	ans := make([]policyStatusesEntry, 0, len(list))
+	for i := range list {
+		m := list[i].(map[string]interface{})
+		ans = append(ans, policyStatusesEntry{
+			Id:   m["policy_id"].(string),
+			Name: m["name"].(string),
+			Original: policyStatus{
+				Enabled:  m["original_enabled"].(bool),
+				Severity: m["original_severity"].(string),
+			},
+		})
+	}
+
+	return ans
+}
+
+func savePolicyStatusesEntries(d *schema.ResourceData, entries []policyStatusesEntry) {
+	list := make([]interface{}, 0, len(entries))
+	for _, e := range entries {
+		list = append(list, map[string]interface{}{
+			"policy_id":         e.Id,
+			"name":              e.Name,
+			"original_enabled":  e.Original.Enabled,
+			"original_severity": e.Original.Severity,
+		})
+	}
+
+	if err := d.Set("policies", list); err != nil {
+		log.Printf("[WARN] Error setting 'policies' field for %q: %s", d.Id(), err)
+	}
+}
+
+// listPolicyStatuses returns the policies selected by the filters.
+func listPolicyStatuses(client *pc.Client, d *schema.ResourceData) ([]policy.Policy, error) {
+	filters := d.Get("filters").(map[string]interface{})
+	query := make(map[string]string, len(filters))
+	for k, v := range filters {
+		query[k] = v.(string)
+	}
+
+	return policy.List(client, query)
+}
+
+// wantPolicyStatus returns the status that a selected policy should have.
+func wantPolicyStatus(d *schema.ResourceData, obj policy.Policy) policyStatus {
+	ans := policyStatusOf(obj)
+	ans.Enabled = d.Get("enabled").(bool)
+	if v := d.Get("severity").(string); v != "" {
+		ans.Severity = v
+	}
+
+	return ans
+}
+
+// restorePolicyStatus restores the original status of a policy that is no
+// longer managed by the resource.  Policies that are gone are skipped.
+func restorePolicyStatus(ctx context.Context, client *pc.Client, timeout time.Duration, e policyStatusesEntry) diag.Diagnostics {
+	obj, err := policy.Get(client, e.Id)
+	if err != nil {
+		if err == pc.ObjectNotFoundError {
+			return nil
+		}
+		return diag.FromErr(err)
+	}
+
+	want := policyStatusOf(obj)
+	want.Enabled = e.Original.Enabled
+	want.Severity = e.Original.Severity
+
+	return setPolicyStatus(ctx, client, timeout, e.Id, want, true)
+}
+
+/*
+applyPolicyStatuses sets the status of the policies selected by the filters,
+and restores the status of the policies that it managed before but are no
+longer selected.
+
+The policies managed so far are saved even if a policy fails, so that they
+are restored on destroy.
+*/
+func applyPolicyStatuses(ctx context.Context, client *pc.Client, d *schema.ResourceData, timeout time.Duration) diag.Diagnostics {
+	matched, err := listPolicyStatuses(client, d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	selected := make(map[string]bool, len(matched))
+	for _, obj := range matched {
+		selected[obj.PolicyId] = true
+	}
+
+	// Drop a severity override by restoring the original severities.
+	restoreSeverity := d.HasChange("severity") && d.Get("severity").(string) == ""
+
+	entries := parsePolicyStatusesEntries(d)
+	tracked := make(map[string]policyStatusesEntry, len(entries))
+	kept := make([]policyStatusesEntry, 0, len(entries)+len(matched))
+	for i, e := range entries {
+		if selected[e.Id] {
+			tracked[e.Id] = e
+			kept = append(kept, e)
+			continue
+		}
+
+		if diags := restorePolicyStatus(ctx, client, timeout, e); diags.HasError() {
+			savePolicyStatusesEntries(d, append(kept, entries[i:]...))
+			return diags
+		}
+	}
+
+	managed := make([]policyStatusesEntry, 0, len(matched))
+	for i, obj := range matched {
+		e, ok := tracked[obj.PolicyId]
+		if !ok {
+			e = policyStatusesEntry{Id: obj.PolicyId, Original: policyStatusOf(obj)}
+		}
+		e.Name = obj.Name
+
+		want := wantPolicyStatus(d, obj)
+		if restoreSeverity {
+			want.Severity = e.Original.Severity
+		}
+		if diags := setPolicyStatus(ctx, client, timeout, obj.PolicyId, want, false); diags.HasError() {
+			// Keep the policies not reached yet that were managed before.
+			for _, rest := range matched[i:] {
+				if e, ok := tracked[rest.PolicyId]; ok {
+					managed = append(managed, e)
+				}
+			}
+			savePolicyStatusesEntries(d, managed)
+			return diags
+		}
+		managed = append(managed, e)
+	}
+
+	savePolicyStatusesEntries(d, managed)
+	return nil
+}
+
+func customizePolicyStatusesDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	// An override would move policies out of a severity filter, and the
+	// next refresh would restore them.
+	if _, ok := d.Get("filters").(map[string]interface{})["policy.severity"]; ok && d.Get("severity").(string) != "" {
+		return fmt.Errorf("severity can't be overridden for policies selected by the policy.severity filter")
+	}
+
+	if d.Id() == "" {
+		return nil
+	}
+
+	// Drifted policies are changed in an update.
+	if d.Get("drifted_policy_ids").(*schema.Set).Len() > 0 {
+		if err := d.SetNewComputed("policies"); err != nil {
+			return err
+		}
+		return d.SetNewComputed("drifted_policy_ids")
+	}
+
+	return nil
+}
+
+func createPolicyStatuses(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*pc.Client)
+
+	d.SetId(resource.UniqueId())
+	if diags := applyPolicyStatuses(ctx, client, d, d.Timeout(schema.TimeoutCreate)); diags.HasError() {
+		return diags
+	}
+
+	return readPolicyStatuses(ctx, d, meta)
+}
+
+func readPolicyStatuses(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*pc.Client)
+
+	matched, err := listPolicyStatuses(client, d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	entries := parsePolicyStatusesEntries(d)
+	tracked := make(map[string]bool, len(entries))
+	for _, e := range entries {
+		tracked[e.Id] = true
+	}
+
+	drifted := make([]string, 0)
+	selected := make(map[string]bool, len(matched))
+	for _, obj := range matched {
+		selected[obj.PolicyId] = true
+		if !tracked[obj.PolicyId] || policyStatusOf(obj) != wantPolicyStatus(d, obj) {
+			drifted = append(drifted, obj.PolicyId)
+		}
+	}
+
+	// Policies that are no longer selected are restored in an update, unless
+	// they are gone.
+	kept := make([]policyStatusesEntry, 0, len(entries))
+	for _, e := range entries {
+		if !selected[e.Id] {
+			if _, err = policy.Get(client, e.Id); err != nil {
+				if err == pc.ObjectNotFoundError {
+					log.Printf("[WARN] Policy %q of %q is gone", e.Id, d.Id())
+					continue
+				}
+				return diag.FromErr(err)
+			}
+			drifted = append(drifted, e.Id)
+		}
+		kept = append(kept, e)
+	}
+
+	if len(drifted) > 0 {
+		log.Printf("[DEBUG] Policy statuses %q has %d drifted policies", d.Id(), len(drifted))
+	}
+	savePolicyStatusesEntries(d, kept)
+	d.Set("drifted_policy_ids", drifted)
+
+	return nil
+}
+
+func updatePolicyStatuses(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*pc.Client)
+
+	if diags := applyPolicyStatuses(ctx, client, d, d.Timeout(schema.TimeoutUpdate)); diags.HasError() {
+		return diags
+	}
+
+	return readPolicyStatuses(ctx, d, meta)
+}
+
+func deletePolicyStatuses(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*pc.Client)
+	timeout := d.Timeout(schema.TimeoutDelete)
+
+	entries := parsePolicyStatusesEntries(d)
+	for i, e := range entries {
+		if diags := restorePolicyStatus(ctx, client, timeout, e); diags.HasError() {
+			savePolicyStatusesEntries(d, entries[i:])
+			return diags
+		}
+	}
+
+	d.SetId("")
+	return nil
+}
@@ -0,0 +1,103 @@
+package prismacloud
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/paloaltonetworks/prisma-cloud-go/policy"
+	"golang.org/x/net/context"
+)
+
+func TestPolicyStatusesFakeApi(t *testing.T) {
+	s, client := testFakeClient(t, nil)
+	ctx := context.Background()
+	r := resourcePolicyStatuses()
+
+	a := s.Policies.Put(policy.Policy{Name: "a", Labels: []string{"noisy"}, Severity: policy.SeverityHigh, Enabled: true})
+	b := s.Policies.Put(policy.Policy{Name: "b", Labels: []string{"noisy"}, Severity: policy.SeverityLow})
+	c := s.Policies.Put(policy.Policy{
+		Name:               "c",
+		Severity:           policy.SeverityMedium,
+		Enabled:            true,
+		ComplianceMetadata: []policy.ComplianceMetadata{{StandardName: "CIS"}},
+	})
+
+	d := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{
+		"filters": map[string]interface{}{"policy.label": "noisy"},
+		"enabled": false,
+	})
+	if diags := r.CreateContext(ctx, d, client); diags.HasError() {
+		t.Fatalf("Error in create: %v", diags)
+	}
+	for _, id := range []string{a, b} {
+		if testPolicyStatus(t, s, id).Enabled {
+			t.Errorf("Policy %q is enabled after create", id)
+		}
+	}
+	if !testPolicyStatus(t, s, c).Enabled {
+		t.Errorf("Policy not selected was disabled")
+	}
+	if n := len(d.Get("policies").([]interface{})); n != 2 {
+		t.Fatalf("Got %d policies, expected 2", n)
+	}
+
+	// Enabled elsewhere, and a new policy with the label.
+	var obj policy.Policy
+	s.Policies.Load(a, &obj)
+	obj.Enabled = true
+	s.Policies.Put(obj)
+	n := s.Policies.Put(policy.Policy{Name: "new", Labels: []string{"noisy"}, Severity: policy.SeverityLow, Enabled: true})
+
+	if diags := r.ReadContext(ctx, d, client); diags.HasError() {
+		t.Fatalf("Error in read: %v", diags)
+	}
+	drifted := d.Get("drifted_policy_ids").(*schema.Set)
+	if drifted.Len() != 2 || !drifted.Contains(a) || !drifted.Contains(n) {
+		t.Errorf("Drifted policies are %v, expected %s and %s", drifted.List(), a, n)
+	}
+
+	// Select by compliance standard instead, with a severity override.
+	d.Set("filters", map[string]interface{}{"policy.complianceStandard": "CIS"})
+	d.Set("severity", policy.SeverityCritical)
+	if diags := r.UpdateContext(ctx, d, client); diags.HasError() {
+		t.Fatalf("Error in update: %v", diags)
+	}
+	for id, want := range map[string]policyStatus{
+		a: {Enabled: true, Severity: policy.SeverityHigh},
+		b: {Severity: policy.SeverityLow},
+		c: {Severity: policy.SeverityCritical},
+		n: {Enabled: true, Severity: policy.SeverityLow},
+	} {
+		if got := testPolicyStatus(t, s, id); got != want {
+			t.Errorf("Policy %q status is %+v after update, expected %+v", id, got, want)
+		}
+	}
+	if d.Get("drifted_policy_ids").(*schema.Set).Len() != 0 {
+		t.Errorf("Drifted policies after update: %v", d.Get("drifted_policy_ids").(*schema.Set).List())
+	}
+
+	if diags := r.DeleteContext(ctx, d, client); diags.HasError() {
+		t.Fatalf("Error in delete: %v", diags)
+	}
+	if got, want := testPolicyStatus(t, s, c), (policyStatus{Enabled: true, Severity: policy.SeverityMedium}); got != want {
+		t.Errorf("Policy status is %+v after delete, expected %+v", got, want)
+	}
+}
+
+func TestPolicyStatusesSeverityFilterOverride(t *testing.T) {
+	r := resourcePolicyStatuses()
+	for _, tc := range []struct {
+		raw map[string]interface{}
+		ok  bool
+	}{
+		{map[string]interface{}{"filters": map[string]interface{}{"policy.severity": "low"}, "enabled": false}, true},
+		{map[string]interface{}{"filters": map[string]interface{}{"policy.label": "x"}, "enabled": false, "severity": "high"}, true},
+		{map[string]interface{}{"filters": map[string]interface{}{"policy.severity": "low"}, "enabled": false, "severity": "high"}, false},
+	} {
+		_, err := r.Diff(context.Background(), nil, terraform.NewResourceConfigRaw(tc.raw), nil)
+		if (err == nil) != tc.ok {
+			t.Errorf("Diff of %v returned %v", tc.raw, err)
+		}
+	}
+}
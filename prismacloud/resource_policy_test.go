@@ -10,7 +10,9 @@ import (
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"golang.org/x/net/context"
 )
 
 func TestAccPolicyConfig(t *testing.T) {
@@ -220,3 +222,78 @@ resource "prismacloud_policy" "test" {
 
 	return buf.String()
 }
+
+func TestPolicyFakeApi(t *testing.T) {
+	s, client := testFakeClient(t, nil)
+	ctx := context.Background()
+	r := resourcePolicy()
+
+	d := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{
+		"name":        "tf policy",
+		"policy_type": policy.PolicyTypeConfig,
+		"cloud_type":  "aws",
+		"severity":    policy.SeverityLow,
+		"description": "first",
+		"rule": []interface{}{map[string]interface{}{
+			"name":      "tf policy",
+			"rule_type": policy.RuleTypeConfig,
+			"criteria":  "config from cloud.resource where api.name = 'aws-ec2-describe-instances'",
+			"parameters": map[string]interface{}{
+				"savedSearch": "false",
+			},
+		}},
+	})
+
+	if diags := r.CreateContext(ctx, d, client); diags.HasError() {
+		t.Fatalf("Error in create: %v", diags)
+	}
+	id := d.Id()
+	if id == "" {
+		t.Fatalf("ID was not set on create")
+	}
+
+	var o policy.Policy
+	if !s.Policies.Load(id, &o) {
+		t.Fatalf("Policy %q not stored", id)
+	}
+	if o.Name != "tf policy" || o.Description != "first" {
+		t.Fatalf("Policy stored with name %q description %q", o.Name, o.Description)
+	}
+
+	d.Set("description", "second")
+	if diags := r.UpdateContext(ctx, d, client); diags.HasError() {
+		t.Fatalf("Error in update: %v", diags)
+	}
+	s.Policies.Load(id, &o)
+	if o.Description != "second" {
+		t.Fatalf("Description is %q, expected %q", o.Description, "second")
+	}
+
+	imp := r.TestResourceData()
+	imp.SetId(id)
+	states, err := r.Importer.StateContext(ctx, imp, client)
+	if err != nil {
+		t.Fatalf("Error in import: %s", err)
+	}
+	if diags := r.ReadContext(ctx, states[0], client); diags.HasError() {
+		t.Fatalf("Error in read: %v", diags)
+	}
+	if v := states[0].Get("name").(string); v != "tf policy" {
+		t.Fatalf("Imported name is %q, expected %q", v, "tf policy")
+	}
+
+	if diags := r.DeleteContext(ctx, d, client); diags.HasError() {
+		t.Fatalf("Error in delete: %v", diags)
+	}
+	if s.Policies.Len() != 0 {
+		t.Fatalf("Policy still exists after delete")
+	}
+
+	d.SetId(id)
+	if diags := r.ReadContext(ctx, d, client); diags.HasError() {
+		t.Fatalf("Error in read after delete: %v", diags)
+	}
+	if d.Id() != "" {
+		t.Fatalf("ID was not cleared for deleted policy")
+	}
+}
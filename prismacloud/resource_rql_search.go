@@ -4,8 +4,11 @@ import (
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"golang.org/x/net/context"
 	"log"
+	"strings"
+	"time"
 
 	pc "github.com/paloaltonetworks/prisma-cloud-go"
+	"github.com/paloaltonetworks/prisma-cloud-go/rql/history"
 	"github.com/paloaltonetworks/prisma-cloud-go/rql/search"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -14,10 +17,17 @@ import (
 
 func resourceRqlSearch() *schema.Resource {
 	return &schema.Resource{
-		CreateContext: createUpdateRqlSearch,
+		CreateContext: createRqlSearch,
 		ReadContext:   readRqlSearch,
-		UpdateContext: createUpdateRqlSearch,
+		UpdateContext: updateRqlSearch,
 		DeleteContext: deleteRqlSearch,
+
+		Importer: &schema.ResourceImporter{
+			StateContext: importRqlSearch,
+		},
+
+		CustomizeDiff: customizeRqlSearchDiff,
+
 		Schema: map[string]*schema.Schema{
 			// Input.
 			"search_type": {
@@ -35,9 +45,10 @@ func resourceRqlSearch() *schema.Resource {
 				ForceNew: true,
 			},
 			"query": {
-				Type:        schema.TypeString,
-				Required:    true,
-				Description: "The RQL search to perform",
+				Type:         schema.TypeString,
+				Required:     true,
+				Description:  "The RQL search to perform",
+				ValidateFunc: validateRql,
 			},
 			"time_range": timeRangeSchema("resource_rql_search"),
 			"limit": {
@@ -487,7 +498,15 @@ func resourceRqlSearch() *schema.Resource {
 	}
 }
 
-func createUpdateRqlSearch(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+func createRqlSearch(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	return createUpdateRqlSearch(ctx, d, meta, d.Timeout(schema.TimeoutCreate))
+}
+
+func updateRqlSearch(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	return createUpdateRqlSearch(ctx, d, meta, d.Timeout(schema.TimeoutUpdate))
+}
+
+func createUpdateRqlSearch(ctx context.Context, d *schema.ResourceData, meta interface{}, timeout time.Duration) diag.Diagnostics {
 	client := meta.(*pc.Client)
 	query := d.Get("query").(string)
 	limit := d.Get("limit").(int)
@@ -516,7 +535,7 @@ func createUpdateRqlSearch(ctx context.Context, d *schema.ResourceData, meta int
 			return diag.FromErr(err)
 		}
 
-		PollApiUntilSuccess(func() error {
+		if diags := PollApiUntilSuccess(ctx, timeout, func() error {
 			r := search.ConfigRequest{
 				Id:              resp.Id,
 				Query:           query,
@@ -527,7 +546,9 @@ func createUpdateRqlSearch(ctx context.Context, d *schema.ResourceData, meta int
 			}
 			_, err := search.ConfigSearch(client, r)
 			return err
-		})
+		}); diags.HasError() {
+			return diags
+		}
 
 		id = buildRqlSearchId(searchType, query, resp.Id)
 	case "network":
@@ -543,7 +564,7 @@ func createUpdateRqlSearch(ctx context.Context, d *schema.ResourceData, meta int
 			return diag.FromErr(err)
 		}
 
-		PollApiUntilSuccess(func() error {
+		if diags := PollApiUntilSuccess(ctx, timeout, func() error {
 			r := search.NetworkRequest{
 				Id:         resp.Id,
 				Query:      query,
@@ -553,7 +574,9 @@ func createUpdateRqlSearch(ctx context.Context, d *schema.ResourceData, meta int
 			}
 			_, err := search.NetworkSearch(client, r)
 			return err
-		})
+		}); diags.HasError() {
+			return diags
+		}
 
 		id = buildRqlSearchId(searchType, query, resp.Id)
 	case "event":
@@ -570,7 +593,7 @@ func createUpdateRqlSearch(ctx context.Context, d *schema.ResourceData, meta int
 			return diag.FromErr(err)
 		}
 
-		PollApiUntilSuccess(func() error {
+		if diags := PollApiUntilSuccess(ctx, timeout, func() error {
 			r := search.EventRequest{
 				Id:              resp.Id,
 				Query:           query,
@@ -581,7 +604,9 @@ func createUpdateRqlSearch(ctx context.Context, d *schema.ResourceData, meta int
 			}
 			_, err := search.EventSearch(client, r)
 			return err
-		})
+		}); diags.HasError() {
+			return diags
+		}
 
 		id = buildRqlSearchId(searchType, query, resp.Id)
 	case "iam":
@@ -595,7 +620,7 @@ func createUpdateRqlSearch(ctx context.Context, d *schema.ResourceData, meta int
 			return diag.FromErr(err)
 		}
 
-		PollApiUntilSuccess(func() error {
+		if diags := PollApiUntilSuccess(ctx, timeout, func() error {
 			r := search.IamRequest{
 				Id:    resp.Id,
 				Query: query,
@@ -603,7 +628,9 @@ func createUpdateRqlSearch(ctx context.Context, d *schema.ResourceData, meta int
 			}
 			_, err := search.IamSearch(client, r)
 			return err
-		})
+		}); diags.HasError() {
+			return diags
+		}
 
 		id = buildRqlSearchId(searchType, query, resp.Id)
 	case "asset":
@@ -618,7 +645,7 @@ func createUpdateRqlSearch(ctx context.Context, d *schema.ResourceData, meta int
 			return diag.FromErr(err)
 		}
 
-		PollApiUntilSuccess(func() error {
+		if diags := PollApiUntilSuccess(ctx, timeout, func() error {
 			r := search.AssetRequest{
 				SavedSearchId: resp.ResultMetadata.SearchId,
 				Query:         query,
@@ -627,7 +654,9 @@ func createUpdateRqlSearch(ctx context.Context, d *schema.ResourceData, meta int
 			}
 			_, err := search.AssetSearch(client, r)
 			return err
-		})
+		}); diags.HasError() {
+			return diags
+		}
 
 		id = buildRqlSearchId(searchType, query, resp.ResultMetadata.SearchId)
 	}
@@ -923,6 +952,50 @@ func deleteRqlSearch(ctx context.Context, d *schema.ResourceData, meta interface
 	return nil
 }
 
+/*
+importRqlSearch imports a search from the resource ID, from the ID of a search
+in the search history, or from "name:<name>" of a saved search.
+
+Searches are read using the query and search type in state, so these are set
+from the search history when not given by the resource ID.
+*/
+func importRqlSearch(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	client := meta.(*pc.Client)
+
+	if t := Base64Decode(d.Id()); len(t) == 3 {
+		d.Set("search_type", t[0])
+		d.Set("query", t[1])
+		return []*schema.ResourceData{d}, nil
+	}
+
+	id := d.Id()
+	if strings.HasPrefix(id, importNamePrefix) {
+		name := strings.TrimPrefix(id, importNamePrefix)
+		listing, err := listSavedSearchImports(client)
+		if err != nil {
+			return nil, err
+		}
+		if id, err = resolveImportName("saved search", name, listing); err != nil {
+			return nil, err
+		}
+	}
+
+	o, err := history.Get(client, id)
+	if err != nil {
+		return nil, err
+	}
+	searchType := strings.ToLower(o.SearchType)
+	if searchType == "" {
+		searchType = "config"
+	}
+
+	d.SetId(buildRqlSearchId(searchType, o.Query, id))
+	d.Set("search_type", searchType)
+	d.Set("query", o.Query)
+
+	return []*schema.ResourceData{d}, nil
+}
+
 // Id functions.
 func buildRqlSearchId(a, b, c string) string {
 	res := Base64Encode([]interface{}{a, b, c})
@@ -16,16 +16,16 @@ func resourceSavedSearch() *schema.Resource {
 		UpdateContext: updateSavedSearch,
 		ReadContext:   readSavedSearch,
 		DeleteContext: deleteSavedSearch,
-		Importer: &schema.ResourceImporter{
-			StateContext: schema.ImportStatePassthroughContext,
-		},
+		Importer: importByName("saved search", listSavedSearchImports),
 
 		Schema: map[string]*schema.Schema{
 			// Input.
 			"query": {
-				Type:        schema.TypeString,
-				Required:    true,
-				Description: "The RQL search to perform",
+				Type:             schema.TypeString,
+				Required:         true,
+				Description:      "The RQL search to perform",
+				ValidateFunc:     validateRql,
+				DiffSuppressFunc: suppressEquivalentRql,
 			},
 			"search_id": {
 				Type:        schema.TypeString,
@@ -88,11 +88,13 @@ func createSavedSearch(ctx context.Context, d *schema.ResourceData, meta interfa
 	}
 
 	var resp1 history.Query
-	PollApiUntilSuccess(func() error {
+	if diags := PollApiUntilSuccess(ctx, d.Timeout(schema.TimeoutCreate), func() error {
 		resp2, err := history.Get(client, resp.Id)
 		resp1 = resp2
 		return err
-	})
+	}); diags.HasError() {
+		return diags
+	}
 
 	d.SetId(resp1.Id)
 
@@ -121,11 +123,13 @@ func updateSavedSearch(ctx context.Context, d *schema.ResourceData, meta interfa
 	}
 
 	var resp1 history.Query
-	PollApiUntilSuccess(func() error {
+	if diags := PollApiUntilSuccess(ctx, d.Timeout(schema.TimeoutUpdate), func() error {
 		resp2, err := history.Get(client, resp.Id)
 		resp1 = resp2
 		return err
-	})
+	}); diags.HasError() {
+		return diags
+	}
 
 	d.SetId(resp1.Id)
 
@@ -17,9 +17,7 @@ func resourceTrustedAlertIp() *schema.Resource {
 		UpdateContext: updateTrustedAlertIp,
 		DeleteContext: deleteTrustedAlertIp,
 
-		Importer: &schema.ResourceImporter{
-			StateContext: schema.ImportStatePassthroughContext,
-		},
+		Importer: importByName("trusted alert IP", listTrustedAlertIpImports),
 
 		Schema: map[string]*schema.Schema{
 			"uuid": {
@@ -125,20 +123,24 @@ func createTrustedAlertIp(ctx context.Context, d *schema.ResourceData, meta inte
 		}
 	}
 
-	PollApiUntilSuccess(func() error {
+	if diags := PollApiUntilSuccess(ctx, d.Timeout(schema.TimeoutCreate), func() error {
 		id2, err := trustedalertip.Identify(client, obj.Name)
 		id = id2
 		return err
-	})
+	}); diags.HasError() {
+		return diags
+	}
 	for _, o := range obj.CIDRS {
 		_, err := trustedalertip.CreateCIDR(client, o, id)
 		if err == pc.OverlappingCIDRError {
 			var resp trustedalertip.TrustedAlertIP
-			PollApiUntilSuccess(func() error {
+			if diags := PollApiUntilSuccess(ctx, d.Timeout(schema.TimeoutCreate), func() error {
 				resp1, err := trustedalertip.Get(client, id)
 				resp = resp1
 				return err
-			})
+			}); diags.HasError() {
+				return diags
+			}
 
 			d.SetId(resp.UUID)
 			saveTrustedAlertIp(d, resp)
@@ -146,11 +148,13 @@ func createTrustedAlertIp(ctx context.Context, d *schema.ResourceData, meta inte
 		}
 		if err != nil && err != pc.OverlappingCIDRError {
 			var resp trustedalertip.TrustedAlertIP
-			PollApiUntilSuccess(func() error {
+			if diags := PollApiUntilSuccess(ctx, d.Timeout(schema.TimeoutCreate), func() error {
 				resp1, err := trustedalertip.Get(client, id)
 				resp = resp1
 				return err
-			})
+			}); diags.HasError() {
+				return diags
+			}
 
 			d.SetId(resp.UUID)
 			saveTrustedAlertIp(d, resp)
@@ -159,11 +163,13 @@ func createTrustedAlertIp(ctx context.Context, d *schema.ResourceData, meta inte
 	}
 
 	var resp trustedalertip.TrustedAlertIP
-	PollApiUntilSuccess(func() error {
+	if diags := PollApiUntilSuccess(ctx, d.Timeout(schema.TimeoutCreate), func() error {
 		resp1, err := trustedalertip.Get(client, id)
 		resp = resp1
 		return err
-	})
+	}); diags.HasError() {
+		return diags
+	}
 
 	d.SetId(resp.UUID)
 	saveTrustedAlertIp(d, resp)
@@ -215,11 +221,13 @@ func updateTrustedAlertIp(ctx context.Context, d *schema.ResourceData, meta inte
 			return diag.FromErr(err)
 		}
 	}
-	PollApiUntilSuccess(func() error {
+	if diags := PollApiUntilSuccess(ctx, d.Timeout(schema.TimeoutUpdate), func() error {
 		id2, err := trustedalertip.Identify(client, obj.Name)
 		id = id2
 		return err
-	})
+	}); diags.HasError() {
+		return diags
+	}
 
 	listing, _ := trustedalertip.Get(client, id)
 	get_api_all_ips := listing.CIDRS
@@ -237,11 +245,13 @@ func updateTrustedAlertIp(ctx context.Context, d *schema.ResourceData, meta inte
 			if _, err := trustedalertip.UpdateCIDR(client, o, id, o.UUID); err != nil {
 				if "405" == strings.Split(err.Error(), " ")[0] {
 					var resp trustedalertip.TrustedAlertIP
-					PollApiUntilSuccess(func() error {
+					if diags := PollApiUntilSuccess(ctx, d.Timeout(schema.TimeoutUpdate), func() error {
 						resp1, err := trustedalertip.Get(client, id)
 						resp = resp1
 						return err
-					})
+					}); diags.HasError() {
+						return diags
+					}
 					d.SetId(resp.UUID)
 					saveTrustedAlertIp(d, resp)
 					return diag.FromErr(pc.OverlappingCIDRError)
@@ -251,11 +261,13 @@ func updateTrustedAlertIp(ctx context.Context, d *schema.ResourceData, meta inte
 		}
 		if err != nil && err != pc.OverlappingCIDRError {
 			var resp trustedalertip.TrustedAlertIP
-			PollApiUntilSuccess(func() error {
+			if diags := PollApiUntilSuccess(ctx, d.Timeout(schema.TimeoutUpdate), func() error {
 				resp1, err := trustedalertip.Get(client, id)
 				resp = resp1
 				return err
-			})
+			}); diags.HasError() {
+				return diags
+			}
 			d.SetId(resp.UUID)
 			saveTrustedAlertIp(d, resp)
 			return diag.FromErr(err)
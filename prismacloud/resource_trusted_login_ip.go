@@ -18,6 +18,8 @@ func resourceTrustedLoginIp() *schema.Resource {
 		UpdateContext: updateTrustedLoginIp,
 		DeleteContext: deleteTrustedLoginIp,
 
+		Importer: importByName("trusted login IP", listTrustedLoginIpImports),
+
 		Schema: map[string]*schema.Schema{
 			"trusted_login_ip_id": {
 				Type:        schema.TypeString,
@@ -59,20 +61,24 @@ func createTrustedLoginIp(ctx context.Context, d *schema.ResourceData, meta inte
 		return diag.FromErr(err)
 	}
 
-	PollApiUntilSuccess(func() error {
+	if diags := PollApiUntilSuccess(ctx, d.Timeout(schema.TimeoutCreate), func() error {
 		_, err := ip_address.Identify(client, obj.Name)
 		return err
-	})
+	}); diags.HasError() {
+		return diags
+	}
 
 	id, err := ip_address.Identify(client, obj.Name)
 	if err != nil {
 		return diag.FromErr(err)
 	}
 
-	PollApiUntilSuccess(func() error {
+	if diags := PollApiUntilSuccess(ctx, d.Timeout(schema.TimeoutCreate), func() error {
 		_, err := ip_address.Get(client, id)
 		return err
-	})
+	}); diags.HasError() {
+		return diags
+	}
 
 	d.SetId(id)
 	return readTrustedLoginIp(ctx, d, meta)
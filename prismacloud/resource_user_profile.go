@@ -262,10 +262,12 @@ func createUserProfile(ctx context.Context, d *schema.ResourceData, meta interfa
 	}
 	var accessKeyResponse profile.AccessKeyResponse
 	json.Unmarshal(keyResponse, &accessKeyResponse)
-	PollApiUntilSuccess(func() error {
+	if diags := PollApiUntilSuccess(ctx, d.Timeout(schema.TimeoutCreate), func() error {
 		_, err := profile.Get(client, id)
 		return err
-	})
+	}); diags.HasError() {
+		return diags
+	}
 
 	d.SetId(id)
 	d.Set("access_key_id", accessKeyResponse.AccessKeyId)
@@ -16,7 +16,7 @@ import (
 )
 
 func resourceV2CloudAccount() *schema.Resource {
-	return &schema.Resource{
+	r := &schema.Resource{
 		CreateContext: createV2CloudAccount,
 		ReadContext:   readV2CloudAccount,
 		UpdateContext: updateV2CloudAccount,
@@ -28,9 +28,9 @@ func resourceV2CloudAccount() *schema.Resource {
 			Delete: schema.DefaultTimeout(5 * time.Minute),
 		},
 
-		Importer: &schema.ResourceImporter{
-			StateContext: schema.ImportStatePassthroughContext,
-		},
+		Importer: importCloudAccountByName(listV2CloudAccountImports),
+
+		SchemaVersion: 1,
 
 		Schema: map[string]*schema.Schema{
 
@@ -860,6 +860,17 @@ func resourceV2CloudAccount() *schema.Resource {
 			},
 		},
 	}
+
+	// Version 0 has the same layout, see cloud_account_upgrade.go.
+	r.StateUpgraders = []schema.StateUpgrader{
+		{
+			Version: 0,
+			Type:    r.CoreConfigSchema().ImpliedType(),
+			Upgrade: upgradeV2CloudAccountV0,
+		},
+	}
+
+	return r
 }
 
 func gcpv2CredentialsMatch(k, old, new string, d *schema.ResourceData) bool {
@@ -1249,11 +1260,13 @@ func createV2CloudAccount(ctx context.Context, d *schema.ResourceData, meta inte
 	}
 
 	var resp1 interface{}
-	PollApiUntilSuccess(func() error {
+	if diags := PollApiUntilSuccess(ctx, d.Timeout(schema.TimeoutCreate), func() error {
 		resp, err := accountv2.Get(client, cloudType, accId)
 		resp1 = resp
 		return err
-	})
+	}); diags.HasError() {
+		return diags
+	}
 
 	d.SetId(TwoStringsToId(cloudType, accId))
 	saveV2CloudAccount(d, cloudType, resp1)
@@ -1316,11 +1329,13 @@ func updateV2CloudAccount(ctx context.Context, d *schema.ResourceData, meta inte
 	if err := accountv2.Update(client, obj); err != nil {
 		return diag.FromErr(err)
 	}
-	PollApiUntilSuccess(func() error {
+	if diags := PollApiUntilSuccess(ctx, d.Timeout(schema.TimeoutUpdate), func() error {
 		resp, err := accountv2.Get(client, cloudType, accId)
 		resp1 = resp
 		return err
-	})
+	}); diags.HasError() {
+		return diags
+	}
 
 	d.SetId(TwoStringsToId(cloudType, accId))
 	saveV2CloudAccount(d, cloudType, resp1)
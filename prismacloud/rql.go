@@ -0,0 +1,97 @@
+package prismacloud
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/terraform-providers/terraform-provider-prismacloud/internal/rql"
+	"golang.org/x/net/context"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// validateRql checks the syntax of an RQL query.
+func validateRql(i interface{}, k string) ([]string, []error) {
+	s, ok := i.(string)
+	if !ok {
+		return nil, []error{fmt.Errorf("expected type of %s to be string", k)}
+	}
+
+	if _, err := rql.Parse(s); err != nil {
+		return nil, []error{fmt.Errorf("invalid RQL in %s: %s", k, err)}
+	}
+
+	return nil, nil
+}
+
+// validateRqlCriteria checks the syntax of policy rule criteria if it is an
+// RQL query.  Criteria may also be a saved search ID or a JSON build rule,
+// which are left to the API.
+func validateRqlCriteria(i interface{}, k string) ([]string, []error) {
+	if s, ok := i.(string); ok && !rql.LooksLikeQuery(s) {
+		return nil, nil
+	}
+
+	return validateRql(i, k)
+}
+
+// rqlSearchType returns the search type of a query, or an empty string if
+// the query is not known yet or does not parse.
+func rqlSearchType(d *schema.ResourceDiff, key string) string {
+	if !d.NewValueKnown(key) {
+		return ""
+	}
+
+	s, _ := d.Get(key).(string)
+	if !rql.LooksLikeQuery(s) {
+		return ""
+	}
+
+	q, err := rql.Parse(s)
+	if err != nil {
+		return ""
+	}
+
+	return q.SearchType
+}
+
+// customizePolicyDiff checks that RQL criteria search the type of data that
+// the policy type alerts on.
+func customizePolicyDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if !d.NewValueKnown("policy_type") {
+		return nil
+	}
+	policyType := d.Get("policy_type").(string)
+
+	want := rql.SearchTypesFor(policyType)
+	if len(want) == 0 {
+		return nil
+	}
+
+	got := rqlSearchType(d, "rule.0.criteria")
+	if got == "" {
+		return nil
+	}
+	for _, st := range want {
+		if got == st {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("rule.0.criteria is a %s query, but policy_type %q needs a %s query", got, policyType, strings.Join(want, " or "))
+}
+
+// customizeRqlSearchDiff checks that the query is of the given search type.
+func customizeRqlSearchDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if !d.NewValueKnown("search_type") {
+		return nil
+	}
+	searchType := d.Get("search_type").(string)
+
+	got := rqlSearchType(d, "query")
+	if got == "" || got == searchType {
+		return nil
+	}
+
+	return fmt.Errorf("query is a %s query, but search_type is %q", got, searchType)
+}
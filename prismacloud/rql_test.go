@@ -0,0 +1,98 @@
+package prismacloud
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"golang.org/x/net/context"
+)
+
+func TestValidateRqlCriteria(t *testing.T) {
+	for _, tc := range []struct {
+		criteria string
+		ok       bool
+	}{
+		{"ba3ebbd7-4f8a-4b54-9b7c-e0b7a8e5b0a1", true},
+		{`{"or":[{"value":"aws_s3_bucket","operator":"exists"}]}`, true},
+		{"config from cloud.resource where api.name = 'aws-s3api-get-bucket-acl'", true},
+		{"config from cloud.resource where api.name = 'aws-s3api-get-bucket-acl' and", false},
+		{"confg from cloud.resource where api.name = 'aws-s3api-get-bucket-acl'", false},
+	} {
+		_, errs := validateRqlCriteria(tc.criteria, "rule.0.criteria")
+		if ok := len(errs) == 0; ok != tc.ok {
+			t.Errorf("%q: got errors %v", tc.criteria, errs)
+		}
+	}
+}
+
+func TestValidateRqlPosition(t *testing.T) {
+	_, errs := validateRql("config from cloud.resource where\n  api.name = 'a' or or", "query")
+	if len(errs) != 1 {
+		t.Fatalf("Got errors %v, expected 1", errs)
+	}
+	if got := errs[0].Error(); !strings.Contains(got, "line 2, column 21") {
+		t.Errorf("Error %q does not have the position", got)
+	}
+}
+
+func testRqlDiff(t *testing.T, typeName string, raw map[string]interface{}) error {
+	t.Helper()
+
+	r := Provider().ResourcesMap[typeName]
+	_, err := r.Diff(context.Background(), nil, terraform.NewResourceConfigRaw(raw), nil)
+	return err
+}
+
+func TestPolicyCriteriaSearchType(t *testing.T) {
+	for _, tc := range []struct {
+		policyType string
+		criteria   string
+		ok         bool
+	}{
+		{"config", "config from cloud.resource where api.name = 'a'", true},
+		{"config", "event from cloud.audit_logs where operation = 'a'", false},
+		{"audit_event", "event from cloud.audit_logs where operation = 'a'", true},
+		{"network", "network from vpc.flow_record where bytes > 0", true},
+		{"network", "config from network where source.network = '0.0.0.0/0'", true},
+		{"iam", "config from iam where source.cloud.service.name = 'ec2'", true},
+		{"iam", "config from cloud.resource where api.name = 'a'", false},
+		{"config", "ba3ebbd7-4f8a-4b54-9b7c-e0b7a8e5b0a1", true},
+		{"anomaly", "config from cloud.resource where api.name = 'a'", true},
+	} {
+		err := testRqlDiff(t, "prismacloud_policy", map[string]interface{}{
+			"name":        "test",
+			"policy_type": tc.policyType,
+			"rule": []interface{}{map[string]interface{}{
+				"name":     "test",
+				"criteria": tc.criteria,
+			}},
+		})
+		if ok := err == nil; ok != tc.ok {
+			t.Errorf("%s policy with %q: got error %v", tc.policyType, tc.criteria, err)
+		}
+	}
+}
+
+func TestRqlSearchSearchType(t *testing.T) {
+	for _, tc := range []struct {
+		searchType string
+		query      string
+		ok         bool
+	}{
+		{"", "config from cloud.resource where api.name = 'a'", true},
+		{"", "event from cloud.audit_logs where operation = 'a'", false},
+		{"event", "event from cloud.audit_logs where operation = 'a'", true},
+		{"iam", "config from iam where source.cloud.service.name = 'ec2'", true},
+		{"asset", "config from cloud.resource where api.name = 'a'", false},
+	} {
+		raw := map[string]interface{}{"query": tc.query}
+		if tc.searchType != "" {
+			raw["search_type"] = tc.searchType
+		}
+		err := testRqlDiff(t, "prismacloud_rql_search", raw)
+		if ok := err == nil; ok != tc.ok {
+			t.Errorf("%q search with %q: got error %v", tc.searchType, tc.query, err)
+		}
+	}
+}
@@ -127,7 +127,7 @@ func timeRangeSchema(style string) *schema.Schema {
 		model.Schema["to_now"].Computed = true
 		to_now_resource.Schema["unit"].Computed = true
 		to_now_resource.Schema["unit"].ValidateFunc = nil
-	case "resource_saved_search":
+	case "resource_saved_search", "resource_alert_dismissal":
 		ans.ForceNew = true
 		ans.MaxItems = 1
 
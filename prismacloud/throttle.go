@@ -0,0 +1,206 @@
+package prismacloud
+
+import (
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"golang.org/x/net/context"
+)
+
+// retryDelayUnit is the unit of the backoff delay between throttled retries.
+var retryDelayUnit = time.Second
+
+// rateLimiter is a token bucket shared by all requests made by the provider.
+type rateLimiter struct {
+	rate  float64
+	burst float64
+
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+	paused time.Time
+}
+
+func newRateLimiter(rate float64, burst int) *rateLimiter {
+	if burst < 1 {
+		burst = int(math.Max(1, math.Ceil(rate)))
+	}
+
+	return &rateLimiter{
+		rate:   rate,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+// Wait blocks until a token is available or the context is done.
+func (l *rateLimiter) Wait(ctx context.Context) error {
+	for {
+		delay := l.reserve()
+		if delay <= 0 {
+			return nil
+		}
+
+		t := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return ctx.Err()
+		case <-t.C:
+		}
+	}
+}
+
+// reserve takes a token if one is available, otherwise it returns how long to
+// wait before trying again.
+func (l *rateLimiter) reserve() time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	if now.Before(l.paused) {
+		return l.paused.Sub(now)
+	}
+
+	if l.rate > 0 {
+		l.tokens = math.Min(l.burst, l.tokens+now.Sub(l.last).Seconds()*l.rate)
+		l.last = now
+		if l.tokens < 1 {
+			return time.Duration((1 - l.tokens) / l.rate * float64(time.Second))
+		}
+		l.tokens--
+	}
+
+	return 0
+}
+
+// Pause holds back all requests until the given time.
+func (l *rateLimiter) Pause(until time.Time) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if until.After(l.paused) {
+		l.paused = until
+	}
+}
+
+/*
+throttledTransport governs the requests sent to Prisma Cloud.
+
+Requests wait on a token bucket shared by all resources and on a limit of
+concurrent requests in flight.  Requests answered with HTTP 429 are retried
+here, honoring any Retry-After header, with the retry budget tracked for each
+request instead of being shared by the whole run.
+
+The SDK sends requests without a context that Terraform could cancel, so
+neither an interrupt nor a resource timeout stops a request that is waiting
+here.  Instead, all the waiting and retrying for a request is bounded by the
+provider timeout.
+*/
+type throttledTransport struct {
+	next    http.RoundTripper
+	limiter *rateLimiter
+	slots   chan struct{}
+	timeout time.Duration
+
+	maxRetries    int
+	retryMaxDelay time.Duration
+	retryType     string
+}
+
+func (t *throttledTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	if t.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, t.timeout)
+		defer cancel()
+	}
+
+	for retries := 0; ; retries++ {
+		attempt := req
+		if retries > 0 {
+			attempt = req.Clone(req.Context())
+			if req.GetBody != nil {
+				body, err := req.GetBody()
+				if err != nil {
+					return nil, err
+				}
+				attempt.Body = body
+			}
+		}
+
+		resp, err := t.send(ctx, attempt)
+		if err != nil || resp.StatusCode != http.StatusTooManyRequests {
+			return resp, err
+		}
+
+		delay, ok := parseRetryAfter(resp.Header.Get("Retry-After"), time.Now())
+		if !ok {
+			delay = t.backoff(retries + 1)
+		}
+		if retries >= t.maxRetries || delay > t.retryMaxDelay {
+			log.Printf("[DEBUG] API received too many requests, retries exhausted for %s", req.URL.Path)
+			return resp, nil
+		}
+		resp.Body.Close()
+
+		log.Printf("[DEBUG] API received too many requests, retrying %s in %s", req.URL.Path, delay)
+		t.limiter.Pause(time.Now().Add(delay))
+	}
+}
+
+// send performs a single attempt, waiting for a token and a free slot first.
+func (t *throttledTransport) send(ctx context.Context, req *http.Request) (*http.Response, error) {
+	if err := t.limiter.Wait(ctx); err != nil {
+		return nil, err
+	}
+
+	if t.slots != nil {
+		select {
+		case t.slots <- struct{}{}:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		defer func() { <-t.slots }()
+	}
+
+	return t.next.RoundTrip(req)
+}
+
+func (t *throttledTransport) backoff(retries int) time.Duration {
+	switch t.retryType {
+	case "linear_backoff":
+		return time.Duration(1+retries) * retryDelayUnit
+	default:
+		return time.Duration(1<<uint(retries)) * retryDelayUnit
+	}
+}
+
+// parseRetryAfter parses a Retry-After header given in either seconds or as
+// an HTTP date.
+func parseRetryAfter(v string, now time.Time) (time.Duration, bool) {
+	if v == "" {
+		return 0, false
+	}
+
+	if secs, err := strconv.Atoi(v); err == nil {
+		if secs < 0 {
+			return 0, false
+		}
+		return time.Duration(secs) * time.Second, true
+	}
+
+	if t, err := http.ParseTime(v); err == nil {
+		if d := t.Sub(now); d > 0 {
+			return d, true
+		}
+		return 0, true
+	}
+
+	return 0, false
+}
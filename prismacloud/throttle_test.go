@@ -0,0 +1,211 @@
+package prismacloud
+
+import (
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	pc "github.com/paloaltonetworks/prisma-cloud-go"
+	"github.com/paloaltonetworks/prisma-cloud-go/cloud/account/group"
+	"github.com/terraform-providers/terraform-provider-prismacloud/internal/fakeapi"
+)
+
+func shortRetryDelays(t *testing.T) {
+	unit := retryDelayUnit
+	retryDelayUnit = time.Millisecond
+	t.Cleanup(func() {
+		retryDelayUnit = unit
+	})
+}
+
+// throttleEvery answers the first n calls to each path with HTTP 429.
+func throttleEvery(s *fakeapi.Server, n int, next fakeapi.HandlerFunc) fakeapi.HandlerFunc {
+	var mu sync.Mutex
+	seen := make(map[string]int)
+
+	return func(r *fakeapi.Request) (interface{}, error) {
+		mu.Lock()
+		seen[r.URL.Path]++
+		count := seen[r.URL.Path]
+		mu.Unlock()
+
+		if count <= n {
+			return nil, fakeapi.Errorf(http.StatusTooManyRequests, "too_many_requests", r.URL.Path)
+		}
+		return next(r)
+	}
+}
+
+func TestThrottledRetriesArePerRequest(t *testing.T) {
+	shortRetryDelays(t)
+	s, client := testFakeClient(t, map[string]interface{}{"max_retries": 2})
+
+	a := s.AccountGroups.Put(map[string]interface{}{"name": "a"})
+	b := s.AccountGroups.Put(map[string]interface{}{"name": "b"})
+	s.Handle("GET", "/cloud/group/{id}", throttleEvery(s, 2, s.AccountGroups.GetHandler()))
+
+	// Each request may use its own two retries.
+	for _, id := range []string{a, b} {
+		if _, err := group.Get(client, id); err != nil {
+			t.Fatalf("Error getting %s: %s", id, err)
+		}
+	}
+	if n := s.Count("GET", "/cloud/group/"+a); n != 3 {
+		t.Fatalf("Group %s requested %d times, expected 3", a, n)
+	}
+}
+
+func TestThrottledRetriesExhausted(t *testing.T) {
+	shortRetryDelays(t)
+	s, client := testFakeClient(t, map[string]interface{}{"max_retries": 1})
+
+	id := s.AccountGroups.Put(map[string]interface{}{"name": "a"})
+	s.Handle("GET", "/cloud/group/{id}", throttleEvery(s, 5, s.AccountGroups.GetHandler()))
+
+	if _, err := group.Get(client, id); err == nil {
+		t.Fatalf("Expected an error")
+	} else if !isThrottledError(err) {
+		t.Fatalf("Error %q is not a throttling error", err)
+	}
+	if n := s.Count("GET", "/cloud/group/"+id); n != 2 {
+		t.Fatalf("Group requested %d times, expected 2", n)
+	}
+}
+
+func TestThrottledRetryAfter(t *testing.T) {
+	s, client := testFakeClient(t, nil)
+
+	id := s.AccountGroups.Put(map[string]interface{}{"name": "a"})
+	var once int32
+	s.Handle("GET", "/cloud/group/{id}", func(r *fakeapi.Request) (interface{}, error) {
+		if atomic.AddInt32(&once, 1) == 1 {
+			e := fakeapi.Errorf(http.StatusTooManyRequests, "too_many_requests", r.URL.Path)
+			e.Header = http.Header{"Retry-After": []string{"1"}}
+			return nil, e
+		}
+		return s.AccountGroups.GetHandler()(r)
+	})
+
+	start := time.Now()
+	if _, err := group.Get(client, id); err != nil {
+		t.Fatalf("Error in get: %s", err)
+	}
+	if elapsed := time.Since(start); elapsed < time.Second {
+		t.Fatalf("Retried after %s, expected Retry-After of 1s to be honored", elapsed)
+	}
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestThrottledWaitTimeout(t *testing.T) {
+	var calls int32
+	throttle := &throttledTransport{
+		next: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			atomic.AddInt32(&calls, 1)
+			return &http.Response{
+				StatusCode: http.StatusTooManyRequests,
+				Header:     http.Header{"Retry-After": []string{"5"}},
+				Body:       http.NoBody,
+			}, nil
+		}),
+		limiter:       newRateLimiter(0, 0),
+		timeout:       100 * time.Millisecond,
+		maxRetries:    3,
+		retryMaxDelay: time.Minute,
+	}
+
+	// The SDK's requests carry no context, only the timeout ends the wait.
+	req, err := http.NewRequest("GET", "http://localhost/cloud/group", nil)
+	if err != nil {
+		t.Fatalf("Error creating request: %s", err)
+	}
+	start := time.Now()
+	if _, err = throttle.RoundTrip(req); err == nil {
+		t.Fatalf("Expected an error")
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Fatalf("Waited %s, expected the 100ms timeout to end the wait", elapsed)
+	}
+	if calls != 1 {
+		t.Fatalf("Sent %d requests, expected 1", calls)
+	}
+}
+
+func TestThrottledRequestsPerSecond(t *testing.T) {
+	s, client := testFakeClient(t, map[string]interface{}{
+		"max_requests_per_second": 50.0,
+		"burst":                   1,
+	})
+
+	start := time.Now()
+	for i := 0; i < 6; i++ {
+		if _, err := group.List(client); err != nil {
+			t.Fatalf("Error in list: %s", err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 90*time.Millisecond {
+		t.Fatalf("6 requests took %s, expected at least 100ms at 50 requests per second", elapsed)
+	}
+	if n := s.Count("GET", "/cloud/group"); n != 6 {
+		t.Fatalf("Listed groups %d times, expected 6", n)
+	}
+}
+
+func TestThrottledConcurrentRequests(t *testing.T) {
+	s, client := testFakeClient(t, map[string]interface{}{"max_concurrent_requests": 2})
+
+	var inFlight, peak int32
+	s.Handle("GET", "/cloud/group", func(r *fakeapi.Request) (interface{}, error) {
+		n := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+		for {
+			p := atomic.LoadInt32(&peak)
+			if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+		return []interface{}{}, nil
+	})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 6; i++ {
+		wg.Add(1)
+		go func(c *pc.Client) {
+			defer wg.Done()
+			group.List(c)
+		}(client)
+	}
+	wg.Wait()
+
+	if peak > 2 {
+		t.Fatalf("%d requests were in flight, expected at most 2", peak)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	for _, tc := range []struct {
+		v    string
+		want time.Duration
+		ok   bool
+	}{
+		{"", 0, false},
+		{"3", 3 * time.Second, true},
+		{"-1", 0, false},
+		{"Mon, 01 Jan 2024 00:00:05 GMT", 5 * time.Second, true},
+		{"soon", 0, false},
+	} {
+		got, ok := parseRetryAfter(tc.v, now)
+		if got != tc.want || ok != tc.ok {
+			t.Errorf("parseRetryAfter(%q) = %s, %t; expected %s, %t", tc.v, got, ok, tc.want, tc.ok)
+		}
+	}
+}
@@ -0,0 +1,253 @@
+package prismacloud
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/net/context"
+)
+
+// tokenRenewWindow is how long before its expiry a JSON web token is renewed.
+var tokenRenewWindow = time.Minute
+
+const (
+	authHeader = "x-redlock-auth"
+	loginPath  = "/login"
+	extendPath = "/auth_token/extend"
+)
+
+/*
+tokenTransport keeps the JSON web token used for all requests fresh.
+
+The SDK client only logs in again after a request is rejected with HTTP 401,
+and concurrent requests that are rejected at the same time each log in on
+their own.  Instead, the token is renewed here shortly before the expiry in
+its "exp" claim, by extending it or by repeating the last login, and requests
+that are still rejected are retried once with a renewed token.  Only one
+request renews the token at a time, the others wait for it and then use the
+new token.
+*/
+type tokenTransport struct {
+	next http.RoundTripper
+
+	mu      sync.Mutex
+	token   string
+	expires time.Time
+	login   []byte
+}
+
+func (t *tokenTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	switch strings.TrimRight(req.URL.Path, "/") {
+	case loginPath, extendPath:
+		return t.authenticate(req)
+	}
+
+	token := t.current(req)
+	resp, err := t.send(req, token, false)
+	if err != nil || resp.StatusCode != http.StatusUnauthorized || token == "" {
+		return resp, err
+	}
+	if req.Body != nil && req.GetBody == nil {
+		return resp, nil
+	}
+
+	renewed, err := t.renew(req, token)
+	if err != nil {
+		log.Printf("[DEBUG] Unable to renew JSON web token: %s", err)
+		return resp, nil
+	}
+	resp.Body.Close()
+
+	return t.send(req, renewed, true)
+}
+
+// authenticate passes a login or token extension made by the client through,
+// saving the token it returns.
+func (t *tokenTransport) authenticate(req *http.Request) (*http.Response, error) {
+	var login []byte
+	if req.Method == http.MethodPost && req.Body != nil {
+		b, err := ioutil.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		req.Body = ioutil.NopCloser(bytes.NewReader(b))
+		login = b
+	}
+
+	resp, err := t.next.RoundTrip(req)
+	if err != nil || resp.StatusCode != http.StatusOK {
+		return resp, err
+	}
+
+	b, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	resp.Body = ioutil.NopCloser(bytes.NewReader(b))
+	if err != nil {
+		return resp, err
+	}
+
+	var ans struct {
+		Token string `json:"token"`
+	}
+	if json.Unmarshal(b, &ans) == nil && ans.Token != "" {
+		t.mu.Lock()
+		t.setToken(ans.Token)
+		if login != nil {
+			t.login = login
+		}
+		t.mu.Unlock()
+	}
+
+	return resp, nil
+}
+
+// current returns the token to use for the given request, renewing it first
+// if it is about to expire.
+func (t *tokenTransport) current(req *http.Request) string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.token == "" {
+		t.setToken(req.Header.Get(authHeader))
+	}
+
+	if t.token != "" && !t.expires.IsZero() && time.Now().Add(tokenRenewWindow).After(t.expires) {
+		if err := t.renewLocked(req, false); err != nil {
+			// Leave it to the server to decide if the token is still good,
+			// rather than trying again for every request.
+			log.Printf("[DEBUG] Unable to renew JSON web token before it expires: %s", err)
+			t.expires = time.Time{}
+		}
+	}
+
+	return t.token
+}
+
+// renew renews a token that has been rejected, unless another request has
+// already done so.
+func (t *tokenTransport) renew(req *http.Request, rejected string) (string, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.token != rejected {
+		return t.token, nil
+	}
+	if err := t.renewLocked(req, true); err != nil {
+		return "", err
+	}
+
+	return t.token, nil
+}
+
+func (t *tokenTransport) renewLocked(req *http.Request, rejected bool) error {
+	if !rejected || t.login == nil {
+		log.Printf("[DEBUG] Extending JSON web token")
+		err := t.fetch(req, http.MethodGet, extendPath, nil)
+		if err == nil || t.login == nil {
+			return err
+		}
+	}
+
+	log.Printf("[DEBUG] Logging in for a new JSON web token")
+	return t.fetch(req, http.MethodPost, loginPath, t.login)
+}
+
+// fetch requests a new token from the given path on the same host as req.
+func (t *tokenTransport) fetch(req *http.Request, method, path string, body []byte) error {
+	u := *req.URL
+	u.Path = path
+	u.RawPath = ""
+	u.RawQuery = ""
+
+	ctx := req.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	r, err := http.NewRequestWithContext(ctx, method, u.String(), bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	r.Header.Set("Content-Type", "application/json")
+	if method != http.MethodPost {
+		r.Header.Set(authHeader, t.token)
+	}
+
+	resp, err := t.next.RoundTrip(r)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s returned %d", path, resp.StatusCode)
+	}
+
+	var ans struct {
+		Token string `json:"token"`
+	}
+	if err = json.NewDecoder(resp.Body).Decode(&ans); err != nil {
+		return err
+	}
+	if ans.Token == "" {
+		return fmt.Errorf("%s did not return a token", path)
+	}
+	t.setToken(ans.Token)
+
+	return nil
+}
+
+func (t *tokenTransport) send(req *http.Request, token string, retry bool) (*http.Response, error) {
+	if token == "" || (!retry && req.Header.Get(authHeader) == token) {
+		return t.next.RoundTrip(req)
+	}
+
+	r := req.Clone(req.Context())
+	if retry && req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		r.Body = body
+	}
+	r.Header.Set(authHeader, token)
+
+	return t.next.RoundTrip(r)
+}
+
+// setToken saves the token along with the expiry in its "exp" claim, if any.
+func (t *tokenTransport) setToken(token string) {
+	t.token = token
+	t.expires = tokenExpiry(token)
+}
+
+// tokenExpiry returns the time in the "exp" claim of a JSON web token, or the
+// zero time if it cannot be decoded.
+func tokenExpiry(token string) time.Time {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return time.Time{}
+	}
+
+	b, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
+	if err != nil {
+		return time.Time{}
+	}
+
+	var claims struct {
+		Exp float64 `json:"exp"`
+	}
+	if err = json.Unmarshal(b, &claims); err != nil || claims.Exp <= 0 {
+		return time.Time{}
+	}
+
+	return time.Unix(int64(claims.Exp), 0)
+}
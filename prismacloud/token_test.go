@@ -0,0 +1,90 @@
+package prismacloud
+
+import (
+	"encoding/base64"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/paloaltonetworks/prisma-cloud-go/cloud/account/group"
+)
+
+func TestTokenRenewedBeforeExpiry(t *testing.T) {
+	s, client := testFakeClient(t, nil)
+
+	window := tokenRenewWindow
+	tokenRenewWindow = s.TokenLifetime + time.Minute
+	t.Cleanup(func() {
+		tokenRenewWindow = window
+	})
+
+	if _, err := group.List(client); err != nil {
+		t.Fatalf("Error in list: %s", err)
+	}
+	if n := s.Count("GET", "/auth_token/extend"); n != 1 {
+		t.Fatalf("Extended token %d times, expected 1", n)
+	}
+	if n := s.Count("POST", "/login"); n != 1 {
+		t.Fatalf("Expected 1 login, got %d", n)
+	}
+
+	tokenRenewWindow = window
+	if _, err := group.List(client); err != nil {
+		t.Fatalf("Error in list: %s", err)
+	}
+	if n := s.Count("GET", "/auth_token/extend"); n != 1 {
+		t.Fatalf("Extended token %d times, expected 1", n)
+	}
+}
+
+func TestTokenConcurrentRenewal(t *testing.T) {
+	s, client := testFakeClient(t, nil)
+	s.ExpireTokens()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := group.List(client); err != nil {
+				t.Errorf("Error in list: %s", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if n := s.Count("POST", "/login"); n != 2 {
+		t.Fatalf("Expected 2 logins, got %d", n)
+	}
+}
+
+func TestTokenDisableReconnect(t *testing.T) {
+	s, client := testFakeClient(t, map[string]interface{}{"disable_reconnect": true})
+	s.ExpireTokens()
+
+	if _, err := group.List(client); err == nil {
+		t.Fatalf("Expected an error with an expired token")
+	}
+	if n := s.Count("POST", "/login"); n != 1 {
+		t.Fatalf("Expected 1 login, got %d", n)
+	}
+}
+
+func TestTokenExpiry(t *testing.T) {
+	enc := base64.RawURLEncoding
+	header := enc.EncodeToString([]byte(`{"alg":"none"}`))
+
+	for _, tc := range []struct {
+		token string
+		want  time.Time
+	}{
+		{header + "." + enc.EncodeToString([]byte(`{"exp":1700000000}`)) + ".sig", time.Unix(1700000000, 0)},
+		{header + "." + enc.EncodeToString([]byte(`{"sub":"x"}`)) + ".sig", time.Time{}},
+		{header + ".!!!.sig", time.Time{}},
+		{"opaque", time.Time{}},
+	} {
+		if got := tokenExpiry(tc.token); !got.Equal(tc.want) {
+			t.Errorf("tokenExpiry(%q) = %s, expected %s", tc.token, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,467 @@
+package prismacloud
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	pc "github.com/paloaltonetworks/prisma-cloud-go"
+)
+
+// logTraceFile is the logging option naming the trace file.
+const logTraceFile = "trace_file"
+
+// Headers carrying the IDs Palo Alto support asks for.
+const (
+	requestIdHeader   = "X-Redlock-Request-Id"
+	traceIdHeader     = "Trace-Id"
+	terraformIdHeader = "terraform-request-identifier"
+)
+
+// traceSensitiveKeys are the JSON keys scrubbed from traced bodies, which
+// are the client's sensitive keys, the JSON web token returned on login and
+// the secrets of cloud accounts and integrations.  Keys are matched
+// regardless of case.
+var traceSensitiveKeys = append(append([]string(nil), pc.SensitiveKeys...),
+	"token",
+	"externalId",
+	"secretKey",
+	"privateKey",
+	"apiKey",
+	"apiToken",
+	"authToken",
+	"integrationKey",
+)
+
+// traceSensitiveSiblingKeys are JSON keys that are only scrubbed from
+// objects that also have the given key.  The Azure client secret is sent as
+// "key" next to its "clientId", while other objects, such as tags, use "key"
+// for data that is not secret.
+var traceSensitiveSiblingKeys = map[string]string{
+	"key": "clientId",
+}
+
+// traceMask replaces the value of a sensitive key.
+const traceMask = "********"
+
+// isTraceSensitiveKey returns if the value of the given JSON key is masked
+// in the given object.
+func isTraceSensitiveKey(obj map[string]interface{}, key string) bool {
+	for _, val := range traceSensitiveKeys {
+		if strings.EqualFold(key, val) {
+			return true
+		}
+	}
+
+	for val, sibling := range traceSensitiveSiblingKeys {
+		if strings.EqualFold(key, val) {
+			if _, ok := obj[sibling]; ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// scrubSensitiveValue masks the values of sensitive keys anywhere in a
+// decoded JSON value.
+func scrubSensitiveValue(v interface{}) interface{} {
+	switch x := v.(type) {
+	case map[string]interface{}:
+		for key, val := range x {
+			switch val.(type) {
+			case map[string]interface{}, []interface{}, nil:
+				x[key] = scrubSensitiveValue(val)
+			default:
+				if isTraceSensitiveKey(x, key) {
+					x[key] = traceMask
+				}
+			}
+		}
+	case []interface{}:
+		for i := range x {
+			x[i] = scrubSensitiveValue(x[i])
+		}
+	}
+	return v
+}
+
+/*
+scrubSensitiveData masks the values of sensitive keys in a body before it
+is traced.
+
+JSON bodies are decoded and walked, so keys are found at any depth and the
+whole value is masked even if it contains escaped quotes.  Bodies that are
+not JSON fall back to matching the quoted values of the keys.
+*/
+func scrubSensitiveData(b []byte) string {
+	var v interface{}
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	if err := dec.Decode(&v); err == nil && !dec.More() {
+		var buf bytes.Buffer
+		enc := json.NewEncoder(&buf)
+		enc.SetEscapeHTML(false)
+		if err = enc.Encode(scrubSensitiveValue(v)); err == nil {
+			return strings.TrimSuffix(buf.String(), "\n")
+		}
+	}
+
+	s := string(b)
+	for _, val := range traceSensitiveKeys {
+		pat := regexp.MustCompile(`(?i)("` + regexp.QuoteMeta(val) + `":\s*)"(?:[^"\\]|\\.)*"`)
+		s = pat.ReplaceAllString(s, `${1}"`+traceMask+`"`)
+	}
+
+	return s
+}
+
+// traceEntry is a single request and response, as written to a JSONL trace.
+type traceEntry struct {
+	Started      time.Time   `json:"started"`
+	Method       string      `json:"method"`
+	Url          string      `json:"url"`
+	Path         string      `json:"path"`
+	Query        string      `json:"query,omitempty"`
+	Status       int         `json:"status"`
+	Error        string      `json:"error,omitempty"`
+	Timings      traceTiming `json:"timings"`
+	RequestId    string      `json:"request_id,omitempty"`
+	TraceId      string      `json:"trace_id,omitempty"`
+	TerraformId  string      `json:"terraform_request_identifier,omitempty"`
+	RequestBody  string      `json:"request_body,omitempty"`
+	ResponseBody string      `json:"response_body,omitempty"`
+
+	reqHeader  http.Header
+	respHeader http.Header
+}
+
+// traceTiming holds durations in milliseconds.
+type traceTiming struct {
+	Wait    float64 `json:"wait"`
+	Receive float64 `json:"receive"`
+	Total   float64 `json:"total"`
+}
+
+// traceWriter writes trace entries to a file.
+type traceWriter interface {
+	Write(e *traceEntry) error
+}
+
+// traceWriters are the open trace files by absolute path.  Providers that
+// are configured with the same file, such as aliased providers, share its
+// writer, as two writers would overwrite each other's HAR entries.
+var (
+	traceWritersMu sync.Mutex
+	traceWriters   = make(map[string]traceWriter)
+)
+
+/*
+newTraceWriter opens a trace file for appending, or returns the writer of a
+file that is already open.
+
+Files ending in ".har" are written as an HTTP archive, which browsers and
+most HTTP tools can load, and any other file is written as JSON lines.  The
+file is kept open for the life of the provider process.
+*/
+func newTraceWriter(filename string) (traceWriter, error) {
+	path, err := filepath.Abs(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	traceWritersMu.Lock()
+	defer traceWritersMu.Unlock()
+
+	if w, ok := traceWriters[path]; ok {
+		return w, nil
+	}
+
+	var w traceWriter
+	if strings.EqualFold(filepath.Ext(path), ".har") {
+		w, err = newHarWriter(path)
+	} else {
+		var f *os.File
+		if f, err = os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600); err == nil {
+			w = &jsonlWriter{f: f}
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	traceWriters[path] = w
+	return w, nil
+}
+
+type jsonlWriter struct {
+	mu sync.Mutex
+	f  *os.File
+}
+
+func (w *jsonlWriter) Write(e *traceEntry) error {
+	b, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	_, err = w.f.Write(append(b, '\n'))
+	return err
+}
+
+const (
+	harHeader  = `{"log":{"version":"1.2","creator":{"name":"terraform-provider-prismacloud","version":""},"entries":[`
+	harTrailer = "\n]}}\n"
+)
+
+/*
+harWriter writes an HTTP archive one entry at a time.
+
+The file is kept valid after every entry by writing each new entry over the
+closing brackets and then writing them again, so a trace can be read while
+the provider is still running, and later runs add to the same archive.
+*/
+type harWriter struct {
+	mu    sync.Mutex
+	f     *os.File
+	size  int64
+	empty bool
+}
+
+func newHarWriter(filename string) (*harWriter, error) {
+	f, err := os.OpenFile(filename, os.O_CREATE|os.O_RDWR, 0600)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	w := &harWriter{f: f, size: info.Size(), empty: true}
+	if w.size == 0 {
+		if _, err = f.WriteString(harHeader + harTrailer); err != nil {
+			f.Close()
+			return nil, err
+		}
+		w.size = int64(len(harHeader) + len(harTrailer))
+		return w, nil
+	}
+
+	tail := make([]byte, len(harTrailer)+1)
+	if w.size < int64(len(harHeader)+len(harTrailer)) {
+		f.Close()
+		return nil, fmt.Errorf("%s is not a trace file written by this provider", filename)
+	}
+	if _, err = f.ReadAt(tail, w.size-int64(len(tail))); err != nil {
+		f.Close()
+		return nil, err
+	}
+	if string(tail[1:]) != harTrailer {
+		f.Close()
+		return nil, fmt.Errorf("%s is not a trace file written by this provider", filename)
+	}
+	w.empty = tail[0] == '['
+
+	return w, nil
+}
+
+func (w *harWriter) Write(e *traceEntry) error {
+	b, err := json.Marshal(e.har())
+	if err != nil {
+		return err
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	var buf bytes.Buffer
+	if !w.empty {
+		buf.WriteString(",")
+	}
+	buf.WriteString("\n")
+	buf.Write(b)
+	buf.WriteString(harTrailer)
+
+	if _, err = w.f.WriteAt(buf.Bytes(), w.size-int64(len(harTrailer))); err != nil {
+		return err
+	}
+	w.size += int64(buf.Len() - len(harTrailer))
+	w.empty = false
+
+	return nil
+}
+
+type harNameValue struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// har returns the entry in the HTTP archive 1.2 format.  The request IDs are
+// kept as custom fields as well as in the headers, so they are easy to find.
+func (e *traceEntry) har() interface{} {
+	headers := func(h http.Header) []harNameValue {
+		ans := make([]harNameValue, 0, len(h))
+		for key, vals := range h {
+			for _, v := range vals {
+				if strings.EqualFold(key, authHeader) {
+					v = "********"
+				}
+				ans = append(ans, harNameValue{Name: key, Value: v})
+			}
+		}
+		return ans
+	}
+
+	query := make([]harNameValue, 0)
+	if e.Query != "" {
+		for _, kv := range strings.Split(e.Query, "&") {
+			parts := strings.SplitN(kv, "=", 2)
+			nv := harNameValue{Name: parts[0]}
+			if len(parts) == 2 {
+				nv.Value = parts[1]
+			}
+			query = append(query, nv)
+		}
+	}
+
+	req := map[string]interface{}{
+		"method":      e.Method,
+		"url":         e.Url,
+		"httpVersion": "HTTP/1.1",
+		"cookies":     []interface{}{},
+		"headers":     headers(e.reqHeader),
+		"queryString": query,
+		"headersSize": -1,
+		"bodySize":    len(e.RequestBody),
+	}
+	if e.RequestBody != "" {
+		req["postData"] = map[string]interface{}{
+			"mimeType": "application/json",
+			"text":     e.RequestBody,
+		}
+	}
+
+	return map[string]interface{}{
+		"startedDateTime": e.Started.Format(time.RFC3339Nano),
+		"time":            e.Timings.Total,
+		"request":         req,
+		"response": map[string]interface{}{
+			"status":      e.Status,
+			"statusText":  http.StatusText(e.Status),
+			"httpVersion": "HTTP/1.1",
+			"cookies":     []interface{}{},
+			"headers":     headers(e.respHeader),
+			"content": map[string]interface{}{
+				"size":     len(e.ResponseBody),
+				"mimeType": "application/json",
+				"text":     e.ResponseBody,
+			},
+			"redirectURL": "",
+			"headersSize": -1,
+			"bodySize":    len(e.ResponseBody),
+		},
+		"cache": map[string]interface{}{},
+		"timings": map[string]interface{}{
+			"send":    0,
+			"wait":    e.Timings.Wait,
+			"receive": e.Timings.Receive,
+		},
+		"_requestId":                  e.RequestId,
+		"_traceId":                    e.TraceId,
+		"_terraformRequestIdentifier": e.TerraformId,
+		"_error":                      e.Error,
+	}
+}
+
+// tracingTransport records every request sent to Prisma Cloud.
+type tracingTransport struct {
+	next http.RoundTripper
+	out  traceWriter
+}
+
+func (t *tracingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	e := &traceEntry{
+		Started:     time.Now(),
+		Method:      req.Method,
+		Url:         req.URL.Scheme + "://" + req.URL.Host + req.URL.Path,
+		Path:        req.URL.Path,
+		Query:       req.URL.RawQuery,
+		TerraformId: req.Header.Get(terraformIdHeader),
+		reqHeader:   req.Header.Clone(),
+	}
+
+	if req.Body != nil && req.GetBody != nil {
+		if body, err := req.GetBody(); err == nil {
+			b, _ := ioutil.ReadAll(body)
+			body.Close()
+			e.RequestBody = scrubSensitiveData(b)
+		}
+	}
+
+	resp, err := t.next.RoundTrip(req)
+	e.Timings.Wait = sinceMillis(e.Started)
+	if err != nil {
+		e.Error = err.Error()
+		e.Timings.Total = e.Timings.Wait
+		t.write(e)
+		return resp, err
+	}
+
+	received := time.Now()
+	b, rerr := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	resp.Body = ioutil.NopCloser(io.MultiReader(bytes.NewReader(b), errReader{rerr}))
+
+	e.Timings.Receive = sinceMillis(received)
+	e.Timings.Total = sinceMillis(e.Started)
+	e.Status = resp.StatusCode
+	e.RequestId = resp.Header.Get(requestIdHeader)
+	e.TraceId = resp.Header.Get(traceIdHeader)
+	e.ResponseBody = scrubSensitiveData(b)
+	e.respHeader = resp.Header.Clone()
+	if rerr != nil {
+		e.Error = rerr.Error()
+	}
+	t.write(e)
+
+	return resp, nil
+}
+
+func (t *tracingTransport) write(e *traceEntry) {
+	if err := t.out.Write(e); err != nil {
+		// Tracing is a debugging aid, so it should never fail a request.
+		log.Printf("[WARN] Unable to write to trace file: %s", err)
+	}
+}
+
+func sinceMillis(t time.Time) float64 {
+	return float64(time.Since(t)) / float64(time.Millisecond)
+}
+
+// errReader returns the given error once the body has been read, or io.EOF.
+type errReader struct {
+	err error
+}
+
+func (r errReader) Read(p []byte) (int, error) {
+	if r.err != nil {
+		return 0, r.err
+	}
+	return 0, io.EOF
+}
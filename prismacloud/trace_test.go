@@ -0,0 +1,169 @@
+package prismacloud
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	pc "github.com/paloaltonetworks/prisma-cloud-go"
+	"github.com/paloaltonetworks/prisma-cloud-go/cloud/account/group"
+)
+
+func TestScrubSensitiveData(t *testing.T) {
+	in := `{"username":"key","password":"secret","token": "jwt","nested":{"private_key":"pem"}}`
+	got := scrubSensitiveData([]byte(in))
+
+	for _, secret := range []string{"secret", "jwt", "pem"} {
+		if strings.Contains(got, secret) {
+			t.Errorf("Scrubbed data %s still contains %q", got, secret)
+		}
+	}
+	if !strings.Contains(got, `"username":"key"`) {
+		t.Errorf("Scrubbed data %s is missing the username", got)
+	}
+}
+
+func TestTraceFileJsonl(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "trace.jsonl")
+	s, client := testFakeClient(t, map[string]interface{}{"logging": map[string]interface{}{pc.LogQuiet: "true", logTraceFile: filename}})
+
+	if _, err := group.List(client); err != nil {
+		t.Fatalf("Error in list: %s", err)
+	}
+
+	f, err := os.Open(filename)
+	if err != nil {
+		t.Fatalf("Error opening trace: %s", err)
+	}
+	defer f.Close()
+
+	var entries []traceEntry
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var e traceEntry
+		if err = json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			t.Fatalf("Invalid trace line %q: %s", scanner.Text(), err)
+		}
+		entries = append(entries, e)
+	}
+	if len(entries) != len(s.Calls()) {
+		t.Fatalf("Traced %d requests, expected %d", len(entries), len(s.Calls()))
+	}
+
+	login := entries[0]
+	if login.Method != "POST" || login.Path != "/login" || login.Status != 200 {
+		t.Errorf("Unexpected login entry: %#v", login)
+	}
+	for _, secret := range []string{s.Password, `"token":"ey`} {
+		if strings.Contains(login.RequestBody+login.ResponseBody, secret) {
+			t.Errorf("Login entry contains %q", secret)
+		}
+	}
+
+	list := entries[len(entries)-1]
+	if list.Path != "/cloud/group" || list.RequestId == "" || !strings.HasPrefix(list.TerraformId, "PrismaCloud-terraform-") {
+		t.Errorf("Unexpected list entry: %#v", list)
+	}
+}
+
+func TestTraceFileHar(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "trace.har")
+
+	// Aliased providers write to the same archive.
+	config := map[string]interface{}{"logging": map[string]interface{}{pc.LogQuiet: "true", logTraceFile: filename}}
+	_, first := testFakeClient(t, config)
+	_, second := testFakeClient(t, config)
+	for _, client := range []*pc.Client{first, second} {
+		if _, err := group.List(client); err != nil {
+			t.Fatalf("Error in list: %s", err)
+		}
+	}
+
+	b, err := os.ReadFile(filename)
+	if err != nil {
+		t.Fatalf("Error reading trace: %s", err)
+	}
+	var har struct {
+		Log struct {
+			Entries []struct {
+				Request struct {
+					Method  string `json:"method"`
+					Headers []struct {
+						Name  string `json:"name"`
+						Value string `json:"value"`
+					} `json:"headers"`
+				} `json:"request"`
+				Response struct {
+					Status int `json:"status"`
+				} `json:"response"`
+				RequestId string `json:"_requestId"`
+			} `json:"entries"`
+		} `json:"log"`
+	}
+	if err = json.Unmarshal(b, &har); err != nil {
+		t.Fatalf("Invalid HAR file: %s\n%s", err, b)
+	}
+	if n := len(har.Log.Entries); n != 4 {
+		t.Fatalf("Traced %d requests, expected 4", n)
+	}
+
+	for _, e := range har.Log.Entries {
+		if e.RequestId == "" {
+			t.Errorf("Entry is missing the request ID")
+		}
+		for _, h := range e.Request.Headers {
+			if strings.EqualFold(h.Name, authHeader) && h.Value != "********" {
+				t.Errorf("Entry contains the JSON web token")
+			}
+		}
+	}
+}
+
+func TestScrubSensitiveDataAzureAccount(t *testing.T) {
+	in := `{"cloudAccount":{"accountId":"sub","name":"azure"},"clientId":"app","key":"cli\"ent-se\"cret","monitorFlowLogs":true,"tenantId":"tenant"}`
+	got := scrubSensitiveData([]byte(in))
+
+	for _, secret := range []string{"ent-se", "cret"} {
+		if strings.Contains(got, secret) {
+			t.Errorf("Scrubbed data %s still contains %q", got, secret)
+		}
+	}
+	for _, want := range []string{`"key":"********"`, `"clientId":"app"`, `"tenantId":"tenant"`, `"name":"azure"`} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Scrubbed data %s is missing %s", got, want)
+		}
+	}
+}
+
+func TestScrubSensitiveDataIntegration(t *testing.T) {
+	in := `{"name":"ops","integrationType":"pager_duty","integrationConfig":{"externalId":"ext","secretKey":"sk","privateKey":"pk","apiKey":"ak","apiToken":"at","authToken":"tok","integrationKey":"ik","tables":[{"PASSWORD":"pw"}]}}`
+	got := scrubSensitiveData([]byte(in))
+
+	for _, secret := range []string{`"ext"`, `"sk"`, `"pk"`, `"ak"`, `"at"`, `"tok"`, `"ik"`, `"pw"`} {
+		if strings.Contains(got, secret) {
+			t.Errorf("Scrubbed data %s still contains %s", got, secret)
+		}
+	}
+	if !strings.Contains(got, `"integrationType":"pager_duty"`) {
+		t.Errorf("Scrubbed data %s is missing the integration type", got)
+	}
+}
+
+func TestScrubSensitiveDataTags(t *testing.T) {
+	in := `{"name":"rule","target":{"tags":[{"key":"env","values":["prod"]}]}}`
+	if got := scrubSensitiveData([]byte(in)); got != in {
+		t.Errorf("Scrubbed data is %s, expected it unchanged", got)
+	}
+}
+
+func TestScrubSensitiveDataNotJson(t *testing.T) {
+	in := `key=1 {"SecretKey": "a\"b", "apiKey":"c"`
+	got := scrubSensitiveData([]byte(in))
+
+	if got != `key=1 {"SecretKey": "********", "apiKey":"********"` {
+		t.Errorf("Scrubbed data is %s", got)
+	}
+}
@@ -0,0 +1,33 @@
+package prismacloud
+
+import (
+	"crypto/tls"
+	"net/http"
+)
+
+// newBaseTransport returns the transport that actually talks to Prisma Cloud,
+// configured the same way the SDK configures its default transport.
+func newBaseTransport(skipSslCertVerification bool) *http.Transport {
+	return &http.Transport{
+		TLSClientConfig: &tls.Config{
+			InsecureSkipVerify: skipSslCertVerification,
+		},
+		Proxy: http.ProxyFromEnvironment,
+	}
+}
+
+/*
+newClientTransport returns a transport for pc.Client that hands every request
+to the given round tripper.
+
+The SDK client only accepts a concrete *http.Transport, so the round tripper
+is registered as the handler for both the http and https schemes.  This lets
+the provider layer request level behavior (throttling, retries, caching and
+so on) on top of the SDK without changing it.
+*/
+func newClientTransport(rt http.RoundTripper) *http.Transport {
+	t := &http.Transport{}
+	t.RegisterProtocol("http", rt)
+	t.RegisterProtocol("https", rt)
+	return t
+}